@@ -0,0 +1,228 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Event is an event of an attached buffer: *LinesEvent, *ChangedtickEvent
+// or *DetachEvent.
+type Event interface {
+	isEvent()
+}
+
+// LinesEvent reports that the lines in [FirstLine, LastLine) were replaced
+// with Lines. A LastLine of -1 replaces the whole buffer, as when the
+// buffer is first sent or reloaded.
+type LinesEvent struct {
+	Buf         types.Buffer
+	Changedtick int
+	FirstLine   int
+	LastLine    int
+	Lines       []string
+
+	// DeletedBytes is the size in bytes of the replaced lines.
+	// DeletedCodepoints and DeletedCodeunits are their size in UTF-32 and
+	// UTF-16 code units, only set with AttachOptions.UTFSizes.
+	DeletedBytes      int
+	DeletedCodepoints int
+	DeletedCodeunits  int
+}
+
+// ChangedtickEvent reports that b:changedtick increased without a change
+// of text, such as after undoing to the same text.
+type ChangedtickEvent struct {
+	Buf         types.Buffer
+	Changedtick int
+}
+
+// DetachEvent reports that the buffer was detached by Neovim, such as when
+// it is unloaded. No events follow.
+type DetachEvent struct {
+	Buf types.Buffer
+}
+
+func (*LinesEvent) isEvent()       {}
+func (*ChangedtickEvent) isEvent() {}
+func (*DetachEvent) isEvent()      {}
+
+// AttachOptions configures Attach.
+type AttachOptions struct {
+	// SendBuffer sends the whole buffer in a first LinesEvent.
+	SendBuffer bool
+
+	// UTFSizes sets the UTF-32 and UTF-16 sizes of LinesEvent.
+	UTFSizes bool
+}
+
+// attachLua attaches to a buffer, notifying the Go client of its events.
+// Events of an attachment are dropped once it is marked in
+// _G.__go_nvim_detached, and the attachment ends on the next change.
+const attachLua = `
+local chan, method, buf, send_buffer, utf_sizes = ...
+_G.__go_nvim_detached = _G.__go_nvim_detached or {}
+local detached = _G.__go_nvim_detached
+local function send_all(b, tick)
+  local lines = vim.api.nvim_buf_get_lines(b, 0, -1, true)
+  vim.rpcnotify(chan, method, 'lines', tick, 0, -1, lines, 0, 0, 0)
+end
+local ok = vim.api.nvim_buf_attach(buf, false, {
+  utf_sizes = utf_sizes,
+  on_lines = function(_, b, tick, first, last, new_last, bytes, cp, cu)
+    if detached[method] then
+      detached[method] = nil
+      return true
+    end
+    local lines = vim.api.nvim_buf_get_lines(b, first, new_last, true)
+    vim.rpcnotify(chan, method, 'lines', tick, first, last, lines, bytes, cp or 0, cu or 0)
+  end,
+  on_changedtick = function(_, b, tick)
+    if detached[method] then
+      detached[method] = nil
+      return true
+    end
+    vim.rpcnotify(chan, method, 'changedtick', tick)
+  end,
+  on_reload = function(_, b)
+    if not detached[method] then
+      send_all(b, vim.api.nvim_buf_get_changedtick(b))
+    end
+  end,
+  on_detach = function()
+    if detached[method] then
+      detached[method] = nil
+      return
+    end
+    vim.rpcnotify(chan, method, 'detach')
+  end,
+})
+if ok and send_buffer then
+  send_all(buf, vim.api.nvim_buf_get_changedtick(buf))
+end
+return ok
+`
+
+var attachSeq uint64
+
+// Attachment is a buffer attached with Attach.
+type Attachment struct {
+	v      *nvim.Nvim
+	buf    types.Buffer
+	method string
+
+	once sync.Once
+}
+
+// Attach calls fn with the events of buf, in order, until Neovim detaches
+// the buffer or Detach is called. fn runs like a notification handler: it
+// may make calls, but blocks the notifications that follow.
+func Attach(ctx context.Context, v *nvim.Nvim, buf types.Buffer, opts *AttachOptions, fn func(Event)) (*Attachment, error) {
+	if opts == nil {
+		opts = &AttachOptions{}
+	}
+	channel, err := v.ChannelID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Attachment{
+		v:      v,
+		buf:    buf,
+		method: fmt.Sprintf("go_nvim_buf_attach_%d", atomic.AddUint64(&attachSeq, 1)),
+	}
+	v.Handle(a.method, func(args []interface{}) {
+		if e := decodeEvent(buf, args); e != nil {
+			if _, ok := e.(*DetachEvent); ok {
+				v.Handle(a.method, nil)
+			}
+			fn(e)
+		}
+	})
+
+	var ok bool
+	if err := v.Call(ctx, "nvim_exec_lua", &ok, attachLua, []interface{}{channel, a.method, buf, opts.SendBuffer, opts.UTFSizes}); err != nil {
+		v.Handle(a.method, nil)
+		return nil, err
+	}
+	if !ok {
+		v.Handle(a.method, nil)
+		return nil, fmt.Errorf("buffer: cannot attach to %s", buf)
+	}
+
+	return a, nil
+}
+
+// decodeEvent returns the event of the arguments of a notification, or nil
+// if they are invalid.
+func decodeEvent(buf types.Buffer, args []interface{}) Event {
+	if len(args) == 0 {
+		return nil
+	}
+	kind, _ := args[0].(string)
+	ints := func(vs ...interface{}) []int {
+		n := make([]int, len(vs))
+		for i, v := range vs {
+			n[i] = toInt(v)
+		}
+		return n
+	}
+
+	switch {
+	case kind == "lines" && len(args) == 8:
+		raw, _ := args[4].([]interface{})
+		lines := make([]string, len(raw))
+		for i, l := range raw {
+			lines[i], _ = l.(string)
+		}
+		n := ints(args[1], args[2], args[3], args[5], args[6], args[7])
+		return &LinesEvent{
+			Buf:               buf,
+			Changedtick:       n[0],
+			FirstLine:         n[1],
+			LastLine:          n[2],
+			Lines:             lines,
+			DeletedBytes:      n[3],
+			DeletedCodepoints: n[4],
+			DeletedCodeunits:  n[5],
+		}
+	case kind == "changedtick" && len(args) == 2:
+		return &ChangedtickEvent{Buf: buf, Changedtick: toInt(args[1])}
+	case kind == "detach":
+		return &DetachEvent{Buf: buf}
+	}
+
+	return nil
+}
+
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+
+	return 0
+}
+
+// Detach stops the events of the buffer. fn is not called for the
+// notifications received after Detach, not even with a DetachEvent.
+func (a *Attachment) Detach(ctx context.Context) error {
+	var err error
+	a.once.Do(func() {
+		a.v.Handle(a.method, nil)
+		_, err = a.v.ExecLua(ctx, `(_G.__go_nvim_detached or {})[...] = true`, []interface{}{a.method})
+	})
+
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package buffer_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/buffer"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/types"
+)
+
+// mockAttach makes the mock accept attachments, and returns a channel of
+// their notification methods.
+func mockAttach(s *nvimtest.MockServer) <-chan string {
+	methods := make(chan string, 8)
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		code, _ := args[0].(string)
+		if !strings.Contains(code, "nvim_buf_attach") {
+			return nil, nil
+		}
+		params, _ := args[1].([]interface{})
+		method, _ := params[1].(string)
+		methods <- method
+		return true, nil
+	})
+
+	return methods
+}
+
+func TestAttach(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	methods := mockAttach(s)
+
+	events := make(chan buffer.Event, 8)
+	ctx := context.Background()
+	if _, err := buffer.Attach(ctx, v, 2, &buffer.AttachOptions{SendBuffer: true}, func(e buffer.Event) { events <- e }); err != nil {
+		t.Fatal(err)
+	}
+	method := <-methods
+
+	s.Notify(method, "lines", 5, 0, -1, []string{"a", "b"}, 0, 0, 0)
+	s.Notify(method, "changedtick", 6)
+	s.Notify(method, "lines", 7, 1, 2, []string{"c"}, 2, 0, 0)
+	s.Notify(method, "detach")
+
+	want := []buffer.Event{
+		&buffer.LinesEvent{Buf: 2, Changedtick: 5, FirstLine: 0, LastLine: -1, Lines: []string{"a", "b"}},
+		&buffer.ChangedtickEvent{Buf: 2, Changedtick: 6},
+		&buffer.LinesEvent{Buf: 2, Changedtick: 7, FirstLine: 1, LastLine: 2, Lines: []string{"c"}, DeletedBytes: 2},
+		&buffer.DetachEvent{Buf: 2},
+	}
+	for i, w := range want {
+		select {
+		case e := <-events:
+			if !reflect.DeepEqual(e, w) {
+				t.Errorf("event %d = %#v, want %#v", i, e, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d not received", i)
+		}
+	}
+
+	args := s.Calls()[len(s.Calls())-1].Args[1].([]interface{})
+	if args[0] != int64(1) || args[2] != types.Buffer(2) || args[3] != true {
+		t.Errorf("attach arguments = %v, want channel 1, buffer 2 and send_buffer", args)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	methods := mockAttach(s)
+
+	events := make(chan buffer.Event, 8)
+	ctx := context.Background()
+	a, err := buffer.Attach(ctx, v, 1, nil, func(e buffer.Event) { events <- e })
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := <-methods
+
+	if err := a.Detach(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_exec_lua", `(_G.__go_nvim_detached or {})[...] = true`, []interface{}{method})
+
+	// Notifications after Detach are dropped. Notifications are handled
+	// in order, so the first one is done once "sync" is handled.
+	synced := make(chan struct{})
+	v.Handle("sync", func([]interface{}) { close(synced) })
+	s.Notify(method, "changedtick", 2)
+	s.Notify("sync")
+	<-synced
+	select {
+	case e := <-events:
+		t.Errorf("event %#v after Detach", e)
+	default:
+	}
+}
+
+func TestAttachFails(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_exec_lua", false)
+
+	if _, err := buffer.Attach(context.Background(), v, 9, nil, func(buffer.Event) {}); err == nil {
+		t.Error("Attach succeeded, want an error")
+	}
+}
+
+func TestAttachChannelCached(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	methods := mockAttach(s)
+
+	ctx := context.Background()
+	for buf := types.Buffer(1); buf <= 3; buf++ {
+		if _, err := buffer.Attach(ctx, v, buf, nil, func(buffer.Event) {}); err != nil {
+			t.Fatal(err)
+		}
+		<-methods
+	}
+
+	n := 0
+	for _, m := range s.Methods() {
+		if m == "nvim_get_api_info" {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("nvim_get_api_info called %d times, want 1", n)
+	}
+}
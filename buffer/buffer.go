@@ -0,0 +1,148 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package buffer
+
+import (
+	"context"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Buffer is a Neovim buffer.
+//
+// Lines are zero-based and columns are zero-based byte offsets in the
+// line. Negative line indexes count from the end, -1 being past the last
+// line, as in nvim_buf_get_lines.
+type Buffer struct {
+	v   *nvim.Nvim
+	buf types.Buffer
+}
+
+// Range is a range of text from a start position, inclusive, to an end
+// position, exclusive.
+type Range struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+}
+
+// New returns the Buffer buf of v. Buffer 0 is the current buffer at the
+// time of each call.
+func New(v *nvim.Nvim, buf types.Buffer) *Buffer {
+	return &Buffer{v: v, buf: buf}
+}
+
+// Current returns the current buffer.
+func Current(ctx context.Context, v *nvim.Nvim) (*Buffer, error) {
+	buf, err := v.GetCurrentBuf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(v, buf), nil
+}
+
+// Handle returns the handle of the buffer.
+func (b *Buffer) Handle() types.Buffer {
+	return b.buf
+}
+
+func (b *Buffer) String() string {
+	return b.buf.String()
+}
+
+// IsValid reports whether the buffer exists. A buffer stops being valid
+// once wiped out.
+func (b *Buffer) IsValid(ctx context.Context) (bool, error) {
+	return b.v.BufIsValid(ctx, b.buf)
+}
+
+// IsLoaded reports whether the buffer is valid and loaded.
+func (b *Buffer) IsLoaded(ctx context.Context) (bool, error) {
+	return b.v.BufIsLoaded(ctx, b.buf)
+}
+
+// Lines returns the lines in [start, end). Out of bounds indexes are an
+// error.
+func (b *Buffer) Lines(ctx context.Context, start, end int) ([]string, error) {
+	return b.v.BufGetLines(ctx, b.buf, start, end, true)
+}
+
+// SetLines replaces the lines in [start, end) with lines. Out of bounds
+// indexes are an error, and an empty lines deletes the range.
+func (b *Buffer) SetLines(ctx context.Context, start, end int, lines []string) error {
+	if lines == nil {
+		lines = []string{}
+	}
+
+	return b.v.BufSetLines(ctx, b.buf, start, end, true, lines)
+}
+
+// Text returns the text in r, as lines.
+func (b *Buffer) Text(ctx context.Context, r Range) ([]string, error) {
+	return b.v.BufGetText(ctx, b.buf, r.StartLine, r.StartCol, r.EndLine, r.EndCol, map[string]interface{}{})
+}
+
+// SetText replaces the text in r with text, as lines. An empty r inserts
+// text, and an empty text deletes r.
+func (b *Buffer) SetText(ctx context.Context, r Range, text []string) error {
+	if text == nil {
+		text = []string{}
+	}
+
+	return b.v.BufSetText(ctx, b.buf, r.StartLine, r.StartCol, r.EndLine, r.EndCol, text)
+}
+
+// LineCount returns the number of lines, or 0 if the buffer is not loaded.
+func (b *Buffer) LineCount(ctx context.Context) (int, error) {
+	return b.v.BufLineCount(ctx, b.buf)
+}
+
+// ByteOffset returns the byte offset of line, counting the line endings of
+// the buffer. The line past the last one is the size of the buffer.
+func (b *Buffer) ByteOffset(ctx context.Context, line int) (int, error) {
+	return b.v.BufGetOffset(ctx, b.buf, line)
+}
+
+// Changedtick returns b:changedtick, which increases on every change.
+func (b *Buffer) Changedtick(ctx context.Context) (int, error) {
+	return b.v.BufGetChangedtick(ctx, b.buf)
+}
+
+// Name returns the full file name of the buffer.
+func (b *Buffer) Name(ctx context.Context) (string, error) {
+	return b.v.BufGetName(ctx, b.buf)
+}
+
+// SetName sets the file name of the buffer.
+func (b *Buffer) SetName(ctx context.Context, name string) error {
+	return b.v.BufSetName(ctx, b.buf, name)
+}
+
+// Option returns the value of the buffer-local option name.
+func (b *Buffer) Option(ctx context.Context, name string) (interface{}, error) {
+	return b.v.GetOptionValue(ctx, name, map[string]interface{}{"buf": b.buf})
+}
+
+// SetOption sets the buffer-local option name to value.
+func (b *Buffer) SetOption(ctx context.Context, name string, value interface{}) error {
+	return b.v.SetOptionValue(ctx, name, value, map[string]interface{}{"buf": b.buf})
+}
+
+// Var stores the value of the buffer variable name in the value pointed to
+// by result.
+func (b *Buffer) Var(ctx context.Context, name string, result interface{}) error {
+	return b.v.Call(ctx, "nvim_buf_get_var", result, b.buf, name)
+}
+
+// SetVar sets the buffer variable name to value.
+func (b *Buffer) SetVar(ctx context.Context, name string, value interface{}) error {
+	return b.v.BufSetVar(ctx, b.buf, name, value)
+}
+
+// Delete deletes the buffer, as :bwipeout, or :bunload if unload is true.
+// Changes are discarded if force is true, and are otherwise an error.
+func (b *Buffer) Delete(ctx context.Context, force, unload bool) error {
+	return b.v.BufDelete(ctx, b.buf, map[string]interface{}{"force": force, "unload": unload})
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package buffer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-nvim/pkg/buffer"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/types"
+)
+
+func TestDeleteLines(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_buf_set_lines", nil)
+	s.Return("nvim_buf_set_text", nil)
+	b := buffer.New(v, 3)
+	ctx := context.Background()
+
+	if err := b.SetLines(ctx, 1, 4, nil); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_buf_set_lines", types.Buffer(3), 1, 4, true, []interface{}{})
+
+	if err := b.SetText(ctx, buffer.Range{StartLine: 0, StartCol: 1, EndLine: 0, EndCol: 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_buf_set_text", types.Buffer(3), 0, 1, 0, 2, []interface{}{})
+}
@@ -0,0 +1,258 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package buffer works with the contents of Neovim buffers.
+package buffer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/go-nvim/pkg/internal/xxhash"
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Hasher maintains the hash of the lines of a buffer.
+//
+// The hash of each line is kept so that replacing lines, as reported by
+// the nvim_buf_lines_event notifications of an attached buffer, does not
+// rehash the whole buffer.
+type Hasher struct {
+	lines []uint64
+}
+
+// NewHasher returns a Hasher of lines.
+func NewHasher(lines []string) *Hasher {
+	h := &Hasher{lines: make([]uint64, len(lines))}
+	for i, l := range lines {
+		h.lines[i] = xxhash.Sum64([]byte(l))
+	}
+
+	return h
+}
+
+// Update replaces the lines in [first, last) with lines. A negative last
+// is the end of the buffer, as in nvim_buf_lines_event.
+func (h *Hasher) Update(first, last int, lines []string) {
+	if last < 0 || last > len(h.lines) {
+		last = len(h.lines)
+	}
+	if first > last {
+		first = last
+	}
+
+	sums := make([]uint64, len(lines))
+	for i, l := range lines {
+		sums[i] = xxhash.Sum64([]byte(l))
+	}
+	h.lines = append(h.lines[:first:first], append(sums, h.lines[last:]...)...)
+}
+
+// Len returns the number of lines.
+func (h *Hasher) Len() int {
+	return len(h.lines)
+}
+
+// Sum returns the hash of the lines.
+func (h *Hasher) Sum() uint64 {
+	b := make([]byte, 8*len(h.lines))
+	for i, s := range h.lines {
+		binary.LittleEndian.PutUint64(b[8*i:], s)
+	}
+
+	return xxhash.Sum64(b)
+}
+
+// HashLines returns the hash of lines, as returned by Hasher.Sum.
+func HashLines(lines []string) uint64 {
+	return NewHasher(lines).Sum()
+}
+
+// Hash returns the hash of the lines of buf.
+func Hash(ctx context.Context, v *nvim.Nvim, buf types.Buffer) (uint64, error) {
+	lines, err := v.BufGetLines(ctx, buf, 0, -1, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return HashLines(lines), nil
+}
+
+// HashFile returns the hash of the lines of the file path, as Hash of a
+// buffer editing it would return.
+//
+// Lines are split at "\n". A final newline does not start a line, and a
+// "\r" ending every line is removed, as when 'fileformat' is "dos".
+func HashFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return HashLines(splitLines(data)), nil
+}
+
+func splitLines(data []byte) []string {
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	parts := bytes.Split(data, []byte("\n"))
+
+	dos := len(data) > 0
+	for _, p := range parts {
+		if !bytes.HasSuffix(p, []byte("\r")) {
+			dos = false
+			break
+		}
+	}
+
+	lines := make([]string, len(parts))
+	for i, p := range parts {
+		if dos {
+			p = p[:len(p)-1]
+		}
+		lines[i] = string(p)
+	}
+
+	return lines
+}
+
+// DiskWatch reports whether a buffer differs from its file on disk.
+type DiskWatch struct {
+	v   *nvim.Nvim
+	buf types.Buffer
+	fn  func(differs bool)
+	r   *autocmd.BufferRegistrar
+	a   *Attachment
+
+	mu      sync.Mutex
+	lines   *Hasher // nil until the buffer is received
+	file    uint64  // hash of the file
+	noFile  bool    // whether the file does not exist
+	known   bool
+	differs bool
+}
+
+// emptyLine is the hash of an empty line.
+var emptyLine = xxhash.Sum64(nil)
+
+// WatchDisk calls fn with whether buf differs from its file on disk, first
+// once the buffer is received, and then whenever that changes after the
+// buffer is edited, read or written.
+//
+// The buffer is attached and hashed incrementally as it changes, and the
+// file is only hashed again when the buffer is read or written, or the file
+// changes outside of Neovim. A buffer whose file does not exist differs
+// from it unless it is empty. Buffer 0 is the current buffer at the time of
+// the call.
+func WatchDisk(ctx context.Context, v *nvim.Nvim, buf types.Buffer, fn func(differs bool)) (*DiskWatch, error) {
+	r, err := autocmd.ForBuffer(ctx, v, int(buf))
+	if err != nil {
+		return nil, err
+	}
+	w := &DiskWatch{v: v, buf: types.Buffer(r.Buffer()), fn: fn, r: r}
+
+	_, err = w.r.Register(autocmd.BufReadPost, autocmd.BufWritePost, autocmd.FileChangedShellPost, autocmd.BufFilePost).
+		Desc("hash the file on disk").
+		Callback(func(*autocmd.Args) { w.hashFile(context.Background()) }).
+		Create(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.hashFile(ctx); err != nil {
+		w.r.Close(ctx)
+		return nil, err
+	}
+	w.a, err = Attach(ctx, v, w.buf, &AttachOptions{SendBuffer: true}, w.update)
+	if err != nil {
+		w.r.Close(ctx)
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Differs returns whether the buffer differed from its file when last
+// checked.
+func (w *DiskWatch) Differs() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.differs
+}
+
+// hashFile hashes the file of the buffer and reports whether the buffer
+// differs from it.
+func (w *DiskWatch) hashFile(ctx context.Context) error {
+	name, err := w.v.BufGetName(ctx, w.buf)
+	if err != nil {
+		return err
+	}
+	file, err := HashFile(name)
+	noFile := errors.Is(err, os.ErrNotExist) || name == ""
+	if err != nil && !noFile {
+		return err
+	}
+
+	w.mu.Lock()
+	w.file, w.noFile = file, noFile
+	w.mu.Unlock()
+	w.check()
+
+	return nil
+}
+
+// update applies an event of the attached buffer to its hash.
+func (w *DiskWatch) update(e Event) {
+	le, ok := e.(*LinesEvent)
+	if !ok {
+		return
+	}
+
+	w.mu.Lock()
+	switch {
+	case le.LastLine < 0:
+		w.lines = NewHasher(le.Lines)
+	case w.lines != nil:
+		w.lines.Update(le.FirstLine, le.LastLine, le.Lines)
+	}
+	w.mu.Unlock()
+	w.check()
+}
+
+// check compares the hashes of the buffer and of its file, and calls fn if
+// the result changed.
+func (w *DiskWatch) check() {
+	w.mu.Lock()
+	if w.lines == nil {
+		w.mu.Unlock()
+		return
+	}
+	var differs bool
+	if w.noFile {
+		differs = w.lines.Len() > 1 || w.lines.Len() == 1 && w.lines.lines[0] != emptyLine
+	} else {
+		differs = w.lines.Sum() != w.file
+	}
+	changed := !w.known || w.differs != differs
+	w.known, w.differs = true, differs
+	w.mu.Unlock()
+
+	if changed {
+		w.fn(differs)
+	}
+}
+
+// Close stops watching the buffer.
+func (w *DiskWatch) Close(ctx context.Context) error {
+	err := w.a.Detach(ctx)
+	if e := w.r.Close(ctx); e != nil && err == nil {
+		err = e
+	}
+
+	return err
+}
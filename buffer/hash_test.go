@@ -0,0 +1,100 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package buffer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/buffer"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+)
+
+func TestWatchDisk(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path, []byte("a\nb\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	s.Return("nvim_buf_get_name", path)
+
+	var mu sync.Mutex
+	var attach, autocmd string
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		code, _ := args[0].(string)
+		params, _ := args[1].([]interface{})
+		if len(params) < 2 {
+			return nil, nil
+		}
+		method, _ := params[1].(string)
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.Contains(code, "nvim_buf_attach"):
+			attach = method
+			return true, nil
+		case strings.Contains(code, "nvim_create_autocmd"):
+			if autocmd == "" {
+				autocmd = method
+			}
+			return 1, nil
+		}
+		return nil, nil
+	})
+
+	got := make(chan bool, 8)
+	ctx := context.Background()
+	w, err := buffer.WatchDisk(ctx, v, 2, func(differs bool) { got <- differs })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close(ctx)
+	expect := func(want bool) {
+		t.Helper()
+		select {
+		case differs := <-got:
+			if differs != want {
+				t.Fatalf("differs = %v, want %v", differs, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no report")
+		}
+	}
+
+	s.Notify(attach, "lines", 1, 0, -1, []string{"a", "b"}, 0, 0, 0)
+	expect(false)
+	s.Notify(attach, "lines", 2, 1, 2, []string{"c"}, 2, 0, 0)
+	expect(true)
+	s.Notify(attach, "lines", 3, 1, 2, []string{"b"}, 2, 0, 0)
+	expect(false)
+
+	// Edits do not read the file again.
+	if n := count(s, "nvim_buf_get_name"); n != 1 {
+		t.Errorf("file looked up %d times, want once", n)
+	}
+
+	// The file is hashed again after it is written.
+	if err := os.WriteFile(path, []byte("a\nb\nc\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	s.Notify(autocmd, map[string]interface{}{"id": 1, "event": "BufWritePost", "buf": 2}, []interface{}{})
+	expect(true)
+}
+
+// count returns the number of calls of method.
+func count(s *nvimtest.MockServer, method string) int {
+	n := 0
+	for _, m := range s.Methods() {
+		if m == method {
+			n++
+		}
+	}
+
+	return n
+}
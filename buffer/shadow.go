@@ -0,0 +1,212 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package buffer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// maxChanges is the number of changes a Shadow keeps for ChangedSince.
+const maxChanges = 256
+
+// Shadow is a copy of the lines of a buffer kept in sync with Neovim, for
+// reading the buffer without calls. It is safe for concurrent use.
+type Shadow struct {
+	a *Attachment
+
+	mu       sync.Mutex
+	lines    []string
+	tick     int
+	changes  []change
+	dropped  bool // whether changes were dropped from changes
+	detached bool
+	ready    chan struct{} // closed once the buffer was received
+}
+
+// change is a change of lines, [first, last) being replaced with n lines.
+// A last of -1 replaces the whole buffer.
+type change struct {
+	tick        int
+	first, last int
+	n           int
+}
+
+// Snapshot is the content of a buffer at a changedtick.
+type Snapshot struct {
+	Lines       []string
+	Changedtick int
+}
+
+// NewShadow returns a Shadow of buf. It returns once the lines of the
+// buffer were received.
+func NewShadow(ctx context.Context, v *nvim.Nvim, buf types.Buffer) (*Shadow, error) {
+	s := &Shadow{ready: make(chan struct{})}
+	a, err := Attach(ctx, v, buf, &AttachOptions{SendBuffer: true}, s.update)
+	if err != nil {
+		return nil, err
+	}
+	s.a = a
+
+	select {
+	case <-s.ready:
+		return s, nil
+	case <-ctx.Done():
+		a.Detach(context.Background())
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Shadow) update(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e := e.(type) {
+	case *LinesEvent:
+		first, last := e.FirstLine, e.LastLine
+		if last < 0 || last > len(s.lines) {
+			last = len(s.lines)
+		}
+		if first > last {
+			first = last
+		}
+		if e.LastLine < 0 {
+			first = 0
+		}
+		s.lines = append(s.lines[:first:first], append(e.Lines, s.lines[last:]...)...)
+		s.tick = e.Changedtick
+
+		c := change{tick: e.Changedtick, first: first, last: last, n: len(e.Lines)}
+		if e.LastLine < 0 {
+			c.last = -1
+		}
+		s.changes = append(s.changes, c)
+		if len(s.changes) > maxChanges {
+			s.dropped = true
+			s.changes = append(s.changes[:0:0], s.changes[len(s.changes)-maxChanges:]...)
+		}
+		if c.last == -1 {
+			select {
+			case <-s.ready:
+			default:
+				close(s.ready)
+			}
+		}
+
+	case *ChangedtickEvent:
+		s.tick = e.Changedtick
+
+	case *DetachEvent:
+		s.detached = true
+	}
+}
+
+// Snapshot returns the lines of the buffer. The Lines are not modified by
+// later changes.
+func (s *Shadow) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Snapshot{Lines: append([]string(nil), s.lines...), Changedtick: s.tick}
+}
+
+// Line returns the line i, and false if there is none.
+func (s *Shadow) Line(i int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i < 0 || i >= len(s.lines) {
+		return "", false
+	}
+
+	return s.lines[i], true
+}
+
+// Len returns the number of lines.
+func (s *Shadow) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.lines)
+}
+
+// Changedtick returns the changedtick of the copy.
+func (s *Shadow) Changedtick() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tick
+}
+
+// Detached reports whether Neovim detached the buffer, such as when it was
+// unloaded. The copy is no longer updated.
+func (s *Shadow) Detached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.detached
+}
+
+// ChangedSince returns the lines [first, last) covering all the changes
+// made after changedtick tick, in the current lines. It returns ok false if
+// the changes since tick are no longer known, in which case the whole
+// buffer should be considered changed. An empty range means no lines
+// changed, or only lines were deleted at first.
+func (s *Shadow) ChangedSince(tick int) (first, last int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tick >= s.tick {
+		return 0, 0, true
+	}
+	i := len(s.changes)
+	for i > 0 && s.changes[i-1].tick > tick {
+		i--
+	}
+	if i == 0 && s.dropped {
+		// The change following tick may have been dropped.
+		return 0, len(s.lines), false
+	}
+
+	found := false
+	for _, c := range s.changes[i:] {
+		if c.last == -1 {
+			return 0, len(s.lines), true
+		}
+		if found {
+			// Map the region through the change, then add it.
+			first, last = shift(first, c), shift(last, c)
+			if c.first < first {
+				first = c.first
+			}
+			if c.first+c.n > last {
+				last = c.first + c.n
+			}
+			continue
+		}
+		first, last, found = c.first, c.first+c.n, true
+	}
+
+	return first, last, true
+}
+
+// shift returns the line p after the change c.
+func shift(p int, c change) int {
+	switch {
+	case p <= c.first:
+		return p
+	case p >= c.last:
+		return p + c.n - (c.last - c.first)
+	}
+
+	return c.first + c.n
+}
+
+// Close stops updating the copy.
+func (s *Shadow) Close(ctx context.Context) error {
+	return s.a.Detach(ctx)
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package channel inspects the channels of Neovim and identifies clients.
+package channel
+
+import (
+	"context"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Stream is the kind of stream of a channel.
+type Stream string
+
+// List of streams.
+const (
+	StreamStdio  Stream = "stdio"
+	StreamStderr Stream = "stderr"
+	StreamSocket Stream = "socket"
+	StreamJob    Stream = "job"
+)
+
+// Mode is how the data of a channel is interpreted.
+type Mode string
+
+// List of modes.
+const (
+	ModeBytes    Mode = "bytes"
+	ModeTerminal Mode = "terminal"
+	ModeRPC      Mode = "rpc"
+)
+
+// ClientType is the type of a client, as set with nvim_set_client_info.
+type ClientType string
+
+// List of client types.
+const (
+	ClientRemote     ClientType = "remote"
+	ClientMsgpackRPC ClientType = "msgpack-rpc"
+	ClientUI         ClientType = "ui"
+	ClientEmbedder   ClientType = "embedder"
+	ClientHost       ClientType = "host"
+	ClientPlugin     ClientType = "plugin"
+)
+
+// Info describes a channel, as returned by nvim_get_chan_info.
+type Info struct {
+	ID     int          `msgpack:"id"`
+	Argv   []string     `msgpack:"argv"` // command of a job
+	Stream Stream       `msgpack:"stream"`
+	Mode   Mode         `msgpack:"mode"`
+	Pty    string       `msgpack:"pty"`    // pty of a job, if any
+	Buffer types.Buffer `msgpack:"buffer"` // buffer of a terminal
+	Client *ClientInfo  `msgpack:"client"` // nil unless set by the client
+}
+
+// ClientInfo identifies the client of an RPC channel.
+type ClientInfo struct {
+	Name       string                `msgpack:"name"`
+	Version    Version               `msgpack:"version"`
+	Type       ClientType            `msgpack:"type"`
+	Methods    map[string]MethodInfo `msgpack:"methods"`
+	Attributes map[string]string     `msgpack:"attributes"`
+}
+
+// Version is the version of a client.
+type Version struct {
+	Major      int    `msgpack:"major,omitempty"`
+	Minor      int    `msgpack:"minor,omitempty"`
+	Patch      int    `msgpack:"patch,omitempty"`
+	Prerelease string `msgpack:"prerelease,omitempty"`
+	Commit     string `msgpack:"commit,omitempty"`
+}
+
+// MethodInfo describes a method the client serves.
+type MethodInfo struct {
+	Async bool `msgpack:"async,omitempty"`
+
+	// NArgs is the number of arguments, as an int, or the minimum and
+	// maximum number as a [2]int. It is unknown if nil.
+	NArgs interface{} `msgpack:"nargs,omitempty"`
+}
+
+// Get returns the channel id, or the channel of v if id is 0.
+func Get(ctx context.Context, v *nvim.Nvim, id int) (*Info, error) {
+	var info Info
+	if err := v.Call(ctx, "nvim_get_chan_info", &info, id); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// List returns all the open channels.
+func List(ctx context.Context, v *nvim.Nvim) ([]*Info, error) {
+	var infos []*Info
+	if err := v.Call(ctx, "nvim_list_chans", &infos); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// Find returns the channels whose client has the given name.
+func Find(ctx context.Context, v *nvim.Nvim, name string) ([]*Info, error) {
+	infos, err := List(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*Info
+	for _, info := range infos {
+		if info.Client != nil && info.Client.Name == name {
+			found = append(found, info)
+		}
+	}
+
+	return found, nil
+}
+
+// Identify identifies v to Neovim as client c, with nvim_set_client_info.
+// A later call replaces the identity, so it should include all the fields.
+func Identify(ctx context.Context, v *nvim.Nvim, c *ClientInfo) error {
+	typ := c.Type
+	if typ == "" {
+		typ = ClientRemote
+	}
+	methods := c.Methods
+	if methods == nil {
+		methods = map[string]MethodInfo{}
+	}
+	attrs := c.Attributes
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+
+	return v.Call(ctx, "nvim_set_client_info", nil, c.Name, c.Version, typ, methods, attrs)
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package fsm implements finite state machines keyed by buffer, for
+// coordinating features that act on the same buffer, such as formatting,
+// linting and saving.
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// State is a state of a buffer.
+type State string
+
+// Signal triggers a transition.
+type Signal string
+
+// Any matches any state in Machine.Allow.
+const Any State = "*"
+
+// ErrNoTransition is returned by Fire when the signal has no transition
+// from the state of the buffer.
+var ErrNoTransition = errors.New("fsm: no transition")
+
+// Transition is a change of state of a buffer.
+type Transition struct {
+	Buffer   int
+	From, To State
+	Signal   Signal
+	Time     time.Time
+}
+
+func (t Transition) String() string {
+	return fmt.Sprintf("buffer %d: %s -(%s)-> %s", t.Buffer, t.From, t.Signal, t.To)
+}
+
+// historySize is the number of transitions kept for Dump.
+const historySize = 32
+
+// Machine is a state machine whose state is kept per buffer. Buffers start
+// in the initial state of the Machine. It is safe for concurrent use.
+type Machine struct {
+	initial State
+
+	mu          sync.Mutex
+	transitions map[State]map[Signal]State
+	states      map[int]State
+	hooks       []func(Transition)
+	history     []Transition
+}
+
+// New returns a new Machine whose buffers start in initial.
+func New(initial State) *Machine {
+	return &Machine{
+		initial:     initial,
+		transitions: make(map[State]map[Signal]State),
+		states:      make(map[int]State),
+	}
+}
+
+// Allow adds the transition from from to to on sig. A from of Any allows
+// the transition from any state without a transition of its own on sig.
+func (m *Machine) Allow(from State, sig Signal, to State) *Machine {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.transitions[from]
+	if !ok {
+		t = make(map[Signal]State)
+		m.transitions[from] = t
+	}
+	t[sig] = to
+
+	return m
+}
+
+// OnTransition registers fn to be called after each transition, in the
+// order registered. Hooks are called without locks held, and may fire
+// signals.
+func (m *Machine) OnTransition(fn func(Transition)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, fn)
+}
+
+// State returns the state of buf.
+func (m *Machine) State(buf int) State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state(buf)
+}
+
+func (m *Machine) state(buf int) State {
+	if s, ok := m.states[buf]; ok {
+		return s
+	}
+
+	return m.initial
+}
+
+// Fire sends sig to buf and returns the resulting transition. It returns
+// an error wrapping ErrNoTransition if sig has no transition from the
+// state of buf, leaving the state unchanged.
+func (m *Machine) Fire(buf int, sig Signal) (Transition, error) {
+	m.mu.Lock()
+	from := m.state(buf)
+	to, ok := m.transitions[from][sig]
+	if !ok {
+		to, ok = m.transitions[Any][sig]
+	}
+	if !ok {
+		m.mu.Unlock()
+		return Transition{}, fmt.Errorf("%w on %s from %s in buffer %d", ErrNoTransition, sig, from, buf)
+	}
+
+	t := Transition{Buffer: buf, From: from, To: to, Signal: sig, Time: time.Now()}
+	m.states[buf] = to
+	m.history = append(m.history, t)
+	if len(m.history) > historySize {
+		m.history = m.history[len(m.history)-historySize:]
+	}
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(t)
+	}
+
+	return t, nil
+}
+
+// Forget resets buf to the initial state.
+func (m *Machine) Forget(buf int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.states, buf)
+}
+
+// Drive fires sig in the buffer of each occurrence of event, subscribed
+// with d. Signals without a transition are ignored.
+func (m *Machine) Drive(ctx context.Context, d *autocmd.Dispatcher, event autocmd.Event, sig Signal) (*autocmd.Subscription, error) {
+	return d.Subscribe(ctx, event, "", func(a *autocmd.Args) {
+		m.Fire(a.Buf, sig)
+	}, autocmd.Name("fsm:"+string(sig)))
+}
+
+// ForgetWiped resets the buffers wiped out, subscribing to BufWipeout with
+// d, so that the Machine does not grow with the buffers ever opened.
+func (m *Machine) ForgetWiped(ctx context.Context, d *autocmd.Dispatcher) (*autocmd.Subscription, error) {
+	return d.Subscribe(ctx, autocmd.BufWipeout, "", func(a *autocmd.Args) {
+		m.Forget(a.Buf)
+	})
+}
+
+// Dump returns a description of the states of the buffers and of the
+// recent transitions, for debugging.
+func (m *Machine) Dump() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bufs := make([]int, 0, len(m.states))
+	for buf := range m.states {
+		bufs = append(bufs, buf)
+	}
+	sort.Ints(bufs)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "initial state: %s\n", m.initial)
+	for _, buf := range bufs {
+		fmt.Fprintf(&b, "buffer %d: %s\n", buf, m.states[buf])
+	}
+	if len(m.history) > 0 {
+		b.WriteString("recent transitions:\n")
+	}
+	for _, t := range m.history {
+		fmt.Fprintf(&b, "  %s %s\n", t.Time.Format("15:04:05.000"), t)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,171 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package fsm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/fsm"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+func newMachine() *fsm.Machine {
+	return fsm.New("idle").
+		Allow("idle", "edit", "dirty").
+		Allow("dirty", "format", "formatting").
+		Allow("formatting", "done", "dirty").
+		Allow("dirty", "save", "idle").
+		Allow(fsm.Any, "reset", "idle").
+		Allow("formatting", "reset", "formatting")
+}
+
+func TestFire(t *testing.T) {
+	m := newMachine()
+
+	for _, step := range []struct {
+		sig  fsm.Signal
+		want fsm.State
+	}{
+		{"edit", "dirty"},
+		{"format", "formatting"},
+		{"reset", "formatting"}, // its own transition wins over Any
+		{"done", "dirty"},
+		{"reset", "idle"},
+	} {
+		tr, err := m.Fire(1, step.sig)
+		if err != nil {
+			t.Fatalf("Fire(%s): %v", step.sig, err)
+		}
+		if tr.To != step.want || tr.Buffer != 1 || tr.Signal != step.sig {
+			t.Errorf("Fire(%s) = %s, want a transition to %s", step.sig, tr, step.want)
+		}
+	}
+
+	_, err := m.Fire(1, "save")
+	if !errors.Is(err, fsm.ErrNoTransition) {
+		t.Errorf("Fire(save) from idle = %v, want %v", err, fsm.ErrNoTransition)
+	}
+	if s := m.State(1); s != "idle" {
+		t.Errorf("state %s after a failed Fire, want idle", s)
+	}
+	if s := m.State(2); s != "idle" {
+		t.Errorf("state of a new buffer %s, want idle", s)
+	}
+
+	m.Fire(2, "edit")
+	m.Forget(2)
+	if s := m.State(2); s != "idle" {
+		t.Errorf("state %s after Forget, want idle", s)
+	}
+}
+
+func TestOnTransition(t *testing.T) {
+	m := newMachine()
+	var got []string
+	m.OnTransition(func(tr fsm.Transition) {
+		got = append(got, "a:"+string(tr.To))
+		if tr.To == "formatting" {
+			// Hooks may fire signals.
+			if _, err := m.Fire(tr.Buffer, "done"); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	m.OnTransition(func(tr fsm.Transition) { got = append(got, "b:"+string(tr.To)) })
+
+	m.Fire(1, "edit")
+	m.Fire(1, "format")
+	want := "a:dirty b:dirty a:formatting a:dirty b:dirty b:formatting"
+	if s := strings.Join(got, " "); s != want {
+		t.Errorf("hooks called %s, want %s", s, want)
+	}
+	if s := m.State(1); s != "dirty" {
+		t.Errorf("state %s, want dirty", s)
+	}
+}
+
+func TestDump(t *testing.T) {
+	m := newMachine()
+	m.Fire(3, "edit")
+	for i := 0; i < 40; i++ {
+		m.Fire(1, "edit")
+		m.Fire(1, "save")
+	}
+
+	d := m.Dump()
+	for _, want := range []string{"initial state: idle\n", "buffer 1: idle\nbuffer 3: dirty\n", "buffer 1: dirty -(save)-> idle"} {
+		if !strings.Contains(d, want) {
+			t.Errorf("Dump() does not contain %q:\n%s", want, d)
+		}
+	}
+	if n := strings.Count(d, "->"); n != 32 {
+		t.Errorf("Dump() has %d transitions, want the last 32", n)
+	}
+	if strings.Contains(d, "buffer 3: idle -(edit)") {
+		t.Errorf("Dump() kept an old transition:\n%s", d)
+	}
+}
+
+func TestDrive(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	var mu sync.Mutex
+	methods := make(map[string]string) // event to notification method
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		params := args[1].([]interface{})
+		mu.Lock()
+		defer mu.Unlock()
+		methods[fmt.Sprint(params[2].([]interface{})[0])] = params[1].(string)
+		return len(methods), nil
+	})
+	ctx := context.Background()
+	d := autocmd.NewDispatcher(v)
+	m := newMachine()
+	changes := make(chan fsm.Transition, 4)
+	m.OnTransition(func(tr fsm.Transition) { changes <- tr })
+
+	if _, err := m.Drive(ctx, d, autocmd.TextChanged, "edit"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ForgetWiped(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	fire := func(e autocmd.Event, buf int) {
+		mu.Lock()
+		method := methods[string(e)]
+		mu.Unlock()
+		s.Notify(method, map[string]interface{}{"event": string(e), "buf": buf}, []interface{}{})
+	}
+
+	fire(autocmd.TextChanged, 4)
+	select {
+	case tr := <-changes:
+		if tr.Buffer != 4 || tr.To != "dirty" {
+			t.Errorf("got %s, want buffer 4 dirty", tr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the transition")
+	}
+	fire(autocmd.TextChanged, 4) // no transition from dirty
+	fire(autocmd.BufWipeout, 4)
+
+	// The notifications are handled in order: once the buffer is
+	// forgotten, the ignored signal has been handled too.
+	deadline := time.Now().Add(5 * time.Second)
+	for m.State(4) != "idle" {
+		if time.Now().After(deadline) {
+			t.Fatal("buffer not forgotten")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(changes) != 0 {
+		t.Errorf("unexpected transition %s", <-changes)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command apigen generates Go bindings of the Neovim API from the metadata
+// printed by nvim --api-info.
+//
+// Usage:
+//
+//	apigen [-in api_info.mpack] [-out api.go] [-package nvim]
+//
+// Without -in, apigen runs nvim --api-info.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// APIInfo is the API metadata of Neovim.
+type APIInfo struct {
+	Version   Version            `msgpack:"version"`
+	Functions []Function         `msgpack:"functions"`
+	Types     map[string]ExtType `msgpack:"types"`
+}
+
+// Version is the version of Neovim and of its API.
+type Version struct {
+	Major    int `msgpack:"major"`
+	Minor    int `msgpack:"minor"`
+	Patch    int `msgpack:"patch"`
+	APILevel int `msgpack:"api_level"`
+}
+
+// Function is an API function.
+type Function struct {
+	Name            string      `msgpack:"name"`
+	Parameters      [][2]string `msgpack:"parameters"` // type and name
+	ReturnType      string      `msgpack:"return_type"`
+	Method          bool        `msgpack:"method"`
+	Since           int         `msgpack:"since"`
+	DeprecatedSince int         `msgpack:"deprecated_since"`
+}
+
+// ExtType is a type encoded as a msgpack extension.
+type ExtType struct {
+	ID     int    `msgpack:"id"`
+	Prefix string `msgpack:"prefix"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("apigen: ")
+
+	in := flag.String("in", "", "read the API metadata from `file` instead of nvim --api-info")
+	out := flag.String("out", "api.go", "write the bindings to `file`")
+	pkg := flag.String("package", "nvim", "package `name` of the bindings")
+	flag.Parse()
+
+	info, err := load(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(info, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load returns the API metadata read from file, or printed by nvim
+// --api-info if file is empty.
+func load(file string) (*APIInfo, error) {
+	var data []byte
+	var err error
+	if file == "" {
+		data, err = exec.Command("nvim", "--api-info").Output()
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var info APIInfo
+	if err := msgpack.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("decode API metadata: %w", err)
+	}
+
+	return &info, nil
+}
+
+// generate returns the formatted source of the bindings of info.
+func generate(info *APIInfo, pkg string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by apigen from the API metadata of Neovim %d.%d.%d (API level %d). DO NOT EDIT.\n\n",
+		info.Version.Major, info.Version.Minor, info.Version.Patch, info.Version.APILevel)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\t\"context\"\n\n\t\"github.com/go-nvim/pkg/types\"\n)\n")
+
+	fns := append([]Function(nil), info.Functions...)
+	sort.Slice(fns, func(i, j int) bool { return fns[i].Name < fns[j].Name })
+
+	var skipped []string
+	for _, fn := range fns {
+		if !strings.HasPrefix(fn.Name, "nvim_") {
+			continue
+		}
+		if err := writeFunction(&b, info.Types, fn); err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", fn.Name, err))
+		}
+	}
+	for _, fn := range fns {
+		if !strings.HasPrefix(fn.Name, "nvim_") {
+			continue
+		}
+		// Skipped functions were reported above.
+		writeBatchFunction(&b, info.Types, fn)
+	}
+	for _, s := range skipped {
+		log.Printf("skip %s", s)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format bindings: %w", err)
+	}
+
+	return src, nil
+}
+
+// writeFunction writes the method binding fn.
+func writeFunction(b *bytes.Buffer, ext map[string]ExtType, fn Function) error {
+	ret, err := goType(ext, fn.ReturnType)
+	if err != nil {
+		return err
+	}
+
+	var params, args []string
+	for _, p := range fn.Parameters {
+		typ, err := goType(ext, p[0])
+		if err != nil {
+			return err
+		}
+		name := paramName(p[1])
+		params = append(params, name+" "+typ)
+		args = append(args, name)
+	}
+
+	name := funcName(fn.Name)
+	fmt.Fprintf(b, "\n// %s calls %s.\n", name, fn.Name)
+	if fn.Since > 0 {
+		fmt.Fprintf(b, "//\n// Since API level %d.\n", fn.Since)
+	}
+	if fn.DeprecatedSince > 0 {
+		fmt.Fprintf(b, "//\n// Deprecated: deprecated since API level %d.\n", fn.DeprecatedSince)
+	}
+
+	sig := strings.Join(append([]string{"ctx context.Context"}, params...), ", ")
+	call := strings.Join(append([]string{"ctx", fmt.Sprintf("%q", fn.Name), "nil"}, args...), ", ")
+	if ret == "" {
+		fmt.Fprintf(b, "func (v *Nvim) %s(%s) error {\n", name, sig)
+		fmt.Fprintf(b, "\treturn v.Call(%s)\n}\n", call)
+		return nil
+	}
+
+	call = strings.Replace(call, ", nil", ", &result", 1)
+	fmt.Fprintf(b, "func (v *Nvim) %s(%s) (%s, error) {\n", name, sig, ret)
+	fmt.Fprintf(b, "\tvar result %s\n", ret)
+	fmt.Fprintf(b, "\terr := v.Call(%s)\n", call)
+	b.WriteString("\treturn result, err\n}\n")
+
+	return nil
+}
+
+// writeBatchFunction writes the Batch method binding fn. The result, if
+// any, is stored through a trailing pointer parameter.
+func writeBatchFunction(b *bytes.Buffer, ext map[string]ExtType, fn Function) error {
+	ret, err := goType(ext, fn.ReturnType)
+	if err != nil {
+		return err
+	}
+
+	var params, args []string
+	for _, p := range fn.Parameters {
+		typ, err := goType(ext, p[0])
+		if err != nil {
+			return err
+		}
+		name := paramName(p[1])
+		params = append(params, name+" "+typ)
+		args = append(args, name)
+	}
+	result := "nil"
+	if ret != "" {
+		params = append(params, "result *"+ret)
+		result = "result"
+	}
+
+	name := funcName(fn.Name)
+	fmt.Fprintf(b, "\n// %s queues a call of %s.\n", name, fn.Name)
+	if fn.DeprecatedSince > 0 {
+		fmt.Fprintf(b, "//\n// Deprecated: deprecated since API level %d.\n", fn.DeprecatedSince)
+	}
+	fmt.Fprintf(b, "func (b *Batch) %s(%s) {\n", name, strings.Join(params, ", "))
+	fmt.Fprintf(b, "\tb.Call(%s)\n}\n", strings.Join(append([]string{fmt.Sprintf("%q", fn.Name), result}, args...), ", "))
+
+	return nil
+}
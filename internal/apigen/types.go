@@ -0,0 +1,121 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// basicTypes maps the API types to Go types.
+var basicTypes = map[string]string{
+	"void":       "",
+	"Boolean":    "bool",
+	"Integer":    "int",
+	"Float":      "float64",
+	"String":     "string",
+	"Object":     "interface{}",
+	"Array":      "[]interface{}",
+	"Dictionary": "map[string]interface{}",
+	"Dict":       "map[string]interface{}",
+}
+
+// goType returns the Go type of the API type typ. The types of ext are
+// mapped to the types of package types.
+func goType(ext map[string]ExtType, typ string) (string, error) {
+	if t, ok := basicTypes[typ]; ok {
+		return t, nil
+	}
+	if _, ok := ext[typ]; ok {
+		return "types." + typ, nil
+	}
+
+	// ArrayOf(T) or ArrayOf(T, n)
+	if inner, ok := strings.CutPrefix(typ, "ArrayOf("); ok && strings.HasSuffix(inner, ")") {
+		inner = strings.TrimSuffix(inner, ")")
+		elem, n, fixed := strings.Cut(inner, ",")
+		t, err := goType(ext, strings.TrimSpace(elem))
+		if err != nil {
+			return "", err
+		}
+		if !fixed {
+			return "[]" + t, nil
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(n))
+		if err != nil {
+			return "", fmt.Errorf("invalid type %s", typ)
+		}
+		return fmt.Sprintf("[%d]%s", size, t), nil
+	}
+	// Dict(name) is a dictionary of keyword arguments.
+	if strings.HasPrefix(typ, "Dict(") {
+		return "map[string]interface{}", nil
+	}
+	if strings.HasPrefix(typ, "Union(") {
+		return "interface{}", nil
+	}
+
+	// Notably LuaRef, which cannot be sent over RPC.
+	return "", fmt.Errorf("unsupported type %s", typ)
+}
+
+// initialisms are the words of function names written in upper case.
+var initialisms = map[string]bool{
+	"api": true,
+	"id":  true,
+	"ui":  true,
+}
+
+// renames maps the functions whose method name would clash with the
+// methods of rpc.Client or the hand-written methods of Nvim.
+var renames = map[string]string{
+	"nvim_notify":      "NotifyUser",
+	"nvim_subscribe":   "SubscribeEvent",
+	"nvim_unsubscribe": "UnsubscribeEvent",
+}
+
+// funcName returns the method name of the API function name.
+func funcName(name string) string {
+	if n, ok := renames[name]; ok {
+		return n
+	}
+
+	var b strings.Builder
+	for _, w := range strings.Split(strings.TrimPrefix(name, "nvim_"), "_") {
+		if initialisms[w] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		if w != "" {
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+
+	return b.String()
+}
+
+// paramName returns the Go name of the API parameter name.
+func paramName(name string) string {
+	words := strings.Split(name, "_")
+	for i := 1; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case initialisms[w]:
+			words[i] = strings.ToUpper(w)
+		case w != "":
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	s := strings.Join(words, "")
+	switch {
+	case s == "ctx", s == "v", s == "b", s == "result", s == "err":
+		return s + "_"
+	case token.IsKeyword(s):
+		return s + "_"
+	}
+
+	return s
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package sequence matches ordered sequences of names, such as the methods
+// called on a mock server or the events fired in a test.
+package sequence
+
+import (
+	"context"
+	"strings"
+)
+
+// Contains reports whether names contains seq in order, possibly with
+// other names in between.
+func Contains(names, seq []string) bool {
+	i := 0
+	for _, name := range names {
+		if i < len(seq) && name == seq[i] {
+			i++
+		}
+	}
+
+	return i == len(seq)
+}
+
+// Wait blocks until has reports true or ctx is done, in which case it
+// returns ctx.Err(). Besides its result, has returns a channel closed on
+// the next change that may alter it.
+func Wait(ctx context.Context, has func() (bool, <-chan struct{})) error {
+	for {
+		ok, changed := has()
+		if ok {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Join formats names for messages.
+func Join(names []string) string {
+	return "[" + strings.Join(names, " ") + "]"
+}
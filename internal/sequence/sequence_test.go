@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sequence_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-nvim/pkg/internal/sequence"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		names, seq string
+		want       bool
+	}{
+		{"a b c", "a c", true},
+		{"a b c", "", true},
+		{"a b c", "c a", false},
+		{"a b", "a a", false},
+		{"a b a", "a a", true},
+		{"", "a", false},
+	}
+	for _, tt := range tests {
+		if got := sequence.Contains(strings.Fields(tt.names), strings.Fields(tt.seq)); got != tt.want {
+			t.Errorf("Contains(%q, %q) = %v, want %v", tt.names, tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	changed := make(chan struct{})
+	n := 0
+	has := func() (bool, <-chan struct{}) {
+		n++
+		if n == 1 {
+			close(changed)
+		}
+		return n == 2, changed
+	}
+	if err := sequence.Wait(context.Background(), has); err != nil || n != 2 {
+		t.Errorf("Wait() = %v after %d checks, want nil after 2", err, n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	never := func() (bool, <-chan struct{}) { return false, make(chan struct{}) }
+	if err := sequence.Wait(ctx, never); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want %v", err, context.Canceled)
+	}
+}
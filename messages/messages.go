@@ -0,0 +1,184 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package messages reads and searches the message history of Neovim, as
+// shown by :messages.
+//
+// Messages are read from the output of :messages, so the messages of a UI
+// attached with ext_messages are only included once Neovim adds them to
+// the history.
+package messages
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-nvim/pkg/nverror"
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Severity is the severity of a message.
+type Severity int
+
+// List of severities.
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Message is a line of the message history.
+type Message struct {
+	Text     string
+	Severity Severity
+	Code     string // error or warning number, such as "E492", if any
+}
+
+// Parse parses the output of :messages, one message per line.
+func Parse(output string) []Message {
+	var msgs []Message
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		m := Message{Text: line}
+		if m.Code = nverror.MessageCode(line); m.Code != "" {
+			m.Severity = Error
+			if m.Code[0] == 'W' {
+				m.Severity = Warning
+			}
+		} else if strings.HasPrefix(line, "Error ") || strings.HasPrefix(line, "Error:") {
+			m.Severity = Error
+		}
+		msgs = append(msgs, m)
+	}
+
+	return msgs
+}
+
+// History returns the message history, oldest first.
+func History(ctx context.Context, v *nvim.Nvim) ([]Message, error) {
+	var out struct {
+		Output string `msgpack:"output"`
+	}
+	if err := v.Call(ctx, "nvim_exec2", &out, "messages", map[string]interface{}{"output": true}); err != nil {
+		return nil, err
+	}
+
+	return Parse(out.Output), nil
+}
+
+// Clear clears the message history, such as before the steps of a test
+// asserting on the messages they show.
+func Clear(ctx context.Context, v *nvim.Nvim) error {
+	return v.Command(ctx, "messages clear")
+}
+
+// Search returns the messages matching re.
+func Search(msgs []Message, re *regexp.Regexp) []Message {
+	var found []Message
+	for _, m := range msgs {
+		if re.MatchString(m.Text) {
+			found = append(found, m)
+		}
+	}
+
+	return found
+}
+
+// Contains reports whether a message contains s.
+func Contains(msgs []Message, s string) bool {
+	for _, m := range msgs {
+		if strings.Contains(m.Text, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LastError returns the last error of msgs.
+func LastError(msgs []Message) (Message, bool) {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Severity == Error {
+			return msgs[i], true
+		}
+	}
+
+	return Message{}, false
+}
+
+// CopyLastError copies the last error of the history to the clipboard
+// register "+", and returns it.
+func CopyLastError(ctx context.Context, v *nvim.Nvim) (Message, error) {
+	msgs, err := History(ctx, v)
+	if err != nil {
+		return Message{}, err
+	}
+	m, ok := LastError(msgs)
+	if !ok {
+		return Message{}, fmt.Errorf("messages: no error in the history")
+	}
+	if _, err := v.CallFunction(ctx, "setreg", []interface{}{"+", m.Text}); err != nil {
+		return Message{}, err
+	}
+
+	return m, nil
+}
+
+// showLua fills a scratch buffer with messages, opened in a split window,
+// and highlights them by severity.
+const showLua = `
+local lines, severities = ...
+local buf = vim.api.nvim_create_buf(false, true)
+vim.api.nvim_buf_set_lines(buf, 0, -1, true, lines)
+vim.bo[buf].modifiable = false
+vim.bo[buf].filetype = 'messages'
+local ns = vim.api.nvim_create_namespace('go_nvim_messages')
+for i, s in ipairs(severities) do
+  local group = s == 2 and 'ErrorMsg' or s == 1 and 'WarningMsg' or nil
+  if group then
+    vim.api.nvim_buf_add_highlight(buf, ns, group, i - 1, 0, -1)
+  end
+end
+vim.cmd('botright split')
+vim.api.nvim_win_set_buf(0, buf)
+return buf
+`
+
+// Show opens a scratch buffer in a new window listing the message history,
+// where it can be searched with the usual commands, and returns it.
+func Show(ctx context.Context, v *nvim.Nvim) (types.Buffer, error) {
+	msgs, err := History(ctx, v)
+	if err != nil {
+		return 0, err
+	}
+	lines := make([]string, len(msgs))
+	severities := make([]int, len(msgs))
+	for i, m := range msgs {
+		lines[i], severities[i] = m.Text, int(m.Severity)
+	}
+
+	var buf types.Buffer
+	if err := v.Call(ctx, "nvim_exec_lua", &buf, showLua, []interface{}{lines, severities}); err != nil {
+		return 0, err
+	}
+
+	return buf, nil
+}
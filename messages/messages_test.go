@@ -0,0 +1,30 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package messages_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/messages"
+)
+
+func TestParse(t *testing.T) {
+	output := "written\n" +
+		"Vim(call):E117: Unknown function: foo\n" +
+		"\n" +
+		"W10: Warning: Changing a readonly file\n" +
+		"Error executing lua callback: boom\n" +
+		"E492: Not an editor command: x"
+	want := []messages.Message{
+		{Text: "written"},
+		{Text: "Vim(call):E117: Unknown function: foo", Severity: messages.Error, Code: "E117"},
+		{Text: "W10: Warning: Changing a readonly file", Severity: messages.Warning, Code: "W10"},
+		{Text: "Error executing lua callback: boom", Severity: messages.Error},
+		{Text: "E492: Not an editor command: x", Severity: messages.Error, Code: "E492"},
+	}
+	if got := messages.Parse(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package mods composes command modifiers, such as :silent and :botright.
+//
+// Modifiers are built from the zero Mods, and passed to command strings
+// with String or Command, or to nvim_cmd with Dict:
+//
+//	m := mods.Mods{}.Silent().Keepalt().Botright()
+//	err := m.Command(ctx, v, "split")
+package mods
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-nvim/pkg/nvim"
+)
+
+// flag is a modifier without arguments.
+type flag uint32
+
+const (
+	sandbox flag = 1 << iota
+	silent
+	emsgSilent
+	unsilent
+	noautocmd
+	keepalt
+	keepjumps
+	keepmarks
+	keeppatterns
+	lockmarks
+	noswapfile
+	hide
+	confirm
+	browse
+	vertical
+	horizontal
+)
+
+// flags lists the modifiers without arguments in the order String writes
+// them, with their command and nvim_cmd key.
+var flags = []struct {
+	f   flag
+	cmd string
+	key string
+}{
+	{sandbox, "sandbox", "sandbox"},
+	{silent, "silent", "silent"},
+	{emsgSilent, "silent!", "emsg_silent"},
+	{unsilent, "unsilent", "unsilent"},
+	{noautocmd, "noautocmd", "noautocmd"},
+	{keepalt, "keepalt", "keepalt"},
+	{keepjumps, "keepjumps", "keepjumps"},
+	{keepmarks, "keepmarks", "keepmarks"},
+	{keeppatterns, "keeppatterns", "keeppatterns"},
+	{lockmarks, "lockmarks", "lockmarks"},
+	{noswapfile, "noswapfile", "noswapfile"},
+	{hide, "hide", "hide"},
+	{confirm, "confirm", "confirm"},
+	{browse, "browse", "browse"},
+	{vertical, "vertical", "vertical"},
+	{horizontal, "horizontal", "horizontal"},
+}
+
+// Mods is a set of command modifiers. The zero Mods has none. Mods are
+// values: the methods adding a modifier return a copy with it.
+type Mods struct {
+	flags   flag
+	split   string // aboveleft, belowright, topleft or botright
+	tab     int    // count of :tab plus 1, -1 for no count, or 0 for none
+	verbose int    // level of :verbose plus 1, or 0 for none
+
+	filter       string
+	filterInvert bool
+	hasFilter    bool
+}
+
+func (m Mods) with(f flag) Mods {
+	m.flags |= f
+	return m
+}
+
+// Sandbox adds :sandbox.
+func (m Mods) Sandbox() Mods { return m.with(sandbox) }
+
+// Silent adds :silent, which hides the normal messages, replacing
+// :silent!.
+func (m Mods) Silent() Mods {
+	m.flags &^= emsgSilent
+	return m.with(silent)
+}
+
+// SilentBang adds :silent!, which also hides the error messages, replacing
+// :silent.
+func (m Mods) SilentBang() Mods {
+	m.flags &^= silent
+	return m.with(emsgSilent)
+}
+
+// Unsilent adds :unsilent.
+func (m Mods) Unsilent() Mods { return m.with(unsilent) }
+
+// Noautocmd adds :noautocmd.
+func (m Mods) Noautocmd() Mods { return m.with(noautocmd) }
+
+// Keepalt adds :keepalt.
+func (m Mods) Keepalt() Mods { return m.with(keepalt) }
+
+// Keepjumps adds :keepjumps.
+func (m Mods) Keepjumps() Mods { return m.with(keepjumps) }
+
+// Keepmarks adds :keepmarks.
+func (m Mods) Keepmarks() Mods { return m.with(keepmarks) }
+
+// Keeppatterns adds :keeppatterns.
+func (m Mods) Keeppatterns() Mods { return m.with(keeppatterns) }
+
+// Lockmarks adds :lockmarks.
+func (m Mods) Lockmarks() Mods { return m.with(lockmarks) }
+
+// Noswapfile adds :noswapfile.
+func (m Mods) Noswapfile() Mods { return m.with(noswapfile) }
+
+// Hide adds :hide.
+func (m Mods) Hide() Mods { return m.with(hide) }
+
+// Confirm adds :confirm.
+func (m Mods) Confirm() Mods { return m.with(confirm) }
+
+// Browse adds :browse.
+func (m Mods) Browse() Mods { return m.with(browse) }
+
+// Vertical adds :vertical, replacing :horizontal.
+func (m Mods) Vertical() Mods {
+	m.flags &^= horizontal
+	return m.with(vertical)
+}
+
+// Horizontal adds :horizontal, replacing :vertical.
+func (m Mods) Horizontal() Mods {
+	m.flags &^= vertical
+	return m.with(horizontal)
+}
+
+// Aboveleft adds :aboveleft, replacing the other split modifiers.
+func (m Mods) Aboveleft() Mods { m.split = "aboveleft"; return m }
+
+// Belowright adds :belowright, replacing the other split modifiers.
+func (m Mods) Belowright() Mods { m.split = "belowright"; return m }
+
+// Topleft adds :topleft, replacing the other split modifiers.
+func (m Mods) Topleft() Mods { m.split = "topleft"; return m }
+
+// Botright adds :botright, replacing the other split modifiers.
+func (m Mods) Botright() Mods { m.split = "botright"; return m }
+
+// Tab adds :[n]tab, opening the window in a new tab page after the tab page
+// n, 0 being before the first one, or after the current tab page if n is
+// negative.
+func (m Mods) Tab(n int) Mods {
+	if n < 0 {
+		m.tab = -1
+		return m
+	}
+	m.tab = n + 1
+
+	return m
+}
+
+// Verbose adds :[level]verbose.
+func (m Mods) Verbose(level int) Mods { m.verbose = level + 1; return m }
+
+// Filter adds :filter /pattern/, or :filter! if invert is true, which
+// restricts the output of the command to the lines matching pattern.
+func (m Mods) Filter(pattern string, invert bool) Mods {
+	m.filter, m.filterInvert, m.hasFilter = pattern, invert, true
+	return m
+}
+
+// String returns the modifiers as a command prefix, followed by a space
+// unless there are none.
+func (m Mods) String() string {
+	var parts []string
+	for _, f := range flags {
+		if m.flags&f.f != 0 {
+			parts = append(parts, f.cmd)
+		}
+	}
+	if m.verbose > 0 {
+		parts = append(parts, fmt.Sprintf("%dverbose", m.verbose-1))
+	}
+	if m.split != "" {
+		parts = append(parts, m.split)
+	}
+	switch {
+	case m.tab > 0:
+		parts = append(parts, fmt.Sprintf("%dtab", m.tab-1))
+	case m.tab < 0:
+		parts = append(parts, "tab")
+	}
+	if m.hasFilter {
+		cmd := "filter"
+		if m.filterInvert {
+			cmd += "!"
+		}
+		parts = append(parts, fmt.Sprintf("%s /%s/", cmd, strings.ReplaceAll(m.filter, "/", `\/`)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return strings.Join(parts, " ") + " "
+}
+
+// Dict returns the modifiers as the mods of a command of nvim_cmd.
+//
+// nvim_cmd has no :tab without a count: Dict leaves it out, so call Resolve
+// first when the modifiers may have one.
+func (m Mods) Dict() map[string]interface{} {
+	d := make(map[string]interface{})
+	for _, f := range flags {
+		if m.flags&f.f != 0 {
+			d[f.key] = true
+		}
+	}
+	if m.verbose > 0 {
+		d["verbose"] = m.verbose - 1
+	}
+	if m.split != "" {
+		d["split"] = m.split
+	}
+	if m.tab > 0 {
+		d["tab"] = m.tab - 1
+	}
+	if m.hasFilter {
+		d["filter"] = map[string]interface{}{"pattern": m.filter, "force": m.filterInvert}
+	}
+
+	return d
+}
+
+// Resolve returns m with a :tab without a count replaced by the count of
+// the current tab page, which has the same effect.
+func (m Mods) Resolve(ctx context.Context, v *nvim.Nvim) (Mods, error) {
+	if m.tab >= 0 {
+		return m, nil
+	}
+	tab, err := v.GetCurrentTabpage(ctx)
+	if err != nil {
+		return m, err
+	}
+	n, err := v.TabpageGetNumber(ctx, tab)
+	if err != nil {
+		return m, err
+	}
+
+	return m.Tab(n), nil
+}
+
+// Command executes the Ex command cmd with the modifiers.
+func (m Mods) Command(ctx context.Context, v *nvim.Nvim, cmd string) error {
+	return v.Command(ctx, m.String()+cmd)
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package mods_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/mods"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/types"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    mods.Mods
+		want string
+	}{
+		{mods.Mods{}, ""},
+		{mods.Mods{}.Botright().Keepalt().Silent(), "silent keepalt botright "},
+		{mods.Mods{}.Silent().SilentBang(), "silent! "},
+		{mods.Mods{}.SilentBang().Silent(), "silent "},
+		{mods.Mods{}.Vertical().Horizontal(), "horizontal "},
+		{mods.Mods{}.Tab(0), "0tab "},
+		{mods.Mods{}.Tab(2), "2tab "},
+		{mods.Mods{}.Tab(-1), "tab "},
+		{mods.Mods{}.Filter("a/b", true), `filter! /a\/b/ `},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDict(t *testing.T) {
+	got := mods.Mods{}.SilentBang().Tab(1).Verbose(0).Dict()
+	want := map[string]interface{}{"emsg_silent": true, "tab": 1, "verbose": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict() = %v, want %v", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_get_current_tabpage", types.Tabpage(1))
+	s.Return("nvim_tabpage_get_number", 3)
+	ctx := context.Background()
+
+	m, err := mods.Mods{}.Tab(-1).Resolve(ctx, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.String(); got != "3tab " {
+		t.Errorf("String() = %q, want %q", got, "3tab ")
+	}
+
+	s.Reset()
+	if _, err := (mods.Mods{}).Tab(0).Resolve(ctx, v); err != nil {
+		t.Fatal(err)
+	}
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Errorf("counted :tab resolved with %v", calls)
+	}
+}
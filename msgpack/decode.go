@@ -0,0 +1,1108 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package msgpack
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Unmarshaler is implemented by types that decode themselves.
+type Unmarshaler interface {
+	UnmarshalMsgPack(d *Decoder) error
+}
+
+// Unmarshal decodes the MessagePack value data and stores the result in the
+// value pointed to by v.
+func Unmarshal(data []byte, v interface{}) error {
+	p := decoderPool.Get().(*pooledDecoder)
+	p.r.Reset(data)
+	err := p.d.Decode(v)
+	p.r.Reset(nil)
+	if cap(p.d.scratch) <= maxScratch {
+		decoderPool.Put(p)
+	}
+
+	return err
+}
+
+// pooledDecoder is a Decoder of a byte slice reused by Unmarshal.
+type pooledDecoder struct {
+	r bytes.Reader
+	d Decoder
+}
+
+var decoderPool = sync.Pool{
+	New: func() interface{} {
+		p := &pooledDecoder{}
+		p.d.r = &p.r
+		return p
+	},
+}
+
+// maxScratch is the size of the largest string read through the scratch
+// buffer of a Decoder.
+const maxScratch = 64 << 10
+
+// TypeError describes a value that cannot be decoded into a Go type.
+type TypeError struct {
+	Code byte
+	Type reflect.Type
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("msgpack: cannot decode %s into %s", formatName(e.Code), e.Type)
+}
+
+// reader is the input of a Decoder.
+type reader interface {
+	io.Reader
+	io.ByteScanner
+}
+
+// Decoder reads MessagePack values from an input stream.
+type Decoder struct {
+	r       reader
+	scratch []byte
+}
+
+// NewDecoder returns a new Decoder reading from r.
+//
+// The Decoder buffers r unless it implements io.ByteScanner, and may read
+// data from r beyond the MessagePack values requested.
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &Decoder{r: br}
+}
+
+// Decode reads the next MessagePack value and stores it in the value
+// pointed to by v.
+//
+// Decode reverses the mapping of Encoder.Encode. Decoding into an empty
+// interface stores:
+//
+//   - nil for nil
+//   - bool for booleans
+//   - int64 for integers, or uint64 for integers above math.MaxInt64
+//   - float64 for floats
+//   - string for strings
+//   - []byte for binary
+//   - []interface{} for arrays
+//   - map[string]interface{} for maps with string keys only, and
+//     map[interface{}]interface{} for other maps
+//   - the type registered with RegisterExt, or Extension, for extensions
+//
+// Structs are decoded from maps by matching keys against field names, and
+// from arrays by field order.
+//
+// As Neovim sends them interchangeably, booleans decode into integers as 0
+// and 1, integers into booleans as false for 0 and true otherwise, and
+// empty arrays, which is how empty Lua tables are sent, into maps.
+func (d *Decoder) Decode(v interface{}) error {
+	if ok, err := d.decodeFast(v); ok {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errNonPointer(v)
+	}
+
+	return d.decode(rv.Elem())
+}
+
+// errNonPointer returns the error of decoding into v, which is not a
+// non-nil pointer.
+func errNonPointer(v interface{}) error {
+	return fmt.Errorf("msgpack: Decode of non-pointer %T", v)
+}
+
+// DecodeInterface reads the next value as it would be decoded into an
+// empty interface.
+func (d *Decoder) DecodeInterface() (interface{}, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.decodeInterface(code)
+}
+
+func (d *Decoder) readCode() (byte, error) {
+	c, err := d.r.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return c, err
+}
+
+// peekCode returns the format code of the next value without consuming it.
+func (d *Decoder) peekCode() (byte, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if err := d.r.UnreadByte(); err != nil {
+		return 0, err
+	}
+
+	return c, nil
+}
+
+func (d *Decoder) readFull(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (d *Decoder) readUint(size int) (uint64, error) {
+	// Read byte by byte: a local array passed to Read would escape to the
+	// heap.
+	var u uint64
+	for i := 0; i < size; i++ {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		u = u<<8 | uint64(c)
+	}
+
+	return u, nil
+}
+
+// IsNil reports whether the next value is nil, without consuming it.
+func (d *Decoder) IsNil() (bool, error) {
+	code, err := d.peekCode()
+	if err != nil {
+		return false, err
+	}
+
+	return code == codeNil, nil
+}
+
+// DecodeNil reads a nil value.
+func (d *Decoder) DecodeNil() error {
+	code, err := d.readCode()
+	if err != nil {
+		return err
+	}
+	if code != codeNil {
+		return &TypeError{Code: code, Type: nil}
+	}
+
+	return nil
+}
+
+// DecodeBool reads a boolean.
+func (d *Decoder) DecodeBool() (bool, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return false, err
+	}
+	switch code {
+	case codeTrue:
+		return true, nil
+	case codeFalse:
+		return false, nil
+	}
+
+	return false, &TypeError{Code: code, Type: reflect.TypeOf(false)}
+}
+
+// DecodeInt reads an integer.
+func (d *Decoder) DecodeInt() (int64, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return 0, err
+	}
+	i, u, isUint, err := d.readInt(code)
+	if err != nil {
+		return 0, err
+	}
+	if isUint {
+		if u > math.MaxInt64 {
+			return 0, fmt.Errorf("msgpack: integer %d overflows int64", u)
+		}
+		return int64(u), nil
+	}
+
+	return i, nil
+}
+
+// DecodeUint reads a non-negative integer.
+func (d *Decoder) DecodeUint() (uint64, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return 0, err
+	}
+	i, u, isUint, err := d.readInt(code)
+	if err != nil {
+		return 0, err
+	}
+	if !isUint {
+		if i < 0 {
+			return 0, fmt.Errorf("msgpack: negative integer %d for uint64", i)
+		}
+		return uint64(i), nil
+	}
+
+	return u, nil
+}
+
+// readInt reads the integer of format code. Unsigned formats are returned
+// in u with isUint set.
+func (d *Decoder) readInt(code byte) (i int64, u uint64, isUint bool, err error) {
+	switch {
+	case code <= maxFixInt:
+		return 0, uint64(code), true, nil
+	case code >= 0xe0:
+		return int64(int8(code)), 0, false, nil
+	}
+
+	switch code {
+	case codeUint8:
+		u, err = d.readUint(1)
+		return 0, u, true, err
+	case codeUint16:
+		u, err = d.readUint(2)
+		return 0, u, true, err
+	case codeUint32:
+		u, err = d.readUint(4)
+		return 0, u, true, err
+	case codeUint64:
+		u, err = d.readUint(8)
+		return 0, u, true, err
+	case codeInt8:
+		u, err = d.readUint(1)
+		return int64(int8(u)), 0, false, err
+	case codeInt16:
+		u, err = d.readUint(2)
+		return int64(int16(u)), 0, false, err
+	case codeInt32:
+		u, err = d.readUint(4)
+		return int64(int32(u)), 0, false, err
+	case codeInt64:
+		u, err = d.readUint(8)
+		return int64(u), 0, false, err
+	case codeFalse, codeTrue:
+		// Vimscript booleans are sometimes sent where numbers are
+		// expected.
+		if code == codeTrue {
+			u = 1
+		}
+		return 0, u, true, nil
+	}
+
+	return 0, 0, false, &TypeError{Code: code, Type: reflect.TypeOf(int64(0))}
+}
+
+// DecodeFloat reads a float, or an integer as a float.
+func (d *Decoder) DecodeFloat() (float64, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return 0, err
+	}
+
+	return d.readFloat(code)
+}
+
+func (d *Decoder) readFloat(code byte) (float64, error) {
+	switch code {
+	case codeFloat32:
+		u, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case codeFloat64:
+		u, err := d.readUint(8)
+		return math.Float64frombits(u), err
+	}
+
+	i, u, isUint, err := d.readInt(code)
+	if err != nil {
+		return 0, &TypeError{Code: code, Type: reflect.TypeOf(float64(0))}
+	}
+	if isUint {
+		return float64(u), nil
+	}
+
+	return float64(i), nil
+}
+
+// DecodeString reads a string, or binary as a string.
+func (d *Decoder) DecodeString() (string, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return "", err
+	}
+
+	return d.readString(code)
+}
+
+// readString reads the string or binary of format code as a string. Short
+// strings are read through the scratch buffer of d, so that only the string
+// is allocated.
+func (d *Decoder) readString(code byte) (string, error) {
+	n, err := d.bytesLen(code)
+	if err != nil {
+		return "", err
+	}
+	if n > maxScratch {
+		b, err := d.readFull(n)
+		return string(b), err
+	}
+	if cap(d.scratch) < n {
+		d.scratch = make([]byte, n, max(n, 64))
+	}
+	b := d.scratch[:n]
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// DecodeBytes reads binary, or a string as binary.
+func (d *Decoder) DecodeBytes() ([]byte, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return nil, err
+	}
+	if code == codeNil {
+		return nil, nil
+	}
+
+	return d.readBytes(code)
+}
+
+// bytesLen returns the length of the string or binary of format code.
+func (d *Decoder) bytesLen(code byte) (int, error) {
+	if code&0xe0 == codeFixStr {
+		return int(code & 0x1f), nil
+	}
+
+	var size int
+	switch code {
+	case codeStr8, codeBin8:
+		size = 1
+	case codeStr16, codeBin16:
+		size = 2
+	case codeStr32, codeBin32:
+		size = 4
+	default:
+		return 0, &TypeError{Code: code, Type: reflect.TypeOf("")}
+	}
+	n, err := d.readUint(size)
+
+	return int(n), err
+}
+
+func (d *Decoder) readBytes(code byte) ([]byte, error) {
+	n, err := d.bytesLen(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.readFull(n)
+}
+
+// DecodeArrayLen reads the header of an array and returns its length. It
+// returns -1 for nil.
+func (d *Decoder) DecodeArrayLen() (int, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return 0, err
+	}
+
+	return d.arrayLen(code)
+}
+
+func (d *Decoder) arrayLen(code byte) (int, error) {
+	if code&0xf0 == codeFixArray {
+		return int(code & 0x0f), nil
+	}
+	switch code {
+	case codeNil:
+		return -1, nil
+	case codeArray16:
+		n, err := d.readUint(2)
+		return int(n), err
+	case codeArray32:
+		n, err := d.readUint(4)
+		return int(n), err
+	}
+
+	return 0, &TypeError{Code: code, Type: reflect.TypeOf([]interface{}(nil))}
+}
+
+// DecodeArrayFunc reads an array, calling fn with the encoding of each
+// element as it is read, so that a large array is never held in memory at
+// once. A nil value is an empty array. elem is only valid until fn
+// returns.
+//
+// If fn returns an error, the remaining elements are skipped and
+// DecodeArrayFunc returns the error. A value other than an array is skipped
+// and reported with a *TypeError. Either way the Decoder is left at the
+// next value.
+func (d *Decoder) DecodeArrayFunc(fn func(elem RawMessage) error) error {
+	code, err := d.peekCode()
+	if err != nil {
+		return err
+	}
+	if code != codeNil && code&0xf0 != codeFixArray && code != codeArray16 && code != codeArray32 {
+		if err := d.Skip(); err != nil {
+			return err
+		}
+		return &TypeError{Code: code, Type: reflect.TypeOf([]interface{}(nil))}
+	}
+
+	n, err := d.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	var buf []byte
+	for i := 0; i < n; i++ {
+		if ferr != nil {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if buf, err = d.readRaw(buf[:0]); err != nil {
+			return err
+		}
+		ferr = fn(buf)
+	}
+
+	return ferr
+}
+
+// DecodeMapLen reads the header of a map and returns its number of
+// key/value pairs. It returns -1 for nil.
+func (d *Decoder) DecodeMapLen() (int, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return 0, err
+	}
+
+	return d.mapLen(code)
+}
+
+func (d *Decoder) mapLen(code byte) (int, error) {
+	if code&0xf0 == codeFixMap {
+		return int(code & 0x0f), nil
+	}
+	switch code {
+	case codeNil:
+		return -1, nil
+	case codeFixArray:
+		// An empty Lua table.
+		return 0, nil
+	case codeMap16:
+		n, err := d.readUint(2)
+		return int(n), err
+	case codeMap32:
+		n, err := d.readUint(4)
+		return int(n), err
+	}
+
+	return 0, &TypeError{Code: code, Type: reflect.TypeOf(map[string]interface{}(nil))}
+}
+
+// DecodeExt reads an extension value and returns its type and data.
+func (d *Decoder) DecodeExt() (int8, []byte, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return d.readExt(code)
+}
+
+func (d *Decoder) extLen(code byte) (int, error) {
+	switch code {
+	case codeFixExt1:
+		return 1, nil
+	case codeFixExt2:
+		return 2, nil
+	case codeFixExt4:
+		return 4, nil
+	case codeFixExt8:
+		return 8, nil
+	case codeFixExt16:
+		return 16, nil
+	case codeExt8:
+		n, err := d.readUint(1)
+		return int(n), err
+	case codeExt16:
+		n, err := d.readUint(2)
+		return int(n), err
+	case codeExt32:
+		n, err := d.readUint(4)
+		return int(n), err
+	}
+
+	return 0, &TypeError{Code: code, Type: reflect.TypeOf(Extension{})}
+}
+
+func (d *Decoder) readExt(code byte) (int8, []byte, error) {
+	n, err := d.extLen(code)
+	if err != nil {
+		return 0, nil, err
+	}
+	typ, err := d.readCode()
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := d.readFull(n)
+
+	return int8(typ), data, err
+}
+
+// Skip skips the next value.
+func (d *Decoder) Skip() error {
+	_, err := d.readRaw(nil)
+	return err
+}
+
+// DecodeRaw reads the next value without decoding it.
+func (d *Decoder) DecodeRaw() (RawMessage, error) {
+	return d.readRaw(nil)
+}
+
+// readRaw appends the encoding of the next value to buf.
+func (d *Decoder) readRaw(buf []byte) ([]byte, error) {
+	code, err := d.readCode()
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, code)
+
+	// fixed returns buf with the next n bytes appended.
+	fixed := func(n int) ([]byte, error) {
+		start := len(buf)
+		buf = append(buf, make([]byte, n)...)
+		if _, err := io.ReadFull(d.r, buf[start:]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		return buf, nil
+	}
+	// length reads a big-endian length of size bytes into buf.
+	length := func(size int) (int, error) {
+		start := len(buf)
+		if _, err := fixed(size); err != nil {
+			return 0, err
+		}
+		var n uint64
+		for _, b := range buf[start:] {
+			n = n<<8 | uint64(b)
+		}
+		return int(n), nil
+	}
+
+	var elems int
+	switch {
+	case code <= maxFixInt, code >= 0xe0, code == codeNil, code == codeTrue, code == codeFalse:
+		return buf, nil
+	case code&0xe0 == codeFixStr:
+		return fixed(int(code & 0x1f))
+	case code&0xf0 == codeFixArray:
+		elems = int(code & 0x0f)
+	case code&0xf0 == codeFixMap:
+		elems = 2 * int(code&0x0f)
+	default:
+		switch code {
+		case codeUint8, codeInt8:
+			return fixed(1)
+		case codeUint16, codeInt16:
+			return fixed(2)
+		case codeUint32, codeInt32, codeFloat32:
+			return fixed(4)
+		case codeUint64, codeInt64, codeFloat64:
+			return fixed(8)
+		case codeStr8, codeBin8, codeStr16, codeBin16, codeStr32, codeBin32:
+			n, err := length(lenSize(code))
+			if err != nil {
+				return nil, err
+			}
+			return fixed(n)
+		case codeFixExt1, codeFixExt2, codeFixExt4, codeFixExt8, codeFixExt16:
+			return fixed(1<<(code-codeFixExt1) + 1)
+		case codeExt8, codeExt16, codeExt32:
+			n, err := length(lenSize(code))
+			if err != nil {
+				return nil, err
+			}
+			return fixed(n + 1)
+		case codeArray16, codeArray32:
+			n, err := length(lenSize(code))
+			if err != nil {
+				return nil, err
+			}
+			elems = n
+		case codeMap16, codeMap32:
+			n, err := length(lenSize(code))
+			if err != nil {
+				return nil, err
+			}
+			elems = 2 * n
+		default:
+			return nil, fmt.Errorf("msgpack: invalid code %#x", code)
+		}
+	}
+
+	for i := 0; i < elems; i++ {
+		if buf, err = d.readRaw(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+// lenSize returns the size of the length of a variable-length format code.
+func lenSize(code byte) int {
+	switch code {
+	case codeStr8, codeBin8, codeExt8:
+		return 1
+	case codeStr16, codeBin16, codeExt16, codeArray16, codeMap16:
+		return 2
+	}
+
+	return 4
+}
+
+var (
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	stringsType     = reflect.TypeOf([]string(nil))
+)
+
+func (d *Decoder) decode(v reflect.Value) error {
+	if v.Kind() != reflect.Ptr && v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalMsgPack(d)
+	}
+
+	code, err := d.peekCode()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if code == codeNil {
+		if _, err := d.readCode(); err != nil {
+			return err
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(v.Elem())
+
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			x, err := d.DecodeInterface()
+			if err != nil {
+				return err
+			}
+			if x != nil {
+				v.Set(reflect.ValueOf(x))
+			} else {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			return nil
+		}
+		if v.IsNil() || v.Elem().Kind() != reflect.Ptr {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		return d.decode(v.Elem())
+	}
+
+	if _, err := d.readCode(); err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		switch code {
+		case codeTrue, codeFalse:
+			v.SetBool(code == codeTrue)
+		default:
+			i, u, _, err := d.readInt(code)
+			if err != nil {
+				return &TypeError{Code: code, Type: v.Type()}
+			}
+			// Vimscript booleans are sometimes numbers.
+			v.SetBool(i != 0 || u != 0)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, u, isUint, err := d.readInt(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		if isUint {
+			if u > math.MaxInt64 {
+				return fmt.Errorf("msgpack: integer %d overflows %s", u, v.Type())
+			}
+			i = int64(u)
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("msgpack: integer %d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, u, isUint, err := d.readInt(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		if !isUint {
+			if i < 0 {
+				return fmt.Errorf("msgpack: negative integer %d for %s", i, v.Type())
+			}
+			u = uint64(i)
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("msgpack: integer %d overflows %s", u, v.Type())
+		}
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := d.readFloat(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		v.SetFloat(f)
+
+	case reflect.String:
+		str, err := d.readString(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		v.SetString(str)
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.readBytes(code)
+			if err != nil {
+				return &TypeError{Code: code, Type: v.Type()}
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		n, err := d.arrayLen(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		if v.Type() == stringsType {
+			list, err := d.decodeStrings(n)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(list))
+			return nil
+		}
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := d.decode(s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+
+	case reflect.Array:
+		n, err := d.arrayLen(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		for i := 0; i < n; i++ {
+			if i >= v.Len() {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		n, err := d.mapLen(code)
+		if err != nil {
+			return &TypeError{Code: code, Type: v.Type()}
+		}
+		typ := v.Type()
+		m := reflect.MakeMapWithSize(typ, n)
+		for i := 0; i < n; i++ {
+			key := reflect.New(typ.Key()).Elem()
+			if err := d.decode(key); err != nil {
+				return err
+			}
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := d.decode(elem); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+
+	case reflect.Struct:
+		return d.decodeStruct(code, v)
+
+	default:
+		return &TypeError{Code: code, Type: v.Type()}
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeStruct(code byte, v reflect.Value) error {
+	fields := cachedFields(v.Type())
+
+	if n, err := d.arrayLen(code); err == nil {
+		// Decode arrays by field order, as used for tuples such as
+		// cursor positions.
+		for i := 0; i < n; i++ {
+			if i >= len(fields) {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.decode(v.Field(fields[i].index)); err != nil {
+				return fmt.Errorf("%s.%s: %w", v.Type(), fields[i].goName, err)
+			}
+		}
+		return nil
+	}
+
+	n, err := d.mapLen(code)
+	if err != nil {
+		return &TypeError{Code: code, Type: v.Type()}
+	}
+	for i := 0; i < n; i++ {
+		key, err := d.DecodeString()
+		if err != nil {
+			return err
+		}
+		f := lookupField(fields, key)
+		if f == nil {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.decode(v.Field(f.index)); err != nil {
+			return fmt.Errorf("%s.%s: %w", v.Type(), f.goName, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupField returns the field named name, preferring an exact match over
+// a case-insensitive one.
+func lookupField(fields []field, name string) *field {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder) decodeInterface(code byte) (interface{}, error) {
+	switch {
+	case code <= maxFixInt:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xe0 == codeFixStr:
+		return d.readString(code)
+	case code&0xf0 == codeFixArray:
+		return d.decodeArrayInterface(int(code & 0x0f))
+	case code&0xf0 == codeFixMap:
+		return d.decodeMapInterface(int(code & 0x0f))
+	}
+
+	switch code {
+	case codeNil:
+		return nil, nil
+	case codeTrue:
+		return true, nil
+	case codeFalse:
+		return false, nil
+	case codeUint8, codeUint16, codeUint32, codeUint64, codeInt8, codeInt16, codeInt32, codeInt64:
+		i, u, isUint, err := d.readInt(code)
+		if err != nil {
+			return nil, err
+		}
+		if isUint {
+			if u > math.MaxInt64 {
+				return u, nil
+			}
+			return int64(u), nil
+		}
+		return i, nil
+	case codeFloat32, codeFloat64:
+		return d.readFloat(code)
+	case codeStr8, codeStr16, codeStr32:
+		return d.readString(code)
+	case codeBin8, codeBin16, codeBin32:
+		return d.readBytes(code)
+	case codeArray16, codeArray32:
+		n, err := d.arrayLen(code)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArrayInterface(n)
+	case codeMap16, codeMap32:
+		n, err := d.mapLen(code)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMapInterface(n)
+	case codeFixExt1, codeFixExt2, codeFixExt4, codeFixExt8, codeFixExt16, codeExt8, codeExt16, codeExt32:
+		typ, data, err := d.readExt(code)
+		if err != nil {
+			return nil, err
+		}
+		return decodeExtInterface(typ, data)
+	}
+
+	return nil, fmt.Errorf("msgpack: invalid code %#x", code)
+}
+
+func (d *Decoder) decodeArrayInterface(n int) ([]interface{}, error) {
+	list := make([]interface{}, n)
+	for i := range list {
+		x, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		list[i] = x
+	}
+
+	return list, nil
+}
+
+func (d *Decoder) decodeMapInterface(n int) (interface{}, error) {
+	keys := make([]interface{}, n)
+	values := make([]interface{}, n)
+	stringKeys := true
+	for i := 0; i < n; i++ {
+		k, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		switch kk := k.(type) {
+		case string:
+		case []byte:
+			// Binary is not comparable; use it as a string key.
+			k = string(kk)
+			stringKeys = false
+		default:
+			stringKeys = false
+		}
+		v, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		keys[i], values[i] = k, v
+	}
+
+	if stringKeys {
+		m := make(map[string]interface{}, n)
+		for i, k := range keys {
+			m[k.(string)] = values[i]
+		}
+		return m, nil
+	}
+
+	m := make(map[interface{}]interface{}, n)
+	for i, k := range keys {
+		if !reflect.TypeOf(k).Comparable() {
+			return nil, errors.New("msgpack: map key is not comparable")
+		}
+		m[k] = values[i]
+	}
+
+	return m, nil
+}
+
+// formatName returns a description of the value of format code.
+func formatName(code byte) string {
+	switch {
+	case code <= maxFixInt, code >= 0xe0:
+		return "integer"
+	case code&0xe0 == codeFixStr:
+		return "string"
+	case code&0xf0 == codeFixArray:
+		return "array"
+	case code&0xf0 == codeFixMap:
+		return "map"
+	}
+
+	switch code {
+	case codeNil:
+		return "nil"
+	case codeTrue, codeFalse:
+		return "boolean"
+	case codeUint8, codeUint16, codeUint32, codeUint64, codeInt8, codeInt16, codeInt32, codeInt64:
+		return "integer"
+	case codeFloat32, codeFloat64:
+		return "float"
+	case codeStr8, codeStr16, codeStr32:
+		return "string"
+	case codeBin8, codeBin16, codeBin32:
+		return "binary"
+	case codeArray16, codeArray32:
+		return "array"
+	case codeMap16, codeMap32:
+		return "map"
+	case codeFixExt1, codeFixExt2, codeFixExt4, codeFixExt8, codeFixExt16, codeExt8, codeExt16, codeExt32:
+		return "extension"
+	}
+
+	return fmt.Sprintf("code %#x", code)
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package msgpack_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+func TestDecodeNeovimValues(t *testing.T) {
+	type event struct {
+		Visual bool           `msgpack:"visual"`
+		Count  int            `msgpack:"count"`
+		Info   map[string]int `msgpack:"info"`
+	}
+	tests := []struct {
+		in   interface{}
+		want event
+	}{
+		// Vimscript numeric booleans, and booleans for numbers.
+		{map[string]interface{}{"visual": 1, "count": true}, event{Visual: true, Count: 1}},
+		{map[string]interface{}{"visual": 0, "count": false}, event{}},
+		// Empty Lua tables are sent as arrays.
+		{map[string]interface{}{"info": []interface{}{}}, event{Info: map[string]int{}}},
+		{[]interface{}{}, event{}},
+	}
+	for _, tt := range tests {
+		data, err := msgpack.Marshal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, unmarshal := range map[string]func([]byte, interface{}) error{
+			"Fast":    msgpack.Unmarshal,
+			"Reflect": msgpack.UnmarshalReflect,
+		} {
+			var got event
+			if err := unmarshal(data, &got); err != nil {
+				t.Errorf("%s: Unmarshal(%v): %v", name, tt.in, err)
+				continue
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: Unmarshal(%v) = %+v, want %+v", name, tt.in, got, tt.want)
+			}
+		}
+	}
+
+	// A non-empty array is still not a map.
+	data, _ := msgpack.Marshal([]interface{}{1})
+	var m map[string]int
+	if err := msgpack.Unmarshal(data, &m); err == nil {
+		t.Error("Unmarshal of a non-empty array into a map succeeded")
+	}
+}
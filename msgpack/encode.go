@@ -0,0 +1,400 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package msgpack
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Marshaler is implemented by types that encode themselves.
+type Marshaler interface {
+	MarshalMsgPack(e *Encoder) error
+}
+
+// Marshal returns the MessagePack encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	e := encoderPool.Get().(*Encoder)
+	e.buf = e.buf[:0]
+	err := e.encodeAny(v)
+	data := append([]byte(nil), e.buf...)
+	if cap(e.buf) <= maxPooledBuffer {
+		encoderPool.Put(e)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// encoderPool holds the Encoders used by Marshal, whose buffers are reused.
+var encoderPool = sync.Pool{
+	New: func() interface{} { return &Encoder{} },
+}
+
+// maxPooledBuffer is the capacity of the largest buffer kept for reuse.
+const maxPooledBuffer = 1 << 20
+
+// Encoder writes MessagePack values to an output stream.
+type Encoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewEncoder returns a new Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the MessagePack encoding of v.
+//
+// The value is written to the underlying writer with a single Write call.
+//
+// Values are encoded as follows:
+//
+//   - nil pointers and interfaces as nil
+//   - booleans, integers, floats and strings as the corresponding types
+//   - []byte as binary
+//   - arrays and other slices as arrays, and maps as maps, even if nil:
+//     Neovim rejects nil for Array and Dictionary parameters
+//   - structs as maps keyed by field name, or by the name in the "msgpack"
+//     field tag; a "-" tag skips the field, and the "omitempty" option
+//     skips empty values
+//   - types implementing Marshaler by their MarshalMsgPack method
+func (e *Encoder) Encode(v interface{}) error {
+	e.buf = e.buf[:0]
+	if err := e.encodeAny(v); err != nil {
+		return err
+	}
+	_, err := e.w.Write(e.buf)
+	if cap(e.buf) > maxPooledBuffer {
+		// Do not hold on to the buffer of an exceptionally large value.
+		e.buf = nil
+	}
+
+	return err
+}
+
+// EncodeValue encodes v as part of the value being encoded by
+// Encoder.Encode. It is meant to be used by Marshaler implementations.
+func (e *Encoder) EncodeValue(v interface{}) error {
+	return e.encodeAny(v)
+}
+
+// EncodeNil encodes nil.
+func (e *Encoder) EncodeNil() { e.buf = append(e.buf, codeNil) }
+
+// EncodeBool encodes b.
+func (e *Encoder) EncodeBool(b bool) {
+	if b {
+		e.buf = append(e.buf, codeTrue)
+	} else {
+		e.buf = append(e.buf, codeFalse)
+	}
+}
+
+// EncodeInt encodes i using the smallest representation.
+func (e *Encoder) EncodeInt(i int64) {
+	switch {
+	case i >= 0:
+		e.EncodeUint(uint64(i))
+	case i >= -32:
+		e.buf = append(e.buf, byte(i))
+	case i >= math.MinInt8:
+		e.buf = append(e.buf, codeInt8, byte(i))
+	case i >= math.MinInt16:
+		e.buf = append(e.buf, codeInt16, byte(i>>8), byte(i))
+	case i >= math.MinInt32:
+		e.buf = append(e.buf, codeInt32)
+		e.buf = appendUint32(e.buf, uint32(i))
+	default:
+		e.buf = append(e.buf, codeInt64)
+		e.buf = appendUint64(e.buf, uint64(i))
+	}
+}
+
+// EncodeUint encodes u using the smallest representation.
+func (e *Encoder) EncodeUint(u uint64) {
+	switch {
+	case u <= maxFixInt:
+		e.buf = append(e.buf, byte(u))
+	case u <= math.MaxUint8:
+		e.buf = append(e.buf, codeUint8, byte(u))
+	case u <= math.MaxUint16:
+		e.buf = append(e.buf, codeUint16, byte(u>>8), byte(u))
+	case u <= math.MaxUint32:
+		e.buf = append(e.buf, codeUint32)
+		e.buf = appendUint32(e.buf, uint32(u))
+	default:
+		e.buf = append(e.buf, codeUint64)
+		e.buf = appendUint64(e.buf, u)
+	}
+}
+
+// EncodeFloat encodes f as a 64-bit float.
+func (e *Encoder) EncodeFloat(f float64) {
+	e.buf = append(e.buf, codeFloat64)
+	e.buf = appendUint64(e.buf, math.Float64bits(f))
+}
+
+// EncodeString encodes s as a string.
+func (e *Encoder) EncodeString(s string) {
+	n := len(s)
+	switch {
+	case n <= maxFixStr:
+		e.buf = append(e.buf, codeFixStr|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, codeStr8, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, codeStr16, byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, codeStr32)
+		e.buf = appendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, s...)
+}
+
+// EncodeBytes encodes b as binary.
+func (e *Encoder) EncodeBytes(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, codeBin8, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, codeBin16, byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, codeBin32)
+		e.buf = appendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, b...)
+}
+
+// EncodeArrayLen encodes the header of an array of n elements. The
+// elements must be encoded next.
+func (e *Encoder) EncodeArrayLen(n int) {
+	switch {
+	case n <= maxFixArray:
+		e.buf = append(e.buf, codeFixArray|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, codeArray16, byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, codeArray32)
+		e.buf = appendUint32(e.buf, uint32(n))
+	}
+}
+
+// EncodeMapLen encodes the header of a map of n key/value pairs. The keys
+// and values must be encoded next, alternately.
+func (e *Encoder) EncodeMapLen(n int) {
+	switch {
+	case n <= maxFixMap:
+		e.buf = append(e.buf, codeFixMap|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, codeMap16, byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, codeMap32)
+		e.buf = appendUint32(e.buf, uint32(n))
+	}
+}
+
+// EncodeExt encodes an extension value of type typ.
+func (e *Encoder) EncodeExt(typ int8, data []byte) {
+	n := len(data)
+	switch n {
+	case 1:
+		e.buf = append(e.buf, codeFixExt1)
+	case 2:
+		e.buf = append(e.buf, codeFixExt2)
+	case 4:
+		e.buf = append(e.buf, codeFixExt4)
+	case 8:
+		e.buf = append(e.buf, codeFixExt8)
+	case 16:
+		e.buf = append(e.buf, codeFixExt16)
+	default:
+		switch {
+		case n <= math.MaxUint8:
+			e.buf = append(e.buf, codeExt8, byte(n))
+		case n <= math.MaxUint16:
+			e.buf = append(e.buf, codeExt16, byte(n>>8), byte(n))
+		default:
+			e.buf = append(e.buf, codeExt32)
+			e.buf = appendUint32(e.buf, uint32(n))
+		}
+	}
+	e.buf = append(e.buf, byte(typ))
+	e.buf = append(e.buf, data...)
+}
+
+// encodeRaw appends an already encoded value.
+func (e *Encoder) encodeRaw(raw []byte) {
+	e.buf = append(e.buf, raw...)
+}
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+func (e *Encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.EncodeNil()
+		return nil
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		return v.Interface().(Marshaler).MarshalMsgPack(e)
+	}
+	if v.Kind() != reflect.Ptr && v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler).MarshalMsgPack(e)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.EncodeBool(v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.EncodeInt(v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.EncodeUint(v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		e.EncodeFloat(v.Float())
+
+	case reflect.String:
+		e.EncodeString(v.String())
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		return e.encode(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		if v.CanInterface() {
+			// Interface returns the boxed value without allocating.
+			return e.encodeAny(v.Interface())
+		}
+		return e.encode(v.Elem())
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.EncodeBytes(v.Bytes())
+			return nil
+		}
+		return e.encodeArray(v)
+
+	case reflect.Array:
+		return e.encodeArray(v)
+
+	case reflect.Map:
+		return e.encodeMap(v)
+
+	case reflect.Struct:
+		return e.encodeStruct(v)
+
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeArray(v reflect.Value) error {
+	n := v.Len()
+	e.EncodeArrayLen(n)
+	for i := 0; i < n; i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeMap(v reflect.Value) error {
+	keys := v.MapKeys()
+	if v.Type().Key().Kind() == reflect.String {
+		// Sort string keys so that the encoding is deterministic.
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	}
+
+	e.EncodeMapLen(len(keys))
+	for _, k := range keys {
+		if err := e.encode(k); err != nil {
+			return err
+		}
+		if err := e.encode(v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeStruct(v reflect.Value) error {
+	fields := cachedFields(v.Type())
+
+	n := 0
+	for _, f := range fields {
+		if !f.omitEmpty || !isEmptyValue(v.Field(f.index)) {
+			n++
+		}
+	}
+
+	e.EncodeMapLen(n)
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		e.EncodeString(f.name)
+		if err := e.encode(fv); err != nil {
+			return fmt.Errorf("%s.%s: %w", v.Type(), f.goName, err)
+		}
+	}
+
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func appendUint32(b []byte, u uint32) []byte {
+	return append(b, byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendUint64(b []byte, u uint64) []byte {
+	return append(b,
+		byte(u>>56), byte(u>>48), byte(u>>40), byte(u>>32),
+		byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
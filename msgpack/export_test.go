@@ -0,0 +1,24 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package msgpack
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// UnmarshalReflect is Unmarshal without the fast path of common types.
+func UnmarshalReflect(data []byte, v interface{}) error {
+	d := NewDecoder(bytes.NewReader(data))
+
+	return d.decode(reflect.ValueOf(v).Elem())
+}
+
+// MarshalReflect is Marshal without the fast path of common types.
+func MarshalReflect(v interface{}) ([]byte, error) {
+	e := &Encoder{}
+	err := e.encode(reflect.ValueOf(v))
+
+	return e.buf, err
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package msgpack
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// This file implements the encoding and decoding of the types that make up
+// most RPC traffic, such as buffer lines, without reflection.
+
+// decodeFast decodes into v if it points to a common type, and reports
+// whether it did.
+func (d *Decoder) decodeFast(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case *interface{}:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		x, err := d.DecodeInterface()
+		*v = x
+		return true, err
+
+	case *[]interface{}:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		code, err := d.readCode()
+		if err != nil {
+			return true, err
+		}
+		if code == codeNil {
+			*v = nil
+			return true, nil
+		}
+		n, err := d.arrayLen(code)
+		if err != nil {
+			return true, &TypeError{Code: code, Type: reflect.TypeOf(*v)}
+		}
+		*v, err = d.decodeArrayInterface(n)
+		return true, err
+
+	case *string:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		code, err := d.readCode()
+		if err != nil {
+			return true, err
+		}
+		if code == codeNil {
+			*v = ""
+			return true, nil
+		}
+		if *v, err = d.readString(code); err != nil {
+			return true, &TypeError{Code: code, Type: reflect.TypeOf("")}
+		}
+		return true, nil
+
+	case *[]string:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		code, err := d.readCode()
+		if err != nil {
+			return true, err
+		}
+		if code == codeNil {
+			*v = nil
+			return true, nil
+		}
+		n, err := d.arrayLen(code)
+		if err != nil {
+			return true, &TypeError{Code: code, Type: reflect.TypeOf(*v)}
+		}
+		*v, err = d.decodeStrings(n)
+		return true, err
+
+	case *int:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		code, err := d.readCode()
+		if err != nil {
+			return true, err
+		}
+		if code == codeNil {
+			*v = 0
+			return true, nil
+		}
+		i, err := d.readIntN(code, math.MinInt, math.MaxInt)
+		*v = int(i)
+		return true, err
+
+	case *int64:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		code, err := d.readCode()
+		if err != nil {
+			return true, err
+		}
+		if code == codeNil {
+			*v = 0
+			return true, nil
+		}
+		*v, err = d.readIntN(code, math.MinInt64, math.MaxInt64)
+		return true, err
+
+	case *bool:
+		if v == nil {
+			return true, errNonPointer(v)
+		}
+		code, err := d.readCode()
+		if err != nil {
+			return true, err
+		}
+		x, err := d.decodeInterface(code)
+		if err != nil {
+			return true, err
+		}
+		switch x := x.(type) {
+		case nil:
+			*v = false
+		case bool:
+			*v = x
+		case int64:
+			// Vimscript booleans are sometimes numbers.
+			*v = x != 0
+		default:
+			return true, &TypeError{Code: code, Type: reflect.TypeOf(false)}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// decodeStrings decodes n strings.
+func (d *Decoder) decodeStrings(n int) ([]string, error) {
+	list := make([]string, n)
+	for i := range list {
+		code, err := d.readCode()
+		if err != nil {
+			return nil, err
+		}
+		if code == codeNil {
+			continue
+		}
+		if list[i], err = d.readString(code); err != nil {
+			return nil, &TypeError{Code: code, Type: reflect.TypeOf("")}
+		}
+	}
+
+	return list, nil
+}
+
+// readIntN reads the integer of format code, which must be in [min, max].
+func (d *Decoder) readIntN(code byte, min, max int64) (int64, error) {
+	i, u, isUint, err := d.readInt(code)
+	if err != nil {
+		return 0, err
+	}
+	if isUint {
+		if u > uint64(max) {
+			return 0, fmt.Errorf("msgpack: integer %d overflows int", u)
+		}
+		return int64(u), nil
+	}
+	if i < min {
+		return 0, fmt.Errorf("msgpack: integer %d overflows int", i)
+	}
+
+	return i, nil
+}
+
+// encodeAny encodes v, without reflection if it is of a common type.
+func (e *Encoder) encodeAny(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		e.EncodeNil()
+	case bool:
+		e.EncodeBool(v)
+	case int:
+		e.EncodeInt(int64(v))
+	case int64:
+		e.EncodeInt(v)
+	case uint64:
+		e.EncodeUint(v)
+	case float64:
+		e.EncodeFloat(v)
+	case string:
+		e.EncodeString(v)
+
+	case []byte:
+		e.EncodeBytes(v)
+
+	case []string:
+		e.EncodeArrayLen(len(v))
+		for _, s := range v {
+			e.EncodeString(s)
+		}
+
+	case []int:
+		e.EncodeArrayLen(len(v))
+		for _, i := range v {
+			e.EncodeInt(int64(i))
+		}
+
+	case []interface{}:
+		e.EncodeArrayLen(len(v))
+		for _, x := range v {
+			if err := e.encodeAny(x); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		e.EncodeMapLen(len(keys))
+		for _, k := range keys {
+			e.EncodeString(k)
+			if err := e.encodeAny(v[k]); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return e.encode(reflect.ValueOf(v))
+	}
+
+	return nil
+}
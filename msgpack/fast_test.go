@@ -0,0 +1,189 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package msgpack_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/msgpack"
+	"github.com/go-nvim/pkg/types"
+)
+
+func TestUnmarshalNilPointer(t *testing.T) {
+	data, err := msgpack.Marshal(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []interface{}{
+		(*interface{})(nil),
+		(*[]interface{})(nil),
+		(*string)(nil),
+		(*[]string)(nil),
+		(*int)(nil),
+		(*int64)(nil),
+		(*bool)(nil),
+		(*float64)(nil),
+	} {
+		err := msgpack.Unmarshal(data, v)
+		want := fmt.Sprintf("msgpack: Decode of non-pointer %T", v)
+		if err == nil || err.Error() != want {
+			t.Errorf("Unmarshal into %T: got %v, want %s", v, err, want)
+		}
+	}
+}
+
+func TestUnmarshalBoolTypeError(t *testing.T) {
+	data, err := msgpack.Marshal("yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bool
+	err = msgpack.Unmarshal(data, &b)
+	var te *msgpack.TypeError
+	if !errors.As(err, &te) {
+		t.Fatalf("got %v, want a TypeError", err)
+	}
+	if te.Code != data[0] {
+		t.Errorf("got code %#x, want %#x", te.Code, data[0])
+	}
+}
+
+func TestFastMatchesReflect(t *testing.T) {
+	for _, v := range []interface{}{
+		[]string{"a", "", "ü"},
+		[]int{1, -1, 1 << 40},
+		[]interface{}{nil, true, int64(-3), "x", []interface{}{"y"}},
+		map[string]interface{}{"b": int64(1), "a": "x"},
+		[]interface{}{types.Buffer(1), types.Window(1000), types.Tabpage(2)},
+	} {
+		fast, err := msgpack.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slow, err := msgpack.MarshalReflect(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(fast) != string(slow) {
+			t.Errorf("Marshal(%v) = %x, reflective path %x", v, fast, slow)
+		}
+
+		got := reflect.New(reflect.TypeOf(v))
+		if err := msgpack.Unmarshal(fast, got.Interface()); err != nil {
+			t.Fatal(err)
+		}
+		want := reflect.New(reflect.TypeOf(v))
+		if err := msgpack.UnmarshalReflect(fast, want.Interface()); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.Elem().Interface(), v) || !reflect.DeepEqual(want.Elem().Interface(), v) {
+			t.Errorf("Unmarshal(%v) = %v, reflective path %v", v, got.Elem(), want.Elem())
+		}
+	}
+}
+
+// benchValues are the values of the benchmarks, shaped like common RPC
+// traffic.
+func benchValues() map[string]interface{} {
+	lines := make([]string, 1000)
+	ints := make([]int, 1000)
+	handles := make([]interface{}, 1000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("\tline %d of a buffer, with some text", i)
+		ints[i] = i * 1000
+		handles[i] = types.Window(1000 + i)
+	}
+
+	return map[string]interface{}{
+		"Lines":   lines,
+		"Ints":    ints,
+		"Handles": handles,
+	}
+}
+
+var benchNames = []string{"Lines", "Ints", "Handles"}
+
+func BenchmarkDecode(b *testing.B) {
+	values := benchValues()
+	for _, name := range benchNames {
+		v := values[name]
+		data, err := msgpack.Marshal(v)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, path := range []struct {
+			name      string
+			unmarshal func([]byte, interface{}) error
+		}{
+			{"Fast", msgpack.Unmarshal},
+			{"Reflect", msgpack.UnmarshalReflect},
+		} {
+			b.Run(name+"/"+path.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+				for i := 0; i < b.N; i++ {
+					p := reflect.New(reflect.TypeOf(v)).Interface()
+					if err := path.unmarshal(data, p); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	values := benchValues()
+	for _, name := range benchNames {
+		v := values[name]
+		for _, path := range []struct {
+			name    string
+			marshal func(interface{}) ([]byte, error)
+		}{
+			{"Fast", msgpack.Marshal},
+			{"Reflect", msgpack.MarshalReflect},
+		} {
+			b.Run(name+"/"+path.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := path.marshal(v); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalNilCollections(t *testing.T) {
+	type opts struct {
+		Lines []string
+	}
+	for _, tt := range []struct {
+		v    interface{}
+		want string
+	}{
+		{[]string(nil), "90"},
+		{[]int(nil), "90"},
+		{[]interface{}(nil), "90"},
+		{map[string]interface{}(nil), "80"},
+		{[]types.Buffer(nil), "90"},
+		{map[string]string(nil), "80"},
+		{opts{}, "81a54c696e657390"},
+		{(*int)(nil), "c0"},
+	} {
+		for _, marshal := range []func(interface{}) ([]byte, error){msgpack.Marshal, msgpack.MarshalReflect} {
+			data, err := marshal(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprintf("%x", data); got != tt.want {
+				t.Errorf("Marshal(%#v) = %s, want %s", tt.v, got, tt.want)
+			}
+		}
+	}
+}
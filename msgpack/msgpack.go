@@ -0,0 +1,159 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package msgpack implements encoding and decoding of MessagePack values as
+// used by the Neovim RPC protocol.
+package msgpack
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// List of MessagePack format codes.
+const (
+	codeFixMap   = 0x80
+	codeFixArray = 0x90
+	codeFixStr   = 0xa0
+	codeNil      = 0xc0
+	codeFalse    = 0xc2
+	codeTrue     = 0xc3
+	codeBin8     = 0xc4
+	codeBin16    = 0xc5
+	codeBin32    = 0xc6
+	codeExt8     = 0xc7
+	codeExt16    = 0xc8
+	codeExt32    = 0xc9
+	codeFloat32  = 0xca
+	codeFloat64  = 0xcb
+	codeUint8    = 0xcc
+	codeUint16   = 0xcd
+	codeUint32   = 0xce
+	codeUint64   = 0xcf
+	codeInt8     = 0xd0
+	codeInt16    = 0xd1
+	codeInt32    = 0xd2
+	codeInt64    = 0xd3
+	codeFixExt1  = 0xd4
+	codeFixExt2  = 0xd5
+	codeFixExt4  = 0xd6
+	codeFixExt8  = 0xd7
+	codeFixExt16 = 0xd8
+	codeStr8     = 0xd9
+	codeStr16    = 0xda
+	codeStr32    = 0xdb
+	codeArray16  = 0xdc
+	codeArray32  = 0xdd
+	codeMap16    = 0xde
+	codeMap32    = 0xdf
+
+	maxFixInt   = 0x7f
+	maxFixStr   = 0x1f
+	maxFixArray = 0x0f
+	maxFixMap   = 0x0f
+)
+
+// Extension is a MessagePack extension value of an unregistered type.
+type Extension struct {
+	Type int8
+	Data []byte
+}
+
+// MarshalMsgPack implements Marshaler.
+func (x Extension) MarshalMsgPack(e *Encoder) error {
+	e.EncodeExt(x.Type, x.Data)
+	return nil
+}
+
+// RawMessage is a raw encoded MessagePack value.
+//
+// It can be used to delay decoding, or to embed an already encoded value.
+type RawMessage []byte
+
+// MarshalMsgPack implements Marshaler.
+func (m RawMessage) MarshalMsgPack(e *Encoder) error {
+	if len(m) == 0 {
+		e.EncodeNil()
+		return nil
+	}
+	e.encodeRaw(m)
+
+	return nil
+}
+
+// UnmarshalMsgPack implements Unmarshaler.
+func (m *RawMessage) UnmarshalMsgPack(d *Decoder) error {
+	raw, err := d.DecodeRaw()
+	if err != nil {
+		return err
+	}
+	*m = raw
+
+	return nil
+}
+
+// field is an encoded field of a struct.
+type field struct {
+	index     int
+	name      string
+	goName    string
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// cachedFields returns the encoded fields of the struct type typ.
+func cachedFields(typ reflect.Type) []field {
+	if f, ok := fieldCache.Load(typ); ok {
+		return f.([]field)
+	}
+
+	var fields []field
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+
+		tag := sf.Tag.Get("msgpack")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{
+			index:     i,
+			name:      name,
+			goName:    sf.Name,
+			omitEmpty: opts == "omitempty",
+		})
+	}
+
+	f, _ := fieldCache.LoadOrStore(typ, fields)
+
+	return f.([]field)
+}
+
+var extensions sync.Map // map[int8]func([]byte) (interface{}, error)
+
+// RegisterExt registers decode as the function decoding the data of
+// extension values of type typ into an empty interface.
+//
+// It is meant to be called from the init function of the package defining
+// the Go type of the extension.
+func RegisterExt(typ int8, decode func(data []byte) (interface{}, error)) {
+	extensions.Store(typ, decode)
+}
+
+// decodeExtInterface returns the extension value of type typ.
+func decodeExtInterface(typ int8, data []byte) (interface{}, error) {
+	if decode, ok := extensions.Load(typ); ok {
+		return decode.(func([]byte) (interface{}, error))(data)
+	}
+
+	return Extension{Type: typ, Data: data}, nil
+}
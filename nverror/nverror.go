@@ -0,0 +1,150 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package nverror classifies the errors returned by Neovim.
+//
+// The errors returned by calls are *rpc.Error values holding the type and
+// message Neovim sent. The kinds and codes of this package match them with
+// errors.Is:
+//
+//	if errors.Is(err, nverror.ErrInvalidBuffer) {
+//		// The buffer was wiped out.
+//	}
+//	if errors.Is(err, nverror.Code(492)) {
+//		// Not an editor command.
+//	}
+package nverror
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-nvim/pkg/rpc"
+)
+
+// Error is a Neovim error parsed by Parse.
+type Error struct {
+	Type    rpc.ErrorType
+	Code    int // E number, or 0 if the message has none
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// codeRE matches the E or W number of a message, such as in
+// "Vim(call):E117: Unknown function: foo".
+var codeRE = regexp.MustCompile(`\b([EW])(\d+):`)
+
+// Parse returns the Neovim error in the chain of err, if any.
+func Parse(err error) (*Error, bool) {
+	var e *rpc.Error
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+
+	return &Error{Type: e.Type, Code: code(e.Message), Message: e.Message}, true
+}
+
+func code(msg string) int {
+	m := codeRE.FindStringSubmatch(msg)
+	if m == nil || m[1] != "E" {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[2])
+
+	return n
+}
+
+// MessageCode returns the error or warning number of msg, such as "E117"
+// for "Vim(call):E117: Unknown function: foo" or "W10" for "W10: Warning:
+// Changing a readonly file", or "" if it has none.
+func MessageCode(msg string) string {
+	m := codeRE.FindStringSubmatch(msg)
+	if m == nil {
+		return ""
+	}
+
+	return m[1] + m[2]
+}
+
+// CodeOf returns the E number of the Neovim error in the chain of err, or 0
+// if there is none.
+func CodeOf(err error) int {
+	e, ok := Parse(err)
+	if !ok {
+		return 0
+	}
+
+	return e.Code
+}
+
+// Code is an E number. It matches the Neovim errors with that number with
+// errors.Is.
+type Code int
+
+func (c Code) Error() string {
+	return fmt.Sprintf("E%d", int(c))
+}
+
+// MatchError implements the matching of rpc.Error.Is.
+func (c Code) MatchError(e *rpc.Error) bool {
+	return code(e.Message) == int(c)
+}
+
+// Kind is a class of Neovim errors. It matches them with errors.Is.
+type Kind struct {
+	name  string
+	match func(e *rpc.Error) bool
+}
+
+func (k *Kind) Error() string {
+	return k.name
+}
+
+// MatchError implements the matching of rpc.Error.Is.
+func (k *Kind) MatchError(e *rpc.Error) bool {
+	return k.match(e)
+}
+
+// prefix returns a Kind matching the messages starting with p.
+func prefix(name, p string) *Kind {
+	return &Kind{name: name, match: func(e *rpc.Error) bool {
+		return strings.HasPrefix(e.Message, p)
+	}}
+}
+
+// List of error kinds.
+var (
+	ErrValidation = &Kind{name: "validation error", match: func(e *rpc.Error) bool {
+		return e.Type == rpc.ValidationError
+	}}
+	ErrException = &Kind{name: "exception", match: func(e *rpc.Error) bool {
+		return e.Type == rpc.ExceptionError
+	}}
+
+	ErrInvalidBuffer  = prefix("invalid buffer", "Invalid buffer id")
+	ErrInvalidWindow  = prefix("invalid window", "Invalid window id")
+	ErrInvalidTabpage = prefix("invalid tab page", "Invalid tabpage id")
+	ErrKeyNotFound    = prefix("key not found", "Key not found")
+)
+
+// IsBufferInvalid reports whether err is caused by an invalid buffer, such
+// as one that was wiped out.
+func IsBufferInvalid(err error) bool {
+	return errors.Is(err, ErrInvalidBuffer)
+}
+
+// IsWindowInvalid reports whether err is caused by an invalid window.
+func IsWindowInvalid(err error) bool {
+	return errors.Is(err, ErrInvalidWindow)
+}
+
+// IsTabpageInvalid reports whether err is caused by an invalid tab page.
+func IsTabpageInvalid(err error) bool {
+	return errors.Is(err, ErrInvalidTabpage)
+}
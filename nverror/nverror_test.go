@@ -0,0 +1,92 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nverror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-nvim/pkg/nverror"
+	"github.com/go-nvim/pkg/rpc"
+)
+
+func TestMessageCode(t *testing.T) {
+	tests := []struct {
+		msg, want string
+	}{
+		{"Vim(call):E117: Unknown function: foo", "E117"},
+		{"W10: Warning: Changing a readonly file", "W10"},
+		{"Vim:E5108: Error executing lua", "E5108"},
+		{"TE1: not a code", ""},
+		{"E12 without colon", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := nverror.MessageCode(tt.msg); got != tt.want {
+			t.Errorf("MessageCode(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	err := fmt.Errorf("call: %w", &rpc.Error{Type: rpc.ExceptionError, Message: "Vim(call):E117: Unknown function: foo"})
+	e, ok := nverror.Parse(err)
+	if !ok {
+		t.Fatal("Parse() found no Neovim error")
+	}
+	want := &nverror.Error{Type: rpc.ExceptionError, Code: 117, Message: "Vim(call):E117: Unknown function: foo"}
+	if *e != *want {
+		t.Errorf("Parse() = %+v, want %+v", e, want)
+	}
+	if _, ok := nverror.Parse(errors.New("E117: not from Neovim")); ok {
+		t.Error("Parse() of a Go error succeeded")
+	}
+
+	for msg, want := range map[string]int{
+		"Vim:E492: Not an editor command: x":     492,
+		"W10: Warning: Changing a readonly file": 0,
+		"Invalid buffer id: 9":                   0,
+	} {
+		if got := nverror.CodeOf(&rpc.Error{Message: msg}); got != want {
+			t.Errorf("CodeOf(%q) = %d, want %d", msg, got, want)
+		}
+	}
+	if got := nverror.CodeOf(errors.New("E1: x")); got != 0 {
+		t.Errorf("CodeOf() of a Go error = %d, want 0", got)
+	}
+}
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		err    *rpc.Error
+		target error
+		want   bool
+	}{
+		{&rpc.Error{Type: rpc.ExceptionError, Message: "Vim:E492: Not an editor command"}, nverror.Code(492), true},
+		{&rpc.Error{Type: rpc.ExceptionError, Message: "Vim:E492: Not an editor command"}, nverror.Code(49), false},
+		{&rpc.Error{Type: rpc.ExceptionError, Message: "x"}, nverror.ErrException, true},
+		{&rpc.Error{Type: rpc.ExceptionError, Message: "x"}, nverror.ErrValidation, false},
+		{&rpc.Error{Type: rpc.ValidationError, Message: "x"}, nverror.ErrValidation, true},
+		{&rpc.Error{Type: rpc.ValidationError, Message: "Invalid buffer id: 9"}, nverror.ErrInvalidBuffer, true},
+		{&rpc.Error{Type: rpc.ValidationError, Message: "Invalid window id: 1000"}, nverror.ErrInvalidWindow, true},
+		{&rpc.Error{Type: rpc.ValidationError, Message: "Invalid window id: 1000"}, nverror.ErrInvalidBuffer, false},
+		{&rpc.Error{Type: rpc.ValidationError, Message: "Invalid tabpage id: 3"}, nverror.ErrInvalidTabpage, true},
+		{&rpc.Error{Type: rpc.ValidationError, Message: "Key not found: foo"}, nverror.ErrKeyNotFound, true},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("wrapped: %w", tt.err)
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%q, %v) = %v, want %v", tt.err.Message, tt.target, got, tt.want)
+		}
+	}
+
+	buf := &rpc.Error{Type: rpc.ValidationError, Message: "Invalid buffer id: 9"}
+	if !nverror.IsBufferInvalid(buf) || nverror.IsWindowInvalid(buf) || nverror.IsTabpageInvalid(buf) {
+		t.Error("buffer error misclassified")
+	}
+	if nverror.IsBufferInvalid(errors.New("Invalid buffer id: 9")) {
+		t.Error("Go error classified as a Neovim error")
+	}
+}
@@ -0,0 +1,1809 @@
+// Code generated by apigen from the API metadata of Neovim 0.9.5 (API level 11). DO NOT EDIT.
+
+package nvim
+
+import (
+	"context"
+
+	"github.com/go-nvim/pkg/types"
+)
+
+// BufAddHighlight calls nvim_buf_add_highlight.
+//
+// Since API level 1.
+func (v *Nvim) BufAddHighlight(ctx context.Context, buffer types.Buffer, nsID int, hlGroup string, line int, colStart int, colEnd int) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_buf_add_highlight", &result, buffer, nsID, hlGroup, line, colStart, colEnd)
+	return result, err
+}
+
+// BufAttach calls nvim_buf_attach.
+//
+// Since API level 4.
+func (v *Nvim) BufAttach(ctx context.Context, buffer types.Buffer, sendBuffer bool, opts map[string]interface{}) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_attach", &result, buffer, sendBuffer, opts)
+	return result, err
+}
+
+// BufClearNamespace calls nvim_buf_clear_namespace.
+//
+// Since API level 5.
+func (v *Nvim) BufClearNamespace(ctx context.Context, buffer types.Buffer, nsID int, lineStart int, lineEnd int) error {
+	return v.Call(ctx, "nvim_buf_clear_namespace", nil, buffer, nsID, lineStart, lineEnd)
+}
+
+// BufDelExtmark calls nvim_buf_del_extmark.
+//
+// Since API level 7.
+func (v *Nvim) BufDelExtmark(ctx context.Context, buffer types.Buffer, nsID int, id int) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_del_extmark", &result, buffer, nsID, id)
+	return result, err
+}
+
+// BufDelKeymap calls nvim_buf_del_keymap.
+//
+// Since API level 6.
+func (v *Nvim) BufDelKeymap(ctx context.Context, buffer types.Buffer, mode string, lhs string) error {
+	return v.Call(ctx, "nvim_buf_del_keymap", nil, buffer, mode, lhs)
+}
+
+// BufDelMark calls nvim_buf_del_mark.
+//
+// Since API level 8.
+func (v *Nvim) BufDelMark(ctx context.Context, buffer types.Buffer, name string) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_del_mark", &result, buffer, name)
+	return result, err
+}
+
+// BufDelVar calls nvim_buf_del_var.
+//
+// Since API level 1.
+func (v *Nvim) BufDelVar(ctx context.Context, buffer types.Buffer, name string) error {
+	return v.Call(ctx, "nvim_buf_del_var", nil, buffer, name)
+}
+
+// BufDelete calls nvim_buf_delete.
+//
+// Since API level 7.
+func (v *Nvim) BufDelete(ctx context.Context, buffer types.Buffer, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_buf_delete", nil, buffer, opts)
+}
+
+// BufDetach calls nvim_buf_detach.
+//
+// Since API level 4.
+func (v *Nvim) BufDetach(ctx context.Context, buffer types.Buffer) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_detach", &result, buffer)
+	return result, err
+}
+
+// BufGetChangedtick calls nvim_buf_get_changedtick.
+//
+// Since API level 2.
+func (v *Nvim) BufGetChangedtick(ctx context.Context, buffer types.Buffer) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_buf_get_changedtick", &result, buffer)
+	return result, err
+}
+
+// BufGetExtmarkByID calls nvim_buf_get_extmark_by_id.
+//
+// Since API level 7.
+func (v *Nvim) BufGetExtmarkByID(ctx context.Context, buffer types.Buffer, nsID int, id int, opts map[string]interface{}) ([]int, error) {
+	var result []int
+	err := v.Call(ctx, "nvim_buf_get_extmark_by_id", &result, buffer, nsID, id, opts)
+	return result, err
+}
+
+// BufGetExtmarks calls nvim_buf_get_extmarks.
+//
+// Since API level 7.
+func (v *Nvim) BufGetExtmarks(ctx context.Context, buffer types.Buffer, nsID int, start interface{}, end interface{}, opts map[string]interface{}) ([]interface{}, error) {
+	var result []interface{}
+	err := v.Call(ctx, "nvim_buf_get_extmarks", &result, buffer, nsID, start, end, opts)
+	return result, err
+}
+
+// BufGetKeymap calls nvim_buf_get_keymap.
+//
+// Since API level 3.
+func (v *Nvim) BufGetKeymap(ctx context.Context, buffer types.Buffer, mode string) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+	err := v.Call(ctx, "nvim_buf_get_keymap", &result, buffer, mode)
+	return result, err
+}
+
+// BufGetLines calls nvim_buf_get_lines.
+//
+// Since API level 1.
+func (v *Nvim) BufGetLines(ctx context.Context, buffer types.Buffer, start int, end int, strictIndexing bool) ([]string, error) {
+	var result []string
+	err := v.Call(ctx, "nvim_buf_get_lines", &result, buffer, start, end, strictIndexing)
+	return result, err
+}
+
+// BufGetMark calls nvim_buf_get_mark.
+//
+// Since API level 1.
+func (v *Nvim) BufGetMark(ctx context.Context, buffer types.Buffer, name string) ([2]int, error) {
+	var result [2]int
+	err := v.Call(ctx, "nvim_buf_get_mark", &result, buffer, name)
+	return result, err
+}
+
+// BufGetName calls nvim_buf_get_name.
+//
+// Since API level 1.
+func (v *Nvim) BufGetName(ctx context.Context, buffer types.Buffer) (string, error) {
+	var result string
+	err := v.Call(ctx, "nvim_buf_get_name", &result, buffer)
+	return result, err
+}
+
+// BufGetOffset calls nvim_buf_get_offset.
+//
+// Since API level 5.
+func (v *Nvim) BufGetOffset(ctx context.Context, buffer types.Buffer, index int) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_buf_get_offset", &result, buffer, index)
+	return result, err
+}
+
+// BufGetOption calls nvim_buf_get_option.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 10.
+func (v *Nvim) BufGetOption(ctx context.Context, buffer types.Buffer, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_buf_get_option", &result, buffer, name)
+	return result, err
+}
+
+// BufGetText calls nvim_buf_get_text.
+//
+// Since API level 9.
+func (v *Nvim) BufGetText(ctx context.Context, buffer types.Buffer, startRow int, startCol int, endRow int, endCol int, opts map[string]interface{}) ([]string, error) {
+	var result []string
+	err := v.Call(ctx, "nvim_buf_get_text", &result, buffer, startRow, startCol, endRow, endCol, opts)
+	return result, err
+}
+
+// BufGetVar calls nvim_buf_get_var.
+//
+// Since API level 1.
+func (v *Nvim) BufGetVar(ctx context.Context, buffer types.Buffer, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_buf_get_var", &result, buffer, name)
+	return result, err
+}
+
+// BufIsLoaded calls nvim_buf_is_loaded.
+//
+// Since API level 5.
+func (v *Nvim) BufIsLoaded(ctx context.Context, buffer types.Buffer) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_is_loaded", &result, buffer)
+	return result, err
+}
+
+// BufIsValid calls nvim_buf_is_valid.
+//
+// Since API level 1.
+func (v *Nvim) BufIsValid(ctx context.Context, buffer types.Buffer) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_is_valid", &result, buffer)
+	return result, err
+}
+
+// BufLineCount calls nvim_buf_line_count.
+//
+// Since API level 1.
+func (v *Nvim) BufLineCount(ctx context.Context, buffer types.Buffer) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_buf_line_count", &result, buffer)
+	return result, err
+}
+
+// BufSetExtmark calls nvim_buf_set_extmark.
+//
+// Since API level 7.
+func (v *Nvim) BufSetExtmark(ctx context.Context, buffer types.Buffer, nsID int, line int, col int, opts map[string]interface{}) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_buf_set_extmark", &result, buffer, nsID, line, col, opts)
+	return result, err
+}
+
+// BufSetKeymap calls nvim_buf_set_keymap.
+//
+// Since API level 6.
+func (v *Nvim) BufSetKeymap(ctx context.Context, buffer types.Buffer, mode string, lhs string, rhs string, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_buf_set_keymap", nil, buffer, mode, lhs, rhs, opts)
+}
+
+// BufSetLines calls nvim_buf_set_lines.
+//
+// Since API level 1.
+func (v *Nvim) BufSetLines(ctx context.Context, buffer types.Buffer, start int, end int, strictIndexing bool, replacement []string) error {
+	return v.Call(ctx, "nvim_buf_set_lines", nil, buffer, start, end, strictIndexing, replacement)
+}
+
+// BufSetMark calls nvim_buf_set_mark.
+//
+// Since API level 8.
+func (v *Nvim) BufSetMark(ctx context.Context, buffer types.Buffer, name string, line int, col int, opts map[string]interface{}) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_buf_set_mark", &result, buffer, name, line, col, opts)
+	return result, err
+}
+
+// BufSetName calls nvim_buf_set_name.
+//
+// Since API level 1.
+func (v *Nvim) BufSetName(ctx context.Context, buffer types.Buffer, name string) error {
+	return v.Call(ctx, "nvim_buf_set_name", nil, buffer, name)
+}
+
+// BufSetOption calls nvim_buf_set_option.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 10.
+func (v *Nvim) BufSetOption(ctx context.Context, buffer types.Buffer, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_buf_set_option", nil, buffer, name, value)
+}
+
+// BufSetText calls nvim_buf_set_text.
+//
+// Since API level 7.
+func (v *Nvim) BufSetText(ctx context.Context, buffer types.Buffer, startRow int, startCol int, endRow int, endCol int, replacement []string) error {
+	return v.Call(ctx, "nvim_buf_set_text", nil, buffer, startRow, startCol, endRow, endCol, replacement)
+}
+
+// BufSetVar calls nvim_buf_set_var.
+//
+// Since API level 1.
+func (v *Nvim) BufSetVar(ctx context.Context, buffer types.Buffer, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_buf_set_var", nil, buffer, name, value)
+}
+
+// CallAtomic calls nvim_call_atomic.
+//
+// Since API level 1.
+func (v *Nvim) CallAtomic(ctx context.Context, calls []interface{}) ([]interface{}, error) {
+	var result []interface{}
+	err := v.Call(ctx, "nvim_call_atomic", &result, calls)
+	return result, err
+}
+
+// CallFunction calls nvim_call_function.
+//
+// Since API level 1.
+func (v *Nvim) CallFunction(ctx context.Context, fn string, args []interface{}) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_call_function", &result, fn, args)
+	return result, err
+}
+
+// ClearAutocmds calls nvim_clear_autocmds.
+//
+// Since API level 9.
+func (v *Nvim) ClearAutocmds(ctx context.Context, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_clear_autocmds", nil, opts)
+}
+
+// Cmd calls nvim_cmd.
+//
+// Since API level 10.
+func (v *Nvim) Cmd(ctx context.Context, cmd map[string]interface{}, opts map[string]interface{}) (string, error) {
+	var result string
+	err := v.Call(ctx, "nvim_cmd", &result, cmd, opts)
+	return result, err
+}
+
+// Command calls nvim_command.
+//
+// Since API level 1.
+func (v *Nvim) Command(ctx context.Context, command string) error {
+	return v.Call(ctx, "nvim_command", nil, command)
+}
+
+// CommandOutput calls nvim_command_output.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 7.
+func (v *Nvim) CommandOutput(ctx context.Context, command string) (string, error) {
+	var result string
+	err := v.Call(ctx, "nvim_command_output", &result, command)
+	return result, err
+}
+
+// CreateAugroup calls nvim_create_augroup.
+//
+// Since API level 9.
+func (v *Nvim) CreateAugroup(ctx context.Context, name string, opts map[string]interface{}) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_create_augroup", &result, name, opts)
+	return result, err
+}
+
+// CreateAutocmd calls nvim_create_autocmd.
+//
+// Since API level 9.
+func (v *Nvim) CreateAutocmd(ctx context.Context, event interface{}, opts map[string]interface{}) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_create_autocmd", &result, event, opts)
+	return result, err
+}
+
+// CreateBuf calls nvim_create_buf.
+//
+// Since API level 6.
+func (v *Nvim) CreateBuf(ctx context.Context, listed bool, scratch bool) (types.Buffer, error) {
+	var result types.Buffer
+	err := v.Call(ctx, "nvim_create_buf", &result, listed, scratch)
+	return result, err
+}
+
+// CreateNamespace calls nvim_create_namespace.
+//
+// Since API level 5.
+func (v *Nvim) CreateNamespace(ctx context.Context, name string) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_create_namespace", &result, name)
+	return result, err
+}
+
+// CreateUserCommand calls nvim_create_user_command.
+//
+// Since API level 9.
+func (v *Nvim) CreateUserCommand(ctx context.Context, name string, command interface{}, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_create_user_command", nil, name, command, opts)
+}
+
+// DelAugroupByID calls nvim_del_augroup_by_id.
+//
+// Since API level 9.
+func (v *Nvim) DelAugroupByID(ctx context.Context, id int) error {
+	return v.Call(ctx, "nvim_del_augroup_by_id", nil, id)
+}
+
+// DelAugroupByName calls nvim_del_augroup_by_name.
+//
+// Since API level 9.
+func (v *Nvim) DelAugroupByName(ctx context.Context, name string) error {
+	return v.Call(ctx, "nvim_del_augroup_by_name", nil, name)
+}
+
+// DelAutocmd calls nvim_del_autocmd.
+//
+// Since API level 9.
+func (v *Nvim) DelAutocmd(ctx context.Context, id int) error {
+	return v.Call(ctx, "nvim_del_autocmd", nil, id)
+}
+
+// DelCurrentLine calls nvim_del_current_line.
+//
+// Since API level 1.
+func (v *Nvim) DelCurrentLine(ctx context.Context) error {
+	return v.Call(ctx, "nvim_del_current_line", nil)
+}
+
+// DelKeymap calls nvim_del_keymap.
+//
+// Since API level 6.
+func (v *Nvim) DelKeymap(ctx context.Context, mode string, lhs string) error {
+	return v.Call(ctx, "nvim_del_keymap", nil, mode, lhs)
+}
+
+// DelUserCommand calls nvim_del_user_command.
+//
+// Since API level 9.
+func (v *Nvim) DelUserCommand(ctx context.Context, name string) error {
+	return v.Call(ctx, "nvim_del_user_command", nil, name)
+}
+
+// DelVar calls nvim_del_var.
+//
+// Since API level 1.
+func (v *Nvim) DelVar(ctx context.Context, name string) error {
+	return v.Call(ctx, "nvim_del_var", nil, name)
+}
+
+// Echo calls nvim_echo.
+//
+// Since API level 7.
+func (v *Nvim) Echo(ctx context.Context, chunks []interface{}, history bool, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_echo", nil, chunks, history, opts)
+}
+
+// ErrWrite calls nvim_err_write.
+//
+// Since API level 1.
+func (v *Nvim) ErrWrite(ctx context.Context, str string) error {
+	return v.Call(ctx, "nvim_err_write", nil, str)
+}
+
+// ErrWriteln calls nvim_err_writeln.
+//
+// Since API level 1.
+func (v *Nvim) ErrWriteln(ctx context.Context, str string) error {
+	return v.Call(ctx, "nvim_err_writeln", nil, str)
+}
+
+// Eval calls nvim_eval.
+//
+// Since API level 1.
+func (v *Nvim) Eval(ctx context.Context, expr string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_eval", &result, expr)
+	return result, err
+}
+
+// EvalStatusline calls nvim_eval_statusline.
+//
+// Since API level 8.
+func (v *Nvim) EvalStatusline(ctx context.Context, str string, opts map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_eval_statusline", &result, str, opts)
+	return result, err
+}
+
+// Exec calls nvim_exec.
+//
+// Since API level 7.
+//
+// Deprecated: deprecated since API level 11.
+func (v *Nvim) Exec(ctx context.Context, src string, output bool) (string, error) {
+	var result string
+	err := v.Call(ctx, "nvim_exec", &result, src, output)
+	return result, err
+}
+
+// Exec2 calls nvim_exec2.
+//
+// Since API level 11.
+func (v *Nvim) Exec2(ctx context.Context, src string, opts map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_exec2", &result, src, opts)
+	return result, err
+}
+
+// ExecAutocmds calls nvim_exec_autocmds.
+//
+// Since API level 9.
+func (v *Nvim) ExecAutocmds(ctx context.Context, event interface{}, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_exec_autocmds", nil, event, opts)
+}
+
+// ExecLua calls nvim_exec_lua.
+//
+// Since API level 7.
+func (v *Nvim) ExecLua(ctx context.Context, code string, args []interface{}) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_exec_lua", &result, code, args)
+	return result, err
+}
+
+// Feedkeys calls nvim_feedkeys.
+//
+// Since API level 1.
+func (v *Nvim) Feedkeys(ctx context.Context, keys string, mode string, escapeKs bool) error {
+	return v.Call(ctx, "nvim_feedkeys", nil, keys, mode, escapeKs)
+}
+
+// GetAPIInfo calls nvim_get_api_info.
+//
+// Since API level 1.
+func (v *Nvim) GetAPIInfo(ctx context.Context) ([]interface{}, error) {
+	var result []interface{}
+	err := v.Call(ctx, "nvim_get_api_info", &result)
+	return result, err
+}
+
+// GetAutocmds calls nvim_get_autocmds.
+//
+// Since API level 9.
+func (v *Nvim) GetAutocmds(ctx context.Context, opts map[string]interface{}) ([]interface{}, error) {
+	var result []interface{}
+	err := v.Call(ctx, "nvim_get_autocmds", &result, opts)
+	return result, err
+}
+
+// GetChanInfo calls nvim_get_chan_info.
+//
+// Since API level 4.
+func (v *Nvim) GetChanInfo(ctx context.Context, chan_ int) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_get_chan_info", &result, chan_)
+	return result, err
+}
+
+// GetColorByName calls nvim_get_color_by_name.
+//
+// Since API level 1.
+func (v *Nvim) GetColorByName(ctx context.Context, name string) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_get_color_by_name", &result, name)
+	return result, err
+}
+
+// GetCurrentBuf calls nvim_get_current_buf.
+//
+// Since API level 1.
+func (v *Nvim) GetCurrentBuf(ctx context.Context) (types.Buffer, error) {
+	var result types.Buffer
+	err := v.Call(ctx, "nvim_get_current_buf", &result)
+	return result, err
+}
+
+// GetCurrentLine calls nvim_get_current_line.
+//
+// Since API level 1.
+func (v *Nvim) GetCurrentLine(ctx context.Context) (string, error) {
+	var result string
+	err := v.Call(ctx, "nvim_get_current_line", &result)
+	return result, err
+}
+
+// GetCurrentTabpage calls nvim_get_current_tabpage.
+//
+// Since API level 1.
+func (v *Nvim) GetCurrentTabpage(ctx context.Context) (types.Tabpage, error) {
+	var result types.Tabpage
+	err := v.Call(ctx, "nvim_get_current_tabpage", &result)
+	return result, err
+}
+
+// GetCurrentWin calls nvim_get_current_win.
+//
+// Since API level 1.
+func (v *Nvim) GetCurrentWin(ctx context.Context) (types.Window, error) {
+	var result types.Window
+	err := v.Call(ctx, "nvim_get_current_win", &result)
+	return result, err
+}
+
+// GetHlIDByName calls nvim_get_hl_id_by_name.
+//
+// Since API level 7.
+func (v *Nvim) GetHlIDByName(ctx context.Context, name string) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_get_hl_id_by_name", &result, name)
+	return result, err
+}
+
+// GetKeymap calls nvim_get_keymap.
+//
+// Since API level 3.
+func (v *Nvim) GetKeymap(ctx context.Context, mode string) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+	err := v.Call(ctx, "nvim_get_keymap", &result, mode)
+	return result, err
+}
+
+// GetMode calls nvim_get_mode.
+//
+// Since API level 2.
+func (v *Nvim) GetMode(ctx context.Context) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_get_mode", &result)
+	return result, err
+}
+
+// GetNamespaces calls nvim_get_namespaces.
+//
+// Since API level 5.
+func (v *Nvim) GetNamespaces(ctx context.Context) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_get_namespaces", &result)
+	return result, err
+}
+
+// GetOption calls nvim_get_option.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 11.
+func (v *Nvim) GetOption(ctx context.Context, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_get_option", &result, name)
+	return result, err
+}
+
+// GetOptionValue calls nvim_get_option_value.
+//
+// Since API level 10.
+func (v *Nvim) GetOptionValue(ctx context.Context, name string, opts map[string]interface{}) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_get_option_value", &result, name, opts)
+	return result, err
+}
+
+// GetProc calls nvim_get_proc.
+//
+// Since API level 4.
+func (v *Nvim) GetProc(ctx context.Context, pid int) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_get_proc", &result, pid)
+	return result, err
+}
+
+// GetVar calls nvim_get_var.
+//
+// Since API level 1.
+func (v *Nvim) GetVar(ctx context.Context, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_get_var", &result, name)
+	return result, err
+}
+
+// GetVvar calls nvim_get_vvar.
+//
+// Since API level 1.
+func (v *Nvim) GetVvar(ctx context.Context, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_get_vvar", &result, name)
+	return result, err
+}
+
+// Input calls nvim_input.
+//
+// Since API level 1.
+func (v *Nvim) Input(ctx context.Context, keys string) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_input", &result, keys)
+	return result, err
+}
+
+// InputMouse calls nvim_input_mouse.
+//
+// Since API level 6.
+func (v *Nvim) InputMouse(ctx context.Context, button string, action string, modifier string, grid int, row int, col int) error {
+	return v.Call(ctx, "nvim_input_mouse", nil, button, action, modifier, grid, row, col)
+}
+
+// ListBufs calls nvim_list_bufs.
+//
+// Since API level 1.
+func (v *Nvim) ListBufs(ctx context.Context) ([]types.Buffer, error) {
+	var result []types.Buffer
+	err := v.Call(ctx, "nvim_list_bufs", &result)
+	return result, err
+}
+
+// ListChans calls nvim_list_chans.
+//
+// Since API level 4.
+func (v *Nvim) ListChans(ctx context.Context) ([]interface{}, error) {
+	var result []interface{}
+	err := v.Call(ctx, "nvim_list_chans", &result)
+	return result, err
+}
+
+// ListRuntimePaths calls nvim_list_runtime_paths.
+//
+// Since API level 1.
+func (v *Nvim) ListRuntimePaths(ctx context.Context) ([]string, error) {
+	var result []string
+	err := v.Call(ctx, "nvim_list_runtime_paths", &result)
+	return result, err
+}
+
+// ListTabpages calls nvim_list_tabpages.
+//
+// Since API level 1.
+func (v *Nvim) ListTabpages(ctx context.Context) ([]types.Tabpage, error) {
+	var result []types.Tabpage
+	err := v.Call(ctx, "nvim_list_tabpages", &result)
+	return result, err
+}
+
+// ListUis calls nvim_list_uis.
+//
+// Since API level 4.
+func (v *Nvim) ListUis(ctx context.Context) ([]interface{}, error) {
+	var result []interface{}
+	err := v.Call(ctx, "nvim_list_uis", &result)
+	return result, err
+}
+
+// ListWins calls nvim_list_wins.
+//
+// Since API level 1.
+func (v *Nvim) ListWins(ctx context.Context) ([]types.Window, error) {
+	var result []types.Window
+	err := v.Call(ctx, "nvim_list_wins", &result)
+	return result, err
+}
+
+// NotifyUser calls nvim_notify.
+//
+// Since API level 7.
+func (v *Nvim) NotifyUser(ctx context.Context, msg string, logLevel int, opts map[string]interface{}) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_notify", &result, msg, logLevel, opts)
+	return result, err
+}
+
+// OpenWin calls nvim_open_win.
+//
+// Since API level 6.
+func (v *Nvim) OpenWin(ctx context.Context, buffer types.Buffer, enter bool, config map[string]interface{}) (types.Window, error) {
+	var result types.Window
+	err := v.Call(ctx, "nvim_open_win", &result, buffer, enter, config)
+	return result, err
+}
+
+// OutWrite calls nvim_out_write.
+//
+// Since API level 1.
+func (v *Nvim) OutWrite(ctx context.Context, str string) error {
+	return v.Call(ctx, "nvim_out_write", nil, str)
+}
+
+// ParseCmd calls nvim_parse_cmd.
+//
+// Since API level 10.
+func (v *Nvim) ParseCmd(ctx context.Context, str string, opts map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_parse_cmd", &result, str, opts)
+	return result, err
+}
+
+// Paste calls nvim_paste.
+//
+// Since API level 6.
+func (v *Nvim) Paste(ctx context.Context, data string, crlf bool, phase int) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_paste", &result, data, crlf, phase)
+	return result, err
+}
+
+// Put calls nvim_put.
+//
+// Since API level 6.
+func (v *Nvim) Put(ctx context.Context, lines []string, type_ string, after bool, follow bool) error {
+	return v.Call(ctx, "nvim_put", nil, lines, type_, after, follow)
+}
+
+// ReplaceTermcodes calls nvim_replace_termcodes.
+//
+// Since API level 1.
+func (v *Nvim) ReplaceTermcodes(ctx context.Context, str string, fromPart bool, doLt bool, special bool) (string, error) {
+	var result string
+	err := v.Call(ctx, "nvim_replace_termcodes", &result, str, fromPart, doLt, special)
+	return result, err
+}
+
+// SetClientInfo calls nvim_set_client_info.
+//
+// Since API level 4.
+func (v *Nvim) SetClientInfo(ctx context.Context, name string, version map[string]interface{}, type_ string, methods map[string]interface{}, attributes map[string]interface{}) error {
+	return v.Call(ctx, "nvim_set_client_info", nil, name, version, type_, methods, attributes)
+}
+
+// SetCurrentBuf calls nvim_set_current_buf.
+//
+// Since API level 1.
+func (v *Nvim) SetCurrentBuf(ctx context.Context, buffer types.Buffer) error {
+	return v.Call(ctx, "nvim_set_current_buf", nil, buffer)
+}
+
+// SetCurrentDir calls nvim_set_current_dir.
+//
+// Since API level 1.
+func (v *Nvim) SetCurrentDir(ctx context.Context, dir string) error {
+	return v.Call(ctx, "nvim_set_current_dir", nil, dir)
+}
+
+// SetCurrentLine calls nvim_set_current_line.
+//
+// Since API level 1.
+func (v *Nvim) SetCurrentLine(ctx context.Context, line string) error {
+	return v.Call(ctx, "nvim_set_current_line", nil, line)
+}
+
+// SetCurrentTabpage calls nvim_set_current_tabpage.
+//
+// Since API level 1.
+func (v *Nvim) SetCurrentTabpage(ctx context.Context, tabpage types.Tabpage) error {
+	return v.Call(ctx, "nvim_set_current_tabpage", nil, tabpage)
+}
+
+// SetCurrentWin calls nvim_set_current_win.
+//
+// Since API level 1.
+func (v *Nvim) SetCurrentWin(ctx context.Context, window types.Window) error {
+	return v.Call(ctx, "nvim_set_current_win", nil, window)
+}
+
+// SetHl calls nvim_set_hl.
+//
+// Since API level 7.
+func (v *Nvim) SetHl(ctx context.Context, nsID int, name string, val map[string]interface{}) error {
+	return v.Call(ctx, "nvim_set_hl", nil, nsID, name, val)
+}
+
+// SetKeymap calls nvim_set_keymap.
+//
+// Since API level 6.
+func (v *Nvim) SetKeymap(ctx context.Context, mode string, lhs string, rhs string, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_set_keymap", nil, mode, lhs, rhs, opts)
+}
+
+// SetOption calls nvim_set_option.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 11.
+func (v *Nvim) SetOption(ctx context.Context, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_set_option", nil, name, value)
+}
+
+// SetOptionValue calls nvim_set_option_value.
+//
+// Since API level 10.
+func (v *Nvim) SetOptionValue(ctx context.Context, name string, value interface{}, opts map[string]interface{}) error {
+	return v.Call(ctx, "nvim_set_option_value", nil, name, value, opts)
+}
+
+// SetVar calls nvim_set_var.
+//
+// Since API level 1.
+func (v *Nvim) SetVar(ctx context.Context, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_set_var", nil, name, value)
+}
+
+// SetVvar calls nvim_set_vvar.
+//
+// Since API level 6.
+func (v *Nvim) SetVvar(ctx context.Context, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_set_vvar", nil, name, value)
+}
+
+// Strwidth calls nvim_strwidth.
+//
+// Since API level 1.
+func (v *Nvim) Strwidth(ctx context.Context, text string) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_strwidth", &result, text)
+	return result, err
+}
+
+// SubscribeEvent calls nvim_subscribe.
+//
+// Since API level 1.
+func (v *Nvim) SubscribeEvent(ctx context.Context, event string) error {
+	return v.Call(ctx, "nvim_subscribe", nil, event)
+}
+
+// TabpageDelVar calls nvim_tabpage_del_var.
+//
+// Since API level 1.
+func (v *Nvim) TabpageDelVar(ctx context.Context, tabpage types.Tabpage, name string) error {
+	return v.Call(ctx, "nvim_tabpage_del_var", nil, tabpage, name)
+}
+
+// TabpageGetNumber calls nvim_tabpage_get_number.
+//
+// Since API level 1.
+func (v *Nvim) TabpageGetNumber(ctx context.Context, tabpage types.Tabpage) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_tabpage_get_number", &result, tabpage)
+	return result, err
+}
+
+// TabpageGetVar calls nvim_tabpage_get_var.
+//
+// Since API level 1.
+func (v *Nvim) TabpageGetVar(ctx context.Context, tabpage types.Tabpage, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_tabpage_get_var", &result, tabpage, name)
+	return result, err
+}
+
+// TabpageGetWin calls nvim_tabpage_get_win.
+//
+// Since API level 1.
+func (v *Nvim) TabpageGetWin(ctx context.Context, tabpage types.Tabpage) (types.Window, error) {
+	var result types.Window
+	err := v.Call(ctx, "nvim_tabpage_get_win", &result, tabpage)
+	return result, err
+}
+
+// TabpageIsValid calls nvim_tabpage_is_valid.
+//
+// Since API level 1.
+func (v *Nvim) TabpageIsValid(ctx context.Context, tabpage types.Tabpage) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_tabpage_is_valid", &result, tabpage)
+	return result, err
+}
+
+// TabpageListWins calls nvim_tabpage_list_wins.
+//
+// Since API level 1.
+func (v *Nvim) TabpageListWins(ctx context.Context, tabpage types.Tabpage) ([]types.Window, error) {
+	var result []types.Window
+	err := v.Call(ctx, "nvim_tabpage_list_wins", &result, tabpage)
+	return result, err
+}
+
+// TabpageSetVar calls nvim_tabpage_set_var.
+//
+// Since API level 1.
+func (v *Nvim) TabpageSetVar(ctx context.Context, tabpage types.Tabpage, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_tabpage_set_var", nil, tabpage, name, value)
+}
+
+// UnsubscribeEvent calls nvim_unsubscribe.
+//
+// Since API level 1.
+func (v *Nvim) UnsubscribeEvent(ctx context.Context, event string) error {
+	return v.Call(ctx, "nvim_unsubscribe", nil, event)
+}
+
+// WinClose calls nvim_win_close.
+//
+// Since API level 6.
+func (v *Nvim) WinClose(ctx context.Context, window types.Window, force bool) error {
+	return v.Call(ctx, "nvim_win_close", nil, window, force)
+}
+
+// WinDelVar calls nvim_win_del_var.
+//
+// Since API level 1.
+func (v *Nvim) WinDelVar(ctx context.Context, window types.Window, name string) error {
+	return v.Call(ctx, "nvim_win_del_var", nil, window, name)
+}
+
+// WinGetBuf calls nvim_win_get_buf.
+//
+// Since API level 1.
+func (v *Nvim) WinGetBuf(ctx context.Context, window types.Window) (types.Buffer, error) {
+	var result types.Buffer
+	err := v.Call(ctx, "nvim_win_get_buf", &result, window)
+	return result, err
+}
+
+// WinGetConfig calls nvim_win_get_config.
+//
+// Since API level 6.
+func (v *Nvim) WinGetConfig(ctx context.Context, window types.Window) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := v.Call(ctx, "nvim_win_get_config", &result, window)
+	return result, err
+}
+
+// WinGetCursor calls nvim_win_get_cursor.
+//
+// Since API level 1.
+func (v *Nvim) WinGetCursor(ctx context.Context, window types.Window) ([2]int, error) {
+	var result [2]int
+	err := v.Call(ctx, "nvim_win_get_cursor", &result, window)
+	return result, err
+}
+
+// WinGetHeight calls nvim_win_get_height.
+//
+// Since API level 1.
+func (v *Nvim) WinGetHeight(ctx context.Context, window types.Window) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_win_get_height", &result, window)
+	return result, err
+}
+
+// WinGetNumber calls nvim_win_get_number.
+//
+// Since API level 1.
+func (v *Nvim) WinGetNumber(ctx context.Context, window types.Window) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_win_get_number", &result, window)
+	return result, err
+}
+
+// WinGetOption calls nvim_win_get_option.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 10.
+func (v *Nvim) WinGetOption(ctx context.Context, window types.Window, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_win_get_option", &result, window, name)
+	return result, err
+}
+
+// WinGetPosition calls nvim_win_get_position.
+//
+// Since API level 1.
+func (v *Nvim) WinGetPosition(ctx context.Context, window types.Window) ([2]int, error) {
+	var result [2]int
+	err := v.Call(ctx, "nvim_win_get_position", &result, window)
+	return result, err
+}
+
+// WinGetTabpage calls nvim_win_get_tabpage.
+//
+// Since API level 1.
+func (v *Nvim) WinGetTabpage(ctx context.Context, window types.Window) (types.Tabpage, error) {
+	var result types.Tabpage
+	err := v.Call(ctx, "nvim_win_get_tabpage", &result, window)
+	return result, err
+}
+
+// WinGetVar calls nvim_win_get_var.
+//
+// Since API level 1.
+func (v *Nvim) WinGetVar(ctx context.Context, window types.Window, name string) (interface{}, error) {
+	var result interface{}
+	err := v.Call(ctx, "nvim_win_get_var", &result, window, name)
+	return result, err
+}
+
+// WinGetWidth calls nvim_win_get_width.
+//
+// Since API level 1.
+func (v *Nvim) WinGetWidth(ctx context.Context, window types.Window) (int, error) {
+	var result int
+	err := v.Call(ctx, "nvim_win_get_width", &result, window)
+	return result, err
+}
+
+// WinHide calls nvim_win_hide.
+//
+// Since API level 7.
+func (v *Nvim) WinHide(ctx context.Context, window types.Window) error {
+	return v.Call(ctx, "nvim_win_hide", nil, window)
+}
+
+// WinIsValid calls nvim_win_is_valid.
+//
+// Since API level 1.
+func (v *Nvim) WinIsValid(ctx context.Context, window types.Window) (bool, error) {
+	var result bool
+	err := v.Call(ctx, "nvim_win_is_valid", &result, window)
+	return result, err
+}
+
+// WinSetBuf calls nvim_win_set_buf.
+//
+// Since API level 5.
+func (v *Nvim) WinSetBuf(ctx context.Context, window types.Window, buffer types.Buffer) error {
+	return v.Call(ctx, "nvim_win_set_buf", nil, window, buffer)
+}
+
+// WinSetConfig calls nvim_win_set_config.
+//
+// Since API level 6.
+func (v *Nvim) WinSetConfig(ctx context.Context, window types.Window, config map[string]interface{}) error {
+	return v.Call(ctx, "nvim_win_set_config", nil, window, config)
+}
+
+// WinSetCursor calls nvim_win_set_cursor.
+//
+// Since API level 1.
+func (v *Nvim) WinSetCursor(ctx context.Context, window types.Window, pos [2]int) error {
+	return v.Call(ctx, "nvim_win_set_cursor", nil, window, pos)
+}
+
+// WinSetHeight calls nvim_win_set_height.
+//
+// Since API level 1.
+func (v *Nvim) WinSetHeight(ctx context.Context, window types.Window, height int) error {
+	return v.Call(ctx, "nvim_win_set_height", nil, window, height)
+}
+
+// WinSetOption calls nvim_win_set_option.
+//
+// Since API level 1.
+//
+// Deprecated: deprecated since API level 10.
+func (v *Nvim) WinSetOption(ctx context.Context, window types.Window, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_win_set_option", nil, window, name, value)
+}
+
+// WinSetVar calls nvim_win_set_var.
+//
+// Since API level 1.
+func (v *Nvim) WinSetVar(ctx context.Context, window types.Window, name string, value interface{}) error {
+	return v.Call(ctx, "nvim_win_set_var", nil, window, name, value)
+}
+
+// WinSetWidth calls nvim_win_set_width.
+//
+// Since API level 1.
+func (v *Nvim) WinSetWidth(ctx context.Context, window types.Window, width int) error {
+	return v.Call(ctx, "nvim_win_set_width", nil, window, width)
+}
+
+// BufAddHighlight queues a call of nvim_buf_add_highlight.
+func (b *Batch) BufAddHighlight(buffer types.Buffer, nsID int, hlGroup string, line int, colStart int, colEnd int, result *int) {
+	b.Call("nvim_buf_add_highlight", result, buffer, nsID, hlGroup, line, colStart, colEnd)
+}
+
+// BufAttach queues a call of nvim_buf_attach.
+func (b *Batch) BufAttach(buffer types.Buffer, sendBuffer bool, opts map[string]interface{}, result *bool) {
+	b.Call("nvim_buf_attach", result, buffer, sendBuffer, opts)
+}
+
+// BufClearNamespace queues a call of nvim_buf_clear_namespace.
+func (b *Batch) BufClearNamespace(buffer types.Buffer, nsID int, lineStart int, lineEnd int) {
+	b.Call("nvim_buf_clear_namespace", nil, buffer, nsID, lineStart, lineEnd)
+}
+
+// BufDelExtmark queues a call of nvim_buf_del_extmark.
+func (b *Batch) BufDelExtmark(buffer types.Buffer, nsID int, id int, result *bool) {
+	b.Call("nvim_buf_del_extmark", result, buffer, nsID, id)
+}
+
+// BufDelKeymap queues a call of nvim_buf_del_keymap.
+func (b *Batch) BufDelKeymap(buffer types.Buffer, mode string, lhs string) {
+	b.Call("nvim_buf_del_keymap", nil, buffer, mode, lhs)
+}
+
+// BufDelMark queues a call of nvim_buf_del_mark.
+func (b *Batch) BufDelMark(buffer types.Buffer, name string, result *bool) {
+	b.Call("nvim_buf_del_mark", result, buffer, name)
+}
+
+// BufDelVar queues a call of nvim_buf_del_var.
+func (b *Batch) BufDelVar(buffer types.Buffer, name string) {
+	b.Call("nvim_buf_del_var", nil, buffer, name)
+}
+
+// BufDelete queues a call of nvim_buf_delete.
+func (b *Batch) BufDelete(buffer types.Buffer, opts map[string]interface{}) {
+	b.Call("nvim_buf_delete", nil, buffer, opts)
+}
+
+// BufDetach queues a call of nvim_buf_detach.
+func (b *Batch) BufDetach(buffer types.Buffer, result *bool) {
+	b.Call("nvim_buf_detach", result, buffer)
+}
+
+// BufGetChangedtick queues a call of nvim_buf_get_changedtick.
+func (b *Batch) BufGetChangedtick(buffer types.Buffer, result *int) {
+	b.Call("nvim_buf_get_changedtick", result, buffer)
+}
+
+// BufGetExtmarkByID queues a call of nvim_buf_get_extmark_by_id.
+func (b *Batch) BufGetExtmarkByID(buffer types.Buffer, nsID int, id int, opts map[string]interface{}, result *[]int) {
+	b.Call("nvim_buf_get_extmark_by_id", result, buffer, nsID, id, opts)
+}
+
+// BufGetExtmarks queues a call of nvim_buf_get_extmarks.
+func (b *Batch) BufGetExtmarks(buffer types.Buffer, nsID int, start interface{}, end interface{}, opts map[string]interface{}, result *[]interface{}) {
+	b.Call("nvim_buf_get_extmarks", result, buffer, nsID, start, end, opts)
+}
+
+// BufGetKeymap queues a call of nvim_buf_get_keymap.
+func (b *Batch) BufGetKeymap(buffer types.Buffer, mode string, result *[]map[string]interface{}) {
+	b.Call("nvim_buf_get_keymap", result, buffer, mode)
+}
+
+// BufGetLines queues a call of nvim_buf_get_lines.
+func (b *Batch) BufGetLines(buffer types.Buffer, start int, end int, strictIndexing bool, result *[]string) {
+	b.Call("nvim_buf_get_lines", result, buffer, start, end, strictIndexing)
+}
+
+// BufGetMark queues a call of nvim_buf_get_mark.
+func (b *Batch) BufGetMark(buffer types.Buffer, name string, result *[2]int) {
+	b.Call("nvim_buf_get_mark", result, buffer, name)
+}
+
+// BufGetName queues a call of nvim_buf_get_name.
+func (b *Batch) BufGetName(buffer types.Buffer, result *string) {
+	b.Call("nvim_buf_get_name", result, buffer)
+}
+
+// BufGetOffset queues a call of nvim_buf_get_offset.
+func (b *Batch) BufGetOffset(buffer types.Buffer, index int, result *int) {
+	b.Call("nvim_buf_get_offset", result, buffer, index)
+}
+
+// BufGetOption queues a call of nvim_buf_get_option.
+//
+// Deprecated: deprecated since API level 10.
+func (b *Batch) BufGetOption(buffer types.Buffer, name string, result *interface{}) {
+	b.Call("nvim_buf_get_option", result, buffer, name)
+}
+
+// BufGetText queues a call of nvim_buf_get_text.
+func (b *Batch) BufGetText(buffer types.Buffer, startRow int, startCol int, endRow int, endCol int, opts map[string]interface{}, result *[]string) {
+	b.Call("nvim_buf_get_text", result, buffer, startRow, startCol, endRow, endCol, opts)
+}
+
+// BufGetVar queues a call of nvim_buf_get_var.
+func (b *Batch) BufGetVar(buffer types.Buffer, name string, result *interface{}) {
+	b.Call("nvim_buf_get_var", result, buffer, name)
+}
+
+// BufIsLoaded queues a call of nvim_buf_is_loaded.
+func (b *Batch) BufIsLoaded(buffer types.Buffer, result *bool) {
+	b.Call("nvim_buf_is_loaded", result, buffer)
+}
+
+// BufIsValid queues a call of nvim_buf_is_valid.
+func (b *Batch) BufIsValid(buffer types.Buffer, result *bool) {
+	b.Call("nvim_buf_is_valid", result, buffer)
+}
+
+// BufLineCount queues a call of nvim_buf_line_count.
+func (b *Batch) BufLineCount(buffer types.Buffer, result *int) {
+	b.Call("nvim_buf_line_count", result, buffer)
+}
+
+// BufSetExtmark queues a call of nvim_buf_set_extmark.
+func (b *Batch) BufSetExtmark(buffer types.Buffer, nsID int, line int, col int, opts map[string]interface{}, result *int) {
+	b.Call("nvim_buf_set_extmark", result, buffer, nsID, line, col, opts)
+}
+
+// BufSetKeymap queues a call of nvim_buf_set_keymap.
+func (b *Batch) BufSetKeymap(buffer types.Buffer, mode string, lhs string, rhs string, opts map[string]interface{}) {
+	b.Call("nvim_buf_set_keymap", nil, buffer, mode, lhs, rhs, opts)
+}
+
+// BufSetLines queues a call of nvim_buf_set_lines.
+func (b *Batch) BufSetLines(buffer types.Buffer, start int, end int, strictIndexing bool, replacement []string) {
+	b.Call("nvim_buf_set_lines", nil, buffer, start, end, strictIndexing, replacement)
+}
+
+// BufSetMark queues a call of nvim_buf_set_mark.
+func (b *Batch) BufSetMark(buffer types.Buffer, name string, line int, col int, opts map[string]interface{}, result *bool) {
+	b.Call("nvim_buf_set_mark", result, buffer, name, line, col, opts)
+}
+
+// BufSetName queues a call of nvim_buf_set_name.
+func (b *Batch) BufSetName(buffer types.Buffer, name string) {
+	b.Call("nvim_buf_set_name", nil, buffer, name)
+}
+
+// BufSetOption queues a call of nvim_buf_set_option.
+//
+// Deprecated: deprecated since API level 10.
+func (b *Batch) BufSetOption(buffer types.Buffer, name string, value interface{}) {
+	b.Call("nvim_buf_set_option", nil, buffer, name, value)
+}
+
+// BufSetText queues a call of nvim_buf_set_text.
+func (b *Batch) BufSetText(buffer types.Buffer, startRow int, startCol int, endRow int, endCol int, replacement []string) {
+	b.Call("nvim_buf_set_text", nil, buffer, startRow, startCol, endRow, endCol, replacement)
+}
+
+// BufSetVar queues a call of nvim_buf_set_var.
+func (b *Batch) BufSetVar(buffer types.Buffer, name string, value interface{}) {
+	b.Call("nvim_buf_set_var", nil, buffer, name, value)
+}
+
+// CallAtomic queues a call of nvim_call_atomic.
+func (b *Batch) CallAtomic(calls []interface{}, result *[]interface{}) {
+	b.Call("nvim_call_atomic", result, calls)
+}
+
+// CallFunction queues a call of nvim_call_function.
+func (b *Batch) CallFunction(fn string, args []interface{}, result *interface{}) {
+	b.Call("nvim_call_function", result, fn, args)
+}
+
+// ClearAutocmds queues a call of nvim_clear_autocmds.
+func (b *Batch) ClearAutocmds(opts map[string]interface{}) {
+	b.Call("nvim_clear_autocmds", nil, opts)
+}
+
+// Cmd queues a call of nvim_cmd.
+func (b *Batch) Cmd(cmd map[string]interface{}, opts map[string]interface{}, result *string) {
+	b.Call("nvim_cmd", result, cmd, opts)
+}
+
+// Command queues a call of nvim_command.
+func (b *Batch) Command(command string) {
+	b.Call("nvim_command", nil, command)
+}
+
+// CommandOutput queues a call of nvim_command_output.
+//
+// Deprecated: deprecated since API level 7.
+func (b *Batch) CommandOutput(command string, result *string) {
+	b.Call("nvim_command_output", result, command)
+}
+
+// CreateAugroup queues a call of nvim_create_augroup.
+func (b *Batch) CreateAugroup(name string, opts map[string]interface{}, result *int) {
+	b.Call("nvim_create_augroup", result, name, opts)
+}
+
+// CreateAutocmd queues a call of nvim_create_autocmd.
+func (b *Batch) CreateAutocmd(event interface{}, opts map[string]interface{}, result *int) {
+	b.Call("nvim_create_autocmd", result, event, opts)
+}
+
+// CreateBuf queues a call of nvim_create_buf.
+func (b *Batch) CreateBuf(listed bool, scratch bool, result *types.Buffer) {
+	b.Call("nvim_create_buf", result, listed, scratch)
+}
+
+// CreateNamespace queues a call of nvim_create_namespace.
+func (b *Batch) CreateNamespace(name string, result *int) {
+	b.Call("nvim_create_namespace", result, name)
+}
+
+// CreateUserCommand queues a call of nvim_create_user_command.
+func (b *Batch) CreateUserCommand(name string, command interface{}, opts map[string]interface{}) {
+	b.Call("nvim_create_user_command", nil, name, command, opts)
+}
+
+// DelAugroupByID queues a call of nvim_del_augroup_by_id.
+func (b *Batch) DelAugroupByID(id int) {
+	b.Call("nvim_del_augroup_by_id", nil, id)
+}
+
+// DelAugroupByName queues a call of nvim_del_augroup_by_name.
+func (b *Batch) DelAugroupByName(name string) {
+	b.Call("nvim_del_augroup_by_name", nil, name)
+}
+
+// DelAutocmd queues a call of nvim_del_autocmd.
+func (b *Batch) DelAutocmd(id int) {
+	b.Call("nvim_del_autocmd", nil, id)
+}
+
+// DelCurrentLine queues a call of nvim_del_current_line.
+func (b *Batch) DelCurrentLine() {
+	b.Call("nvim_del_current_line", nil)
+}
+
+// DelKeymap queues a call of nvim_del_keymap.
+func (b *Batch) DelKeymap(mode string, lhs string) {
+	b.Call("nvim_del_keymap", nil, mode, lhs)
+}
+
+// DelUserCommand queues a call of nvim_del_user_command.
+func (b *Batch) DelUserCommand(name string) {
+	b.Call("nvim_del_user_command", nil, name)
+}
+
+// DelVar queues a call of nvim_del_var.
+func (b *Batch) DelVar(name string) {
+	b.Call("nvim_del_var", nil, name)
+}
+
+// Echo queues a call of nvim_echo.
+func (b *Batch) Echo(chunks []interface{}, history bool, opts map[string]interface{}) {
+	b.Call("nvim_echo", nil, chunks, history, opts)
+}
+
+// ErrWrite queues a call of nvim_err_write.
+func (b *Batch) ErrWrite(str string) {
+	b.Call("nvim_err_write", nil, str)
+}
+
+// ErrWriteln queues a call of nvim_err_writeln.
+func (b *Batch) ErrWriteln(str string) {
+	b.Call("nvim_err_writeln", nil, str)
+}
+
+// Eval queues a call of nvim_eval.
+func (b *Batch) Eval(expr string, result *interface{}) {
+	b.Call("nvim_eval", result, expr)
+}
+
+// EvalStatusline queues a call of nvim_eval_statusline.
+func (b *Batch) EvalStatusline(str string, opts map[string]interface{}, result *map[string]interface{}) {
+	b.Call("nvim_eval_statusline", result, str, opts)
+}
+
+// Exec queues a call of nvim_exec.
+//
+// Deprecated: deprecated since API level 11.
+func (b *Batch) Exec(src string, output bool, result *string) {
+	b.Call("nvim_exec", result, src, output)
+}
+
+// Exec2 queues a call of nvim_exec2.
+func (b *Batch) Exec2(src string, opts map[string]interface{}, result *map[string]interface{}) {
+	b.Call("nvim_exec2", result, src, opts)
+}
+
+// ExecAutocmds queues a call of nvim_exec_autocmds.
+func (b *Batch) ExecAutocmds(event interface{}, opts map[string]interface{}) {
+	b.Call("nvim_exec_autocmds", nil, event, opts)
+}
+
+// ExecLua queues a call of nvim_exec_lua.
+func (b *Batch) ExecLua(code string, args []interface{}, result *interface{}) {
+	b.Call("nvim_exec_lua", result, code, args)
+}
+
+// Feedkeys queues a call of nvim_feedkeys.
+func (b *Batch) Feedkeys(keys string, mode string, escapeKs bool) {
+	b.Call("nvim_feedkeys", nil, keys, mode, escapeKs)
+}
+
+// GetAPIInfo queues a call of nvim_get_api_info.
+func (b *Batch) GetAPIInfo(result *[]interface{}) {
+	b.Call("nvim_get_api_info", result)
+}
+
+// GetAutocmds queues a call of nvim_get_autocmds.
+func (b *Batch) GetAutocmds(opts map[string]interface{}, result *[]interface{}) {
+	b.Call("nvim_get_autocmds", result, opts)
+}
+
+// GetChanInfo queues a call of nvim_get_chan_info.
+func (b *Batch) GetChanInfo(chan_ int, result *map[string]interface{}) {
+	b.Call("nvim_get_chan_info", result, chan_)
+}
+
+// GetColorByName queues a call of nvim_get_color_by_name.
+func (b *Batch) GetColorByName(name string, result *int) {
+	b.Call("nvim_get_color_by_name", result, name)
+}
+
+// GetCurrentBuf queues a call of nvim_get_current_buf.
+func (b *Batch) GetCurrentBuf(result *types.Buffer) {
+	b.Call("nvim_get_current_buf", result)
+}
+
+// GetCurrentLine queues a call of nvim_get_current_line.
+func (b *Batch) GetCurrentLine(result *string) {
+	b.Call("nvim_get_current_line", result)
+}
+
+// GetCurrentTabpage queues a call of nvim_get_current_tabpage.
+func (b *Batch) GetCurrentTabpage(result *types.Tabpage) {
+	b.Call("nvim_get_current_tabpage", result)
+}
+
+// GetCurrentWin queues a call of nvim_get_current_win.
+func (b *Batch) GetCurrentWin(result *types.Window) {
+	b.Call("nvim_get_current_win", result)
+}
+
+// GetHlIDByName queues a call of nvim_get_hl_id_by_name.
+func (b *Batch) GetHlIDByName(name string, result *int) {
+	b.Call("nvim_get_hl_id_by_name", result, name)
+}
+
+// GetKeymap queues a call of nvim_get_keymap.
+func (b *Batch) GetKeymap(mode string, result *[]map[string]interface{}) {
+	b.Call("nvim_get_keymap", result, mode)
+}
+
+// GetMode queues a call of nvim_get_mode.
+func (b *Batch) GetMode(result *map[string]interface{}) {
+	b.Call("nvim_get_mode", result)
+}
+
+// GetNamespaces queues a call of nvim_get_namespaces.
+func (b *Batch) GetNamespaces(result *map[string]interface{}) {
+	b.Call("nvim_get_namespaces", result)
+}
+
+// GetOption queues a call of nvim_get_option.
+//
+// Deprecated: deprecated since API level 11.
+func (b *Batch) GetOption(name string, result *interface{}) {
+	b.Call("nvim_get_option", result, name)
+}
+
+// GetOptionValue queues a call of nvim_get_option_value.
+func (b *Batch) GetOptionValue(name string, opts map[string]interface{}, result *interface{}) {
+	b.Call("nvim_get_option_value", result, name, opts)
+}
+
+// GetProc queues a call of nvim_get_proc.
+func (b *Batch) GetProc(pid int, result *interface{}) {
+	b.Call("nvim_get_proc", result, pid)
+}
+
+// GetVar queues a call of nvim_get_var.
+func (b *Batch) GetVar(name string, result *interface{}) {
+	b.Call("nvim_get_var", result, name)
+}
+
+// GetVvar queues a call of nvim_get_vvar.
+func (b *Batch) GetVvar(name string, result *interface{}) {
+	b.Call("nvim_get_vvar", result, name)
+}
+
+// Input queues a call of nvim_input.
+func (b *Batch) Input(keys string, result *int) {
+	b.Call("nvim_input", result, keys)
+}
+
+// InputMouse queues a call of nvim_input_mouse.
+func (b *Batch) InputMouse(button string, action string, modifier string, grid int, row int, col int) {
+	b.Call("nvim_input_mouse", nil, button, action, modifier, grid, row, col)
+}
+
+// ListBufs queues a call of nvim_list_bufs.
+func (b *Batch) ListBufs(result *[]types.Buffer) {
+	b.Call("nvim_list_bufs", result)
+}
+
+// ListChans queues a call of nvim_list_chans.
+func (b *Batch) ListChans(result *[]interface{}) {
+	b.Call("nvim_list_chans", result)
+}
+
+// ListRuntimePaths queues a call of nvim_list_runtime_paths.
+func (b *Batch) ListRuntimePaths(result *[]string) {
+	b.Call("nvim_list_runtime_paths", result)
+}
+
+// ListTabpages queues a call of nvim_list_tabpages.
+func (b *Batch) ListTabpages(result *[]types.Tabpage) {
+	b.Call("nvim_list_tabpages", result)
+}
+
+// ListUis queues a call of nvim_list_uis.
+func (b *Batch) ListUis(result *[]interface{}) {
+	b.Call("nvim_list_uis", result)
+}
+
+// ListWins queues a call of nvim_list_wins.
+func (b *Batch) ListWins(result *[]types.Window) {
+	b.Call("nvim_list_wins", result)
+}
+
+// NotifyUser queues a call of nvim_notify.
+func (b *Batch) NotifyUser(msg string, logLevel int, opts map[string]interface{}, result *interface{}) {
+	b.Call("nvim_notify", result, msg, logLevel, opts)
+}
+
+// OpenWin queues a call of nvim_open_win.
+func (b *Batch) OpenWin(buffer types.Buffer, enter bool, config map[string]interface{}, result *types.Window) {
+	b.Call("nvim_open_win", result, buffer, enter, config)
+}
+
+// OutWrite queues a call of nvim_out_write.
+func (b *Batch) OutWrite(str string) {
+	b.Call("nvim_out_write", nil, str)
+}
+
+// ParseCmd queues a call of nvim_parse_cmd.
+func (b *Batch) ParseCmd(str string, opts map[string]interface{}, result *map[string]interface{}) {
+	b.Call("nvim_parse_cmd", result, str, opts)
+}
+
+// Paste queues a call of nvim_paste.
+func (b *Batch) Paste(data string, crlf bool, phase int, result *bool) {
+	b.Call("nvim_paste", result, data, crlf, phase)
+}
+
+// Put queues a call of nvim_put.
+func (b *Batch) Put(lines []string, type_ string, after bool, follow bool) {
+	b.Call("nvim_put", nil, lines, type_, after, follow)
+}
+
+// ReplaceTermcodes queues a call of nvim_replace_termcodes.
+func (b *Batch) ReplaceTermcodes(str string, fromPart bool, doLt bool, special bool, result *string) {
+	b.Call("nvim_replace_termcodes", result, str, fromPart, doLt, special)
+}
+
+// SetClientInfo queues a call of nvim_set_client_info.
+func (b *Batch) SetClientInfo(name string, version map[string]interface{}, type_ string, methods map[string]interface{}, attributes map[string]interface{}) {
+	b.Call("nvim_set_client_info", nil, name, version, type_, methods, attributes)
+}
+
+// SetCurrentBuf queues a call of nvim_set_current_buf.
+func (b *Batch) SetCurrentBuf(buffer types.Buffer) {
+	b.Call("nvim_set_current_buf", nil, buffer)
+}
+
+// SetCurrentDir queues a call of nvim_set_current_dir.
+func (b *Batch) SetCurrentDir(dir string) {
+	b.Call("nvim_set_current_dir", nil, dir)
+}
+
+// SetCurrentLine queues a call of nvim_set_current_line.
+func (b *Batch) SetCurrentLine(line string) {
+	b.Call("nvim_set_current_line", nil, line)
+}
+
+// SetCurrentTabpage queues a call of nvim_set_current_tabpage.
+func (b *Batch) SetCurrentTabpage(tabpage types.Tabpage) {
+	b.Call("nvim_set_current_tabpage", nil, tabpage)
+}
+
+// SetCurrentWin queues a call of nvim_set_current_win.
+func (b *Batch) SetCurrentWin(window types.Window) {
+	b.Call("nvim_set_current_win", nil, window)
+}
+
+// SetHl queues a call of nvim_set_hl.
+func (b *Batch) SetHl(nsID int, name string, val map[string]interface{}) {
+	b.Call("nvim_set_hl", nil, nsID, name, val)
+}
+
+// SetKeymap queues a call of nvim_set_keymap.
+func (b *Batch) SetKeymap(mode string, lhs string, rhs string, opts map[string]interface{}) {
+	b.Call("nvim_set_keymap", nil, mode, lhs, rhs, opts)
+}
+
+// SetOption queues a call of nvim_set_option.
+//
+// Deprecated: deprecated since API level 11.
+func (b *Batch) SetOption(name string, value interface{}) {
+	b.Call("nvim_set_option", nil, name, value)
+}
+
+// SetOptionValue queues a call of nvim_set_option_value.
+func (b *Batch) SetOptionValue(name string, value interface{}, opts map[string]interface{}) {
+	b.Call("nvim_set_option_value", nil, name, value, opts)
+}
+
+// SetVar queues a call of nvim_set_var.
+func (b *Batch) SetVar(name string, value interface{}) {
+	b.Call("nvim_set_var", nil, name, value)
+}
+
+// SetVvar queues a call of nvim_set_vvar.
+func (b *Batch) SetVvar(name string, value interface{}) {
+	b.Call("nvim_set_vvar", nil, name, value)
+}
+
+// Strwidth queues a call of nvim_strwidth.
+func (b *Batch) Strwidth(text string, result *int) {
+	b.Call("nvim_strwidth", result, text)
+}
+
+// SubscribeEvent queues a call of nvim_subscribe.
+func (b *Batch) SubscribeEvent(event string) {
+	b.Call("nvim_subscribe", nil, event)
+}
+
+// TabpageDelVar queues a call of nvim_tabpage_del_var.
+func (b *Batch) TabpageDelVar(tabpage types.Tabpage, name string) {
+	b.Call("nvim_tabpage_del_var", nil, tabpage, name)
+}
+
+// TabpageGetNumber queues a call of nvim_tabpage_get_number.
+func (b *Batch) TabpageGetNumber(tabpage types.Tabpage, result *int) {
+	b.Call("nvim_tabpage_get_number", result, tabpage)
+}
+
+// TabpageGetVar queues a call of nvim_tabpage_get_var.
+func (b *Batch) TabpageGetVar(tabpage types.Tabpage, name string, result *interface{}) {
+	b.Call("nvim_tabpage_get_var", result, tabpage, name)
+}
+
+// TabpageGetWin queues a call of nvim_tabpage_get_win.
+func (b *Batch) TabpageGetWin(tabpage types.Tabpage, result *types.Window) {
+	b.Call("nvim_tabpage_get_win", result, tabpage)
+}
+
+// TabpageIsValid queues a call of nvim_tabpage_is_valid.
+func (b *Batch) TabpageIsValid(tabpage types.Tabpage, result *bool) {
+	b.Call("nvim_tabpage_is_valid", result, tabpage)
+}
+
+// TabpageListWins queues a call of nvim_tabpage_list_wins.
+func (b *Batch) TabpageListWins(tabpage types.Tabpage, result *[]types.Window) {
+	b.Call("nvim_tabpage_list_wins", result, tabpage)
+}
+
+// TabpageSetVar queues a call of nvim_tabpage_set_var.
+func (b *Batch) TabpageSetVar(tabpage types.Tabpage, name string, value interface{}) {
+	b.Call("nvim_tabpage_set_var", nil, tabpage, name, value)
+}
+
+// UnsubscribeEvent queues a call of nvim_unsubscribe.
+func (b *Batch) UnsubscribeEvent(event string) {
+	b.Call("nvim_unsubscribe", nil, event)
+}
+
+// WinClose queues a call of nvim_win_close.
+func (b *Batch) WinClose(window types.Window, force bool) {
+	b.Call("nvim_win_close", nil, window, force)
+}
+
+// WinDelVar queues a call of nvim_win_del_var.
+func (b *Batch) WinDelVar(window types.Window, name string) {
+	b.Call("nvim_win_del_var", nil, window, name)
+}
+
+// WinGetBuf queues a call of nvim_win_get_buf.
+func (b *Batch) WinGetBuf(window types.Window, result *types.Buffer) {
+	b.Call("nvim_win_get_buf", result, window)
+}
+
+// WinGetConfig queues a call of nvim_win_get_config.
+func (b *Batch) WinGetConfig(window types.Window, result *map[string]interface{}) {
+	b.Call("nvim_win_get_config", result, window)
+}
+
+// WinGetCursor queues a call of nvim_win_get_cursor.
+func (b *Batch) WinGetCursor(window types.Window, result *[2]int) {
+	b.Call("nvim_win_get_cursor", result, window)
+}
+
+// WinGetHeight queues a call of nvim_win_get_height.
+func (b *Batch) WinGetHeight(window types.Window, result *int) {
+	b.Call("nvim_win_get_height", result, window)
+}
+
+// WinGetNumber queues a call of nvim_win_get_number.
+func (b *Batch) WinGetNumber(window types.Window, result *int) {
+	b.Call("nvim_win_get_number", result, window)
+}
+
+// WinGetOption queues a call of nvim_win_get_option.
+//
+// Deprecated: deprecated since API level 10.
+func (b *Batch) WinGetOption(window types.Window, name string, result *interface{}) {
+	b.Call("nvim_win_get_option", result, window, name)
+}
+
+// WinGetPosition queues a call of nvim_win_get_position.
+func (b *Batch) WinGetPosition(window types.Window, result *[2]int) {
+	b.Call("nvim_win_get_position", result, window)
+}
+
+// WinGetTabpage queues a call of nvim_win_get_tabpage.
+func (b *Batch) WinGetTabpage(window types.Window, result *types.Tabpage) {
+	b.Call("nvim_win_get_tabpage", result, window)
+}
+
+// WinGetVar queues a call of nvim_win_get_var.
+func (b *Batch) WinGetVar(window types.Window, name string, result *interface{}) {
+	b.Call("nvim_win_get_var", result, window, name)
+}
+
+// WinGetWidth queues a call of nvim_win_get_width.
+func (b *Batch) WinGetWidth(window types.Window, result *int) {
+	b.Call("nvim_win_get_width", result, window)
+}
+
+// WinHide queues a call of nvim_win_hide.
+func (b *Batch) WinHide(window types.Window) {
+	b.Call("nvim_win_hide", nil, window)
+}
+
+// WinIsValid queues a call of nvim_win_is_valid.
+func (b *Batch) WinIsValid(window types.Window, result *bool) {
+	b.Call("nvim_win_is_valid", result, window)
+}
+
+// WinSetBuf queues a call of nvim_win_set_buf.
+func (b *Batch) WinSetBuf(window types.Window, buffer types.Buffer) {
+	b.Call("nvim_win_set_buf", nil, window, buffer)
+}
+
+// WinSetConfig queues a call of nvim_win_set_config.
+func (b *Batch) WinSetConfig(window types.Window, config map[string]interface{}) {
+	b.Call("nvim_win_set_config", nil, window, config)
+}
+
+// WinSetCursor queues a call of nvim_win_set_cursor.
+func (b *Batch) WinSetCursor(window types.Window, pos [2]int) {
+	b.Call("nvim_win_set_cursor", nil, window, pos)
+}
+
+// WinSetHeight queues a call of nvim_win_set_height.
+func (b *Batch) WinSetHeight(window types.Window, height int) {
+	b.Call("nvim_win_set_height", nil, window, height)
+}
+
+// WinSetOption queues a call of nvim_win_set_option.
+//
+// Deprecated: deprecated since API level 10.
+func (b *Batch) WinSetOption(window types.Window, name string, value interface{}) {
+	b.Call("nvim_win_set_option", nil, window, name, value)
+}
+
+// WinSetVar queues a call of nvim_win_set_var.
+func (b *Batch) WinSetVar(window types.Window, name string, value interface{}) {
+	b.Call("nvim_win_set_var", nil, window, name, value)
+}
+
+// WinSetWidth queues a call of nvim_win_set_width.
+func (b *Batch) WinSetWidth(window types.Window, width int) {
+	b.Call("nvim_win_set_width", nil, window, width)
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-nvim/pkg/msgpack"
+	"github.com/go-nvim/pkg/rpc"
+)
+
+// Batch queues API calls to execute them in a single round trip, with
+// nvim_call_atomic.
+//
+// The calls are executed in order without other requests interleaving, but
+// not transactionally: execution stops at the first failing call, and the
+// calls before it are not rolled back.
+//
+// Results are stored when the batch is executed. A Batch is not safe for
+// concurrent use.
+type Batch struct {
+	v     *Nvim
+	calls []batchCall
+}
+
+type batchCall struct {
+	method string
+	args   []interface{}
+	result interface{}
+}
+
+// NewBatch returns a new empty Batch executed with v.
+func (v *Nvim) NewBatch() *Batch {
+	return &Batch{v: v}
+}
+
+// Call queues a call of the API method with args. The result is stored in
+// the value pointed to by result when the batch is executed; a nil result
+// discards it, including a nil pointer such as (*[]string)(nil).
+func (b *Batch) Call(method string, result interface{}, args ...interface{}) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	if rv := reflect.ValueOf(result); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		result = nil
+	}
+	b.calls = append(b.calls, batchCall{method: method, args: args, result: result})
+}
+
+// Len returns the number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// BatchError is returned by Batch.Execute when a call fails. The calls
+// before it were executed and their results stored; the calls after it
+// were not executed.
+type BatchError struct {
+	Index  int    // index of the failed call
+	Method string // method of the failed call
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("nvim: batch call %d (%s): %v", e.Index, e.Method, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Execute executes the queued calls and empties the batch.
+func (b *Batch) Execute(ctx context.Context) error {
+	calls := b.calls
+	b.calls = nil
+	if len(calls) == 0 {
+		return nil
+	}
+
+	req := make([]interface{}, len(calls))
+	for i, c := range calls {
+		req[i] = []interface{}{c.method, c.args}
+	}
+
+	// nvim_call_atomic returns the results of the executed calls, and nil
+	// or the [index, type, message] of the failed call.
+	var resp struct {
+		Results []msgpack.RawMessage
+		Err     []interface{}
+	}
+	if err := b.v.Call(ctx, "nvim_call_atomic", &resp, req); err != nil {
+		return err
+	}
+
+	for i, raw := range resp.Results {
+		if i >= len(calls) {
+			break
+		}
+		c := calls[i]
+		if c.result == nil {
+			continue
+		}
+		if err := msgpack.Unmarshal(raw, c.result); err != nil {
+			return &BatchError{Index: i, Method: c.method, Err: err}
+		}
+	}
+
+	if resp.Err == nil {
+		return nil
+	}
+	if len(resp.Err) != 3 {
+		return fmt.Errorf("nvim: invalid nvim_call_atomic error %v", resp.Err)
+	}
+	index, _ := resp.Err[0].(int64)
+	typ, _ := resp.Err[1].(int64)
+	msg, _ := resp.Err[2].(string)
+	e := &BatchError{Index: int(index), Err: &rpc.Error{Type: rpc.ErrorType(typ), Message: msg}}
+	if e.Index >= 0 && e.Index < len(calls) {
+		e.Method = calls[e.Index].method
+	}
+
+	return e
+}
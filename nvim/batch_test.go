@@ -0,0 +1,58 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/types"
+)
+
+func TestBatch(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_get_current_line", "line")
+	s.Return("nvim_list_bufs", []interface{}{types.Buffer(1), types.Buffer(3)})
+	s.Return("nvim_buf_get_lines", []string{"a", "b"})
+
+	b := v.NewBatch()
+	var line string
+	var bufs []types.Buffer
+	b.GetCurrentLine(&line)
+	b.ListBufs(&bufs)
+	b.BufGetLines(1, 0, -1, true, nil) // typed nil result
+	if b.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", b.Len())
+	}
+	if err := b.Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if line != "line" {
+		t.Errorf("line = %q, want %q", line, "line")
+	}
+	if want := []types.Buffer{1, 3}; !reflect.DeepEqual(bufs, want) {
+		t.Errorf("bufs = %v, want %v", bufs, want)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Len() after Execute = %d, want 0", b.Len())
+	}
+	s.ExpectCall(t, "nvim_call_atomic", []interface{}{
+		[]interface{}{"nvim_get_current_line", []interface{}{}},
+		[]interface{}{"nvim_list_bufs", []interface{}{}},
+		[]interface{}{"nvim_buf_get_lines", []interface{}{types.Buffer(1), 0, -1, true}},
+	})
+}
+
+func TestBatchEmpty(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	if err := v.NewBatch().Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Errorf("empty batch made calls %v", calls)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChannelID returns the ID of the channel of v in Neovim, as used by
+// rpcnotify() and rpcrequest(). It is looked up with nvim_get_api_info
+// once, then cached.
+func (v *Nvim) ChannelID(ctx context.Context) (int, error) {
+	v.channel.Lock()
+	id := v.channel.id
+	v.channel.Unlock()
+	if id != 0 {
+		return id, nil
+	}
+
+	info, err := v.GetAPIInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(info) == 0 {
+		return 0, fmt.Errorf("nvim: unexpected nvim_get_api_info result")
+	}
+	id = toInt(info[0])
+	v.channel.Lock()
+	v.channel.id = id
+	v.channel.Unlock()
+
+	return id, nil
+}
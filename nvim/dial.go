@@ -0,0 +1,84 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNoAddress is returned by DialCurrent when the process does not run
+// inside Neovim.
+var ErrNoAddress = errors.New("nvim: $NVIM and $NVIM_LISTEN_ADDRESS are not set")
+
+// Dial connects to the Neovim instance listening on addr.
+//
+// The address is one of:
+//
+//   - tcp:host:port, or host:port, for a TCP socket
+//   - \\.\pipe\name for a Windows named pipe
+//   - the path of a Unix domain socket otherwise
+func Dial(ctx context.Context, addr string) (*Nvim, error) {
+	network, address := parseAddr(addr)
+
+	var conn io.ReadWriteCloser
+	var err error
+	if network == "pipe" {
+		conn, err = dialPipe(ctx, address)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, network, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return New(conn), nil
+}
+
+// DialCurrent connects to the Neovim instance the process runs in, as given
+// by $NVIM, or by $NVIM_LISTEN_ADDRESS for versions before 0.7.
+func DialCurrent(ctx context.Context) (*Nvim, error) {
+	addr := os.Getenv("NVIM")
+	if addr == "" {
+		addr = os.Getenv("NVIM_LISTEN_ADDRESS")
+	}
+	if addr == "" {
+		return nil, ErrNoAddress
+	}
+
+	return Dial(ctx, addr)
+}
+
+// parseAddr returns the network and address of the Neovim address addr.
+func parseAddr(addr string) (network, address string) {
+	if a, ok := strings.CutPrefix(addr, "tcp:"); ok {
+		return "tcp", a
+	}
+	if isPipe(addr) {
+		return "pipe", addr
+	}
+	// Neovim reports TCP servers as host:port, which cannot be confused
+	// with a socket path since it has no separator.
+	if !strings.ContainsAny(addr, `/\`) {
+		if _, port, err := net.SplitHostPort(addr); err == nil {
+			if _, err := strconv.ParseUint(port, 10, 16); err == nil {
+				return "tcp", addr
+			}
+		}
+	}
+
+	return "unix", addr
+}
+
+// isPipe reports whether addr is the path of a Windows named pipe.
+func isPipe(addr string) bool {
+	lower := strings.ToLower(strings.ReplaceAll(addr, "/", `\`))
+	return strings.HasPrefix(lower, `\\.\pipe\`) || strings.HasPrefix(lower, `\\?\pipe\`)
+}
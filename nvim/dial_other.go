@@ -0,0 +1,16 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build !windows
+
+package nvim
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+func dialPipe(ctx context.Context, name string) (io.ReadWriteCloser, error) {
+	return nil, fmt.Errorf("nvim: named pipe %s is only supported on Windows", name)
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build windows
+
+package nvim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// errorPipeBusy is returned when all the instances of a pipe are busy.
+const errorPipeBusy syscall.Errno = 231
+
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procCreateEventW        = kernel32.NewProc("CreateEventW")
+	procGetOverlappedResult = kernel32.NewProc("GetOverlappedResult")
+)
+
+// dialPipe connects to the named pipe name.
+//
+// The pipe is opened for overlapped I/O, since reads and writes on a
+// synchronous handle are serialized and a pending read would block every
+// write.
+func dialPipe(ctx context.Context, name string) (io.ReadWriteCloser, error) {
+	path, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		h, err := syscall.CreateFile(path,
+			syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+			syscall.OPEN_EXISTING, syscall.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			return newPipe(h)
+		}
+		if err != errorPipeBusy {
+			return nil, fmt.Errorf("nvim: open %s: %w", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// pipe is a client connection to a named pipe.
+type pipe struct {
+	h syscall.Handle
+
+	rmu, wmu sync.Mutex
+	rev, wev syscall.Handle // events of pending reads and writes
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+}
+
+func newPipe(h syscall.Handle) (*pipe, error) {
+	rev, err := createEvent()
+	if err != nil {
+		syscall.CloseHandle(h)
+		return nil, err
+	}
+	wev, err := createEvent()
+	if err != nil {
+		syscall.CloseHandle(rev)
+		syscall.CloseHandle(h)
+		return nil, err
+	}
+
+	return &pipe{h: h, rev: rev, wev: wev}, nil
+}
+
+func createEvent() (syscall.Handle, error) {
+	// Manual-reset, initially unsignaled event.
+	r, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if r == 0 {
+		return 0, err
+	}
+
+	return syscall.Handle(r), nil
+}
+
+// wait waits for the overlapped operation o and returns the number of
+// bytes transferred.
+func (p *pipe) wait(o *syscall.Overlapped) (int, error) {
+	var n uint32
+	r, _, err := procGetOverlappedResult.Call(uintptr(p.h), uintptr(unsafe.Pointer(o)), uintptr(unsafe.Pointer(&n)), 1)
+	if r == 0 {
+		return int(n), err
+	}
+
+	return int(n), nil
+}
+
+func (p *pipe) Read(b []byte) (int, error) {
+	p.rmu.Lock()
+	defer p.rmu.Unlock()
+	if p.closed.Load() {
+		return 0, io.EOF
+	}
+
+	o := syscall.Overlapped{HEvent: p.rev}
+	var n uint32
+	err := syscall.ReadFile(p.h, b, &n, &o)
+	if err == syscall.ERROR_IO_PENDING {
+		var m int
+		m, err = p.wait(&o)
+		n = uint32(m)
+	}
+	switch err {
+	case nil:
+		return int(n), nil
+	case syscall.ERROR_BROKEN_PIPE, syscall.ERROR_OPERATION_ABORTED:
+		return int(n), io.EOF
+	}
+
+	return int(n), err
+}
+
+func (p *pipe) Write(b []byte) (int, error) {
+	p.wmu.Lock()
+	defer p.wmu.Unlock()
+	if p.closed.Load() {
+		return 0, io.ErrClosedPipe
+	}
+
+	written := 0
+	for written < len(b) {
+		o := syscall.Overlapped{HEvent: p.wev}
+		var n uint32
+		err := syscall.WriteFile(p.h, b[written:], &n, &o)
+		if err == syscall.ERROR_IO_PENDING {
+			var m int
+			m, err = p.wait(&o)
+			n = uint32(m)
+		}
+		written += int(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// Close closes the pipe, aborting pending reads and writes.
+func (p *pipe) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+
+		// Cancel until the pending operations have returned, since one
+		// may start between the check of closed and its cancellation.
+		for {
+			syscall.CancelIoEx(p.h, nil)
+			if p.rmu.TryLock() {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		for {
+			syscall.CancelIoEx(p.h, nil)
+			if p.wmu.TryLock() {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		err = syscall.CloseHandle(p.h)
+		syscall.CloseHandle(p.rev)
+		syscall.CloseHandle(p.wev)
+		p.wmu.Unlock()
+		p.rmu.Unlock()
+	})
+
+	return err
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim
+
+import (
+	"context"
+
+	"github.com/go-nvim/pkg/rpc"
+)
+
+type interruptKey struct{}
+
+// WithInterrupt returns a copy of ctx whose calls interrupt Neovim, by
+// sending <C-c> with nvim_input, when ctx is done before they return.
+//
+// Canceling a call only stops waiting for its response; use WithInterrupt
+// for calls Neovim can interrupt, such as long searches, :grep or
+// system(), so that Neovim stops working on them too. Since <C-c> is
+// typed as user input, it may also interrupt whatever else Neovim is
+// doing.
+func WithInterrupt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, interruptKey{}, true)
+}
+
+// interrupt is the interceptor of the calls made with WithInterrupt.
+func (v *Nvim) interrupt(ctx context.Context, info *rpc.Info, invoke func(ctx context.Context) error) error {
+	err := invoke(ctx)
+	if err != nil && info.Kind == rpc.KindCall && ctx.Err() != nil && ctx.Value(interruptKey{}) != nil {
+		// nvim_input is processed even while Neovim is busy.
+		v.Notify(context.Background(), "nvim_input", "<C-c>")
+	}
+
+	return err
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package nvim connects to Neovim instances and calls their API.
+package nvim
+
+//go:generate go run ../internal/apigen -in api_info.mpack -out api.go
+
+import (
+	"io"
+	"sync"
+
+	"github.com/go-nvim/pkg/rpc"
+)
+
+// Nvim is a connection to a Neovim instance.
+type Nvim struct {
+	*rpc.Client
+
+	router router
+
+	channel struct {
+		sync.Mutex
+		id int // 0 until looked up
+	}
+}
+
+// New returns a new Nvim communicating over conn.
+//
+// All the methods take a context: canceling it stops waiting for the
+// response, and with WithInterrupt, interrupts Neovim.
+func New(conn io.ReadWriteCloser) *Nvim {
+	v := &Nvim{Client: rpc.NewClient(conn)}
+	v.Use(v.interrupt)
+
+	return v
+}
@@ -0,0 +1,381 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package nvimtest provides an in-process mock of Neovim for testing
+// packages that build on the client.
+package nvimtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-nvim/pkg/internal/sequence"
+	"github.com/go-nvim/pkg/msgpack"
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/rpc"
+)
+
+// Call is a call received by a MockServer. Args are decoded as by
+// msgpack.Decoder.DecodeInterface.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s%v", c.Method, c.Args)
+}
+
+// HandlerFunc answers a call. An error that is not an *rpc.Error is sent
+// as an exception.
+//
+// Handlers run in their own goroutine, so they may call back into the
+// client with MockServer.Request. Calls are recorded in the order they are
+// received, but their handlers may run concurrently.
+type HandlerFunc func(args []interface{}) (interface{}, error)
+
+// MockServer is an in-process msgpack-RPC server standing in for Neovim.
+//
+// Calls of methods without a handler fail with "method not found", except
+// nvim_get_api_info, which returns channel 1, and nvim_call_atomic, which
+// calls the handlers of the batched methods. All calls are recorded,
+// including the batched ones.
+type MockServer struct {
+	conn net.Conn
+	dec  *msgpack.Decoder
+
+	wmu sync.Mutex
+	enc *msgpack.Encoder
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	calls    []Call
+	changed  chan struct{} // closed and replaced on each call
+	seq      uint32
+	pending  map[uint32]chan response
+}
+
+type response struct {
+	err    interface{}
+	result msgpack.RawMessage
+}
+
+// NewMockServer returns a new MockServer and an Nvim connected to it. Both
+// are closed when the test finishes.
+func NewMockServer(t testing.TB) (*MockServer, *nvim.Nvim) {
+	client, server := net.Pipe()
+	s := &MockServer{
+		conn:     server,
+		dec:      msgpack.NewDecoder(server),
+		enc:      msgpack.NewEncoder(server),
+		handlers: make(map[string]HandlerFunc),
+		changed:  make(chan struct{}),
+		pending:  make(map[uint32]chan response),
+	}
+	s.Handle("nvim_get_api_info", func([]interface{}) (interface{}, error) {
+		return []interface{}{1, map[string]interface{}{}}, nil
+	})
+	go s.serve()
+
+	v := nvim.New(client)
+	t.Cleanup(func() {
+		v.Close()
+		server.Close()
+	})
+
+	return s, v
+}
+
+// Handle registers fn as the handler of method, replacing any previous
+// handler.
+func (s *MockServer) Handle(method string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = fn
+}
+
+// Return makes the calls of method return result.
+func (s *MockServer) Return(method string, result interface{}) {
+	s.Handle(method, func([]interface{}) (interface{}, error) { return result, nil })
+}
+
+// Fail makes the calls of method fail with an exception of message msg.
+func (s *MockServer) Fail(method string, msg string) {
+	s.Handle(method, func([]interface{}) (interface{}, error) {
+		return nil, &rpc.Error{Type: rpc.ExceptionError, Message: msg}
+	})
+}
+
+// Notify sends a notification of method with args to the client.
+func (s *MockServer) Notify(method string, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	return s.write([]interface{}{2, method, args})
+}
+
+// Request calls method of the client with args, as rpcrequest() would, and
+// stores the result in the value pointed to by result.
+func (s *MockServer) Request(ctx context.Context, method string, result interface{}, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	ch := make(chan response, 1)
+	s.mu.Lock()
+	s.seq++
+	id := s.seq
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	if err := s.write([]interface{}{0, id, method, args}); err != nil {
+		return err
+	}
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return fmt.Errorf("nvimtest: %s: %v", method, r.err)
+		}
+		if result == nil {
+			return nil
+		}
+		return msgpack.Unmarshal(r.result, result)
+	case <-ctx.Done():
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *MockServer) write(msg []interface{}) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return s.enc.Encode(msg)
+}
+
+// serve reads messages until the connection is closed.
+func (s *MockServer) serve() {
+	for {
+		var msg []msgpack.RawMessage
+		if err := s.dec.Decode(&msg); err != nil {
+			return
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		var typ int
+		msgpack.Unmarshal(msg[0], &typ)
+
+		switch {
+		case typ == 0 && len(msg) == 4:
+			var id uint32
+			var c Call
+			msgpack.Unmarshal(msg[1], &id)
+			msgpack.Unmarshal(msg[2], &c.Method)
+			msgpack.Unmarshal(msg[3], &c.Args)
+			fn := s.record(c)
+			go func() {
+				result, err := s.handle(c, fn)
+				resp := []interface{}{1, id, nil, result}
+				if err != nil {
+					resp[2], resp[3] = encodeError(err), nil
+				}
+				s.write(resp)
+			}()
+
+		case typ == 1 && len(msg) == 4:
+			var id uint32
+			var r response
+			msgpack.Unmarshal(msg[1], &id)
+			msgpack.Unmarshal(msg[2], &r.err)
+			r.result = msg[3]
+			s.mu.Lock()
+			ch, ok := s.pending[id]
+			delete(s.pending, id)
+			s.mu.Unlock()
+			if ok {
+				ch <- r
+			}
+
+		case typ == 2 && len(msg) == 3:
+			var c Call
+			msgpack.Unmarshal(msg[1], &c.Method)
+			msgpack.Unmarshal(msg[2], &c.Args)
+			fn := s.record(c)
+			go s.handle(c, fn)
+		}
+	}
+}
+
+// call records c and calls its handler.
+func (s *MockServer) call(c Call) (interface{}, error) {
+	return s.handle(c, s.record(c))
+}
+
+// record records c and returns its handler.
+func (s *MockServer) record(c Call) HandlerFunc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, c)
+	close(s.changed)
+	s.changed = make(chan struct{})
+
+	return s.handlers[c.Method]
+}
+
+// handle calls fn, the handler of c.
+func (s *MockServer) handle(c Call, fn HandlerFunc) (interface{}, error) {
+	switch {
+	case fn != nil:
+		return fn(c.Args)
+	case c.Method == "nvim_call_atomic" && len(c.Args) == 1:
+		return s.callAtomic(c.Args[0])
+	}
+
+	return nil, &rpc.Error{Type: rpc.ExceptionError, Message: "method not found: " + c.Method}
+}
+
+// callAtomic calls the batched calls, stopping at the first error.
+func (s *MockServer) callAtomic(arg interface{}) (interface{}, error) {
+	calls, _ := arg.([]interface{})
+	results := []interface{}{}
+	for i, call := range calls {
+		pair, _ := call.([]interface{})
+		if len(pair) != 2 {
+			return nil, &rpc.Error{Type: rpc.ValidationError, Message: "invalid call"}
+		}
+		method, _ := pair[0].(string)
+		args, _ := pair[1].([]interface{})
+		result, err := s.call(Call{Method: method, Args: args})
+		if err != nil {
+			e := encodeError(err)
+			return []interface{}{results, []interface{}{i, e[0], e[1]}}, nil
+		}
+		results = append(results, result)
+	}
+
+	return []interface{}{results, nil}, nil
+}
+
+// encodeError returns err as a msgpack-RPC error.
+func encodeError(err error) []interface{} {
+	if e, ok := err.(*rpc.Error); ok {
+		return []interface{}{int(e.Type), e.Message}
+	}
+
+	return []interface{}{int(rpc.ExceptionError), err.Error()}
+}
+
+// Calls returns the recorded calls in the order they were received.
+func (s *MockServer) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// Methods returns the methods of the recorded calls in the order they were
+// received.
+func (s *MockServer) Methods() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	methods := make([]string, len(s.calls))
+	for i, c := range s.calls {
+		methods[i] = c.Method
+	}
+
+	return methods
+}
+
+// Reset discards the recorded calls.
+func (s *MockServer) Reset() {
+	s.mu.Lock()
+	s.calls = nil
+	s.mu.Unlock()
+}
+
+// hasSequence reports whether methods were called in this order, possibly
+// with other calls in between.
+func (s *MockServer) hasSequence(methods []string) (bool, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	called := make([]string, len(s.calls))
+	for i, c := range s.calls {
+		called[i] = c.Method
+	}
+
+	return sequence.Contains(called, methods), s.changed
+}
+
+// Wait blocks until methods have been called in this order, possibly with
+// other calls in between, or ctx is done.
+func (s *MockServer) Wait(ctx context.Context, methods ...string) error {
+	err := sequence.Wait(ctx, func() (bool, <-chan struct{}) { return s.hasSequence(methods) })
+	if err != nil {
+		return fmt.Errorf("nvimtest: waiting for %s: %w (called %s)", sequence.Join(methods), err, sequence.Join(s.Methods()))
+	}
+
+	return nil
+}
+
+// ExpectSequence reports a test error if methods were not called in this
+// order. Other calls may have been made in between.
+func (s *MockServer) ExpectSequence(t testing.TB, methods ...string) bool {
+	t.Helper()
+
+	if ok, _ := s.hasSequence(methods); !ok {
+		t.Errorf("nvimtest: expected sequence %s, called %s", sequence.Join(methods), sequence.Join(s.Methods()))
+		return false
+	}
+
+	return true
+}
+
+// ExpectCall reports a test error if method was never called with args.
+// Args are compared after a msgpack round trip, so that, for example, an
+// int matches the int64 decoded by the server.
+func (s *MockServer) ExpectCall(t testing.TB, method string, args ...interface{}) bool {
+	t.Helper()
+
+	want, err := roundTrip(args)
+	if err != nil {
+		t.Errorf("nvimtest: encode arguments of %s: %v", method, err)
+		return false
+	}
+	for _, c := range s.Calls() {
+		if c.Method == method && reflect.DeepEqual(c.Args, want) {
+			return true
+		}
+	}
+	t.Errorf("nvimtest: expected call %s, called %v", Call{Method: method, Args: want}, s.Calls())
+
+	return false
+}
+
+// roundTrip returns args encoded and decoded again.
+func roundTrip(args []interface{}) ([]interface{}, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	data, err := msgpack.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	if err := msgpack.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
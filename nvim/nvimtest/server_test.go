@@ -0,0 +1,128 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvimtest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/rpc"
+)
+
+func TestReturnAndFail(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	ctx := context.Background()
+
+	s.Return("nvim_get_current_line", "hello")
+	line, err := v.GetCurrentLine(ctx)
+	if err != nil || line != "hello" {
+		t.Errorf("GetCurrentLine() = %q, %v; want %q", line, err, "hello")
+	}
+
+	s.Fail("nvim_command", "E492: Not an editor command")
+	var e *rpc.Error
+	if err := v.Command(ctx, "nope"); !errors.As(err, &e) || e.Message != "E492: Not an editor command" {
+		t.Errorf("Command() = %v, want the E492 exception", err)
+	}
+
+	if err := v.Call(ctx, "nvim_unknown", nil); err == nil || !strings.Contains(err.Error(), "method not found") {
+		t.Errorf("unknown method: got %v, want method not found", err)
+	}
+
+	s.ExpectSequence(t, "nvim_get_current_line", "nvim_command", "nvim_unknown")
+	s.ExpectCall(t, "nvim_command", "nope")
+
+	s.Reset()
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Errorf("Calls() after Reset = %v", calls)
+	}
+}
+
+func TestCallAtomic(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_get_current_line", "line")
+	s.Fail("nvim_command", "boom")
+
+	b := v.NewBatch()
+	var line string
+	b.GetCurrentLine(&line)
+	b.Command("boom")
+	b.SetCurrentLine("never")
+	err := b.Execute(context.Background())
+
+	var be *nvim.BatchError
+	if !errors.As(err, &be) || be.Index != 1 || be.Method != "nvim_command" {
+		t.Fatalf("Execute() = %v, want a BatchError of call 1", err)
+	}
+	if line != "line" {
+		t.Errorf("result of call 0 = %q, want %q", line, "line")
+	}
+	want := []string{"nvim_call_atomic", "nvim_get_current_line", "nvim_command"}
+	if got := s.Methods(); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Methods() = %v, want %v", got, want)
+	}
+}
+
+func TestWait(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_command", nil)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		v.Command(context.Background(), "first")
+		v.Command(context.Background(), "second")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Wait(ctx, "nvim_command", "nvim_command"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Wait(ctx, "nvim_input"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRequestFromHandler(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	if err := v.HandleRequest("go_ping", func(s string) string { return s + " pong" }); err != nil {
+		t.Fatal(err)
+	}
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		// Like rpcrequest() from Lua, while the call is pending.
+		var res string
+		err := s.Request(context.Background(), "go_ping", &res, "ping")
+		return res, err
+	})
+
+	res, err := v.ExecLua(context.Background(), "return rpcrequest(...)", nil)
+	if err != nil || res != "ping pong" {
+		t.Errorf("ExecLua() = %v, %v; want %q", res, err, "ping pong")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	got := make(chan []interface{}, 1)
+	v.Handle("event", func(args []interface{}) { got <- args })
+
+	if err := s.Notify("event", "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case args := <-got:
+		if len(args) != 2 || args[0] != "a" || args[1] != int64(1) {
+			t.Errorf("got %v, want [a 1]", args)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification not received")
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-nvim/pkg/msgpack"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Extmark is an extmark of a buffer.
+type Extmark struct {
+	ID      int
+	Row     int
+	Col     int
+	Details map[string]interface{} // nil unless requested
+
+	// NsID is the namespace of the extmark, set by StreamExtmarks.
+	NsID int
+}
+
+// ExtmarkChunk is a chunk of extmarks sent by StreamExtmarks. The last
+// chunk of a failed stream has Err set.
+type ExtmarkChunk struct {
+	Extmarks []Extmark
+	Err      error
+}
+
+// StreamExtmarks sends the extmarks of buf in namespace nsID, or in all
+// namespaces if nsID is -1, in chunks of about size extmarks, ordered by
+// position.
+//
+// Each chunk is fetched with a separate nvim_buf_get_extmarks call, so that
+// Neovim never serializes all the extmarks of a large buffer at once. The
+// channel is closed after the last chunk, or when ctx is done.
+//
+// With nsID -1, the details are always fetched to learn the namespace of
+// the extmarks, as IDs are only unique within a namespace.
+func (v *Nvim) StreamExtmarks(ctx context.Context, buf types.Buffer, nsID int, size int, details bool) <-chan ExtmarkChunk {
+	if size <= 0 {
+		size = 1000
+	}
+	ch := make(chan ExtmarkChunk)
+
+	go func() {
+		defer close(ch)
+
+		var start interface{} = 0
+		seen := make(map[extmarkKey]bool) // extmarks at the start position
+		limit := size
+		for {
+			var marks []Extmark
+			if err := v.Call(ctx, "nvim_buf_get_extmarks", &marks, buf, nsID, start, -1,
+				map[string]interface{}{"limit": limit, "details": details || nsID == -1}); err != nil {
+				sendExtmarks(ctx, ch, ExtmarkChunk{Err: err})
+				return
+			}
+			for i := range marks {
+				m := &marks[i]
+				m.NsID = nsID
+				if nsID == -1 {
+					m.NsID = toInt(m.Details["ns_id"])
+				}
+				if !details {
+					m.Details = nil
+				}
+			}
+
+			// The next chunk starts at the position of the last extmark
+			// of this one, and repeats the extmarks at that position.
+			var chunk []Extmark
+			for _, m := range marks {
+				if !seen[m.key()] {
+					chunk = append(chunk, m)
+				}
+			}
+			done := len(marks) < limit
+			if !done {
+				last := marks[len(marks)-1]
+				next := make(map[extmarkKey]bool)
+				for _, m := range marks {
+					if m.Row == last.Row && m.Col == last.Col {
+						next[m.key()] = true
+					}
+				}
+				if len(next) == len(marks) {
+					// The whole chunk is at one position: fetch more
+					// to get past it.
+					limit *= 2
+				} else {
+					limit = size
+				}
+				start = []int{last.Row, last.Col}
+				seen = next
+			}
+
+			if len(chunk) > 0 && !sendExtmarks(ctx, ch, ExtmarkChunk{Extmarks: chunk}) {
+				return
+			}
+			if done {
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// extmarkKey identifies an extmark across namespaces.
+type extmarkKey struct {
+	ns, id int
+}
+
+func (m *Extmark) key() extmarkKey {
+	return extmarkKey{ns: m.NsID, id: m.ID}
+}
+
+// toInt converts a decoded msgpack integer to int.
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	}
+
+	return 0
+}
+
+// sendExtmarks sends c on ch, unless ctx is done first.
+func sendExtmarks(ctx context.Context, ch chan<- ExtmarkChunk, c ExtmarkChunk) bool {
+	select {
+	case ch <- c:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// UnmarshalMsgPack implements msgpack.Unmarshaler.
+func (m *Extmark) UnmarshalMsgPack(d *msgpack.Decoder) error {
+	n, err := d.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+	if n < 3 {
+		return fmt.Errorf("nvim: invalid extmark of length %d", n)
+	}
+	fields := []interface{}{&m.ID, &m.Row, &m.Col, &m.Details}
+	for i := 0; i < n; i++ {
+		if i >= len(fields) {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.Decode(fields[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StreamLines calls fn with each line of buf in [start, end), as
+// nvim_buf_get_lines, as the lines are read from the connection, so that the
+// lines of a large buffer are never held in memory at once.
+//
+// fn must not make calls with v. If fn returns an error, StreamLines stops
+// and returns it.
+func (v *Nvim) StreamLines(ctx context.Context, buf types.Buffer, start, end int, strict bool, fn func(line string) error) error {
+	return v.CallStream(ctx, "nvim_buf_get_lines", func(elem msgpack.RawMessage) error {
+		var line string
+		if err := msgpack.Unmarshal(elem, &line); err != nil {
+			return err
+		}
+		return fn(line)
+	}, buf, start, end, strict)
+}
+
+// CompletionChunk is a chunk of completions sent by StreamCompletion. The
+// last chunk of a failed stream has Err set.
+type CompletionChunk struct {
+	Items []string
+	Err   error
+}
+
+// completionLua pages the result of getcompletion(), caching it in Neovim
+// between calls.
+const completionLua = `
+local key, pat, typ, first, n = ...
+_G.__go_nvim_completion = _G.__go_nvim_completion or {}
+local cache = _G.__go_nvim_completion
+if first == 0 then
+  cache[key] = vim.fn.getcompletion(pat, typ)
+end
+local items = cache[key] or {}
+if first + n >= #items then
+  cache[key] = nil
+end
+return vim.list_slice(items, first + 1, first + n)
+`
+
+var completionSeq uint64
+
+// StreamCompletion sends the completions of pat of type typ, as returned
+// by getcompletion(), in chunks of size items.
+//
+// The completions are computed once and kept in Neovim until the last chunk
+// is sent, or ctx is done. The channel is closed after the last chunk, or
+// when ctx is done.
+func (v *Nvim) StreamCompletion(ctx context.Context, pat, typ string, size int) <-chan CompletionChunk {
+	if size <= 0 {
+		size = 1000
+	}
+	ch := make(chan CompletionChunk)
+	key := fmt.Sprintf("%p:%d", v, atomic.AddUint64(&completionSeq, 1))
+
+	go func() {
+		defer close(ch)
+
+		for first := 0; ; first += size {
+			var items []string
+			if err := v.Call(ctx, "nvim_exec_lua", &items, completionLua, []interface{}{key, pat, typ, first, size}); err != nil {
+				if ctx.Err() == nil {
+					sendCompletion(ctx, ch, CompletionChunk{Err: err})
+				}
+				v.dropCompletion(key)
+				return
+			}
+			if len(items) > 0 && !sendCompletion(ctx, ch, CompletionChunk{Items: items}) {
+				v.dropCompletion(key)
+				return
+			}
+			if len(items) < size {
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// dropCompletion removes the completions cached for key.
+func (v *Nvim) dropCompletion(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	v.Call(ctx, "nvim_exec_lua", nil, `(_G.__go_nvim_completion or {})[...] = nil`, []interface{}{key})
+}
+
+func sendCompletion(ctx context.Context, ch chan<- CompletionChunk, c CompletionChunk) bool {
+	select {
+	case ch <- c:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+)
+
+func TestStreamExtmarksNamespaces(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+
+	// Extmarks of two namespaces, ordered by position, sharing IDs.
+	type mark struct{ ns, id, row, col int }
+	marks := []mark{{1, 1, 0, 0}, {1, 2, 0, 5}, {2, 2, 0, 5}, {2, 3, 1, 0}}
+	s.Handle("nvim_buf_get_extmarks", func(args []interface{}) (interface{}, error) {
+		row, col := int64(0), int64(0)
+		if start, ok := args[2].([]interface{}); ok {
+			row, col = start[0].(int64), start[1].(int64)
+		}
+		limit := args[4].(map[string]interface{})["limit"].(int64)
+		result := []interface{}{}
+		for _, m := range marks {
+			if int64(m.row) < row || int64(m.row) == row && int64(m.col) < col {
+				continue
+			}
+			if int64(len(result)) == limit {
+				break
+			}
+			result = append(result, []interface{}{m.id, m.row, m.col, map[string]interface{}{"ns_id": m.ns}})
+		}
+		return result, nil
+	})
+
+	var got []string
+	for c := range v.StreamExtmarks(context.Background(), 1, -1, 2, false) {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		for _, m := range c.Extmarks {
+			if m.Details != nil {
+				t.Errorf("details of %d:%d not requested", m.NsID, m.ID)
+			}
+			got = append(got, fmt.Sprintf("%d:%d", m.NsID, m.ID))
+		}
+	}
+	if want := "[1:1 1:2 2:2 2:3]"; fmt.Sprint(got) != want {
+		t.Errorf("got extmarks %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// router dispatches notifications to the subscriptions of an Nvim.
+type router struct {
+	mu        sync.Mutex
+	subs      []*Subscription
+	events    map[string]*serverSub    // server subscription of each event
+	leaving   map[string]chan struct{} // closed once nvim_unsubscribe returns
+	wildcards int                      // wildcard subscriptions
+}
+
+// serverSub is the nvim_subscribe subscription of an event, shared by its
+// Subscriptions.
+type serverSub struct {
+	refs  int
+	ready chan struct{} // closed once nvim_subscribe returns
+	err   error
+}
+
+// Subscription is a handler of notifications subscribed with
+// Nvim.Subscribe.
+type Subscription struct {
+	v       *Nvim
+	event   string
+	handler func(args []interface{})
+	server  *serverSub // nil for wildcards
+}
+
+// Event returns the event of s, as passed to Subscribe.
+func (s *Subscription) Event() string { return s.event }
+
+// isWildcard reports whether event is a prefix pattern.
+func isWildcard(event string) bool {
+	return strings.HasSuffix(event, "*")
+}
+
+// match reports whether s handles notifications of event.
+func (s *Subscription) match(event string) bool {
+	if prefix, ok := strings.CutSuffix(s.event, "*"); ok {
+		return strings.HasPrefix(event, prefix)
+	}
+
+	return s.event == event
+}
+
+// Subscribe calls handler with the arguments of the notifications of
+// event, sent with rpcnotify().
+//
+// An event ending with "*" matches the events starting with the part
+// before it. Neovim has no wildcard subscriptions: such a handler receives
+// the notifications sent to the channel of v, and the broadcast events
+// subscribed by other handlers.
+//
+// Handlers are called in the order they subscribed. A panicking handler is
+// reported with nvim_err_writeln.
+func (v *Nvim) Subscribe(ctx context.Context, event string, handler func(args []interface{})) (*Subscription, error) {
+	if event == "" {
+		return nil, fmt.Errorf("nvim: empty event")
+	}
+	s := &Subscription{v: v, event: event, handler: handler}
+	r := &v.router
+
+	r.mu.Lock()
+	r.subs = append(r.subs, s)
+	if isWildcard(event) {
+		r.wildcards++
+		if r.wildcards == 1 {
+			v.HandleDefault(v.route)
+		}
+		r.mu.Unlock()
+		return s, nil
+	}
+	if r.events == nil {
+		r.events = make(map[string]*serverSub)
+	}
+	ss := r.events[event]
+	first := ss == nil
+	if first {
+		ss = &serverSub{ready: make(chan struct{})}
+		r.events[event] = ss
+		v.Handle(event, func(args []interface{}) { v.route(event, args) })
+	}
+	ss.refs++
+	s.server = ss
+	leaving := r.leaving[event]
+	r.mu.Unlock()
+
+	if !first {
+		// Wait for the subscription of the first handler.
+		select {
+		case <-ss.ready:
+		case <-ctx.Done():
+			s.Unsubscribe(context.Background())
+			return nil, ctx.Err()
+		}
+		if ss.err != nil {
+			s.remove()
+			return nil, ss.err
+		}
+		return s, nil
+	}
+
+	if leaving != nil {
+		// Do not race with the nvim_unsubscribe of the previous handlers.
+		select {
+		case <-leaving:
+		case <-ctx.Done():
+		}
+	}
+	err := v.Call(ctx, "nvim_subscribe", nil, event)
+	r.mu.Lock()
+	ss.err = err
+	close(ss.ready)
+	r.mu.Unlock()
+	if err != nil {
+		s.remove()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Unsubscribe removes the handler. Neovim stops broadcasting the event to
+// v when its last handler unsubscribes.
+func (s *Subscription) Unsubscribe(ctx context.Context) error {
+	done := s.remove()
+	if done == nil {
+		return nil
+	}
+
+	err := s.v.Call(ctx, "nvim_unsubscribe", nil, s.event)
+
+	r := &s.v.router
+	r.mu.Lock()
+	if r.leaving[s.event] == done {
+		delete(r.leaving, s.event)
+	}
+	r.mu.Unlock()
+	close(done)
+
+	return err
+}
+
+// remove removes s from the router. If s was the last handler of an event
+// subscribed in Neovim, it returns a channel to close once the event is
+// unsubscribed, which the next nvim_subscribe of the event waits for.
+func (s *Subscription) remove() chan struct{} {
+	r := &s.v.router
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := false
+	for i, sub := range r.subs {
+		if sub == s {
+			r.subs = append(r.subs[:i:i], r.subs[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	if s.server == nil {
+		r.wildcards--
+		if r.wildcards == 0 {
+			s.v.HandleDefault(nil)
+		}
+		return nil
+	}
+	s.server.refs--
+	if s.server.refs > 0 {
+		return nil
+	}
+	if r.events[s.event] == s.server {
+		delete(r.events, s.event)
+		s.v.Handle(s.event, nil)
+	}
+	// The last handler leaves once nvim_subscribe has returned, as the
+	// first handler holds a reference until then.
+	if s.server.err != nil {
+		return nil
+	}
+	done := make(chan struct{})
+	if r.leaving == nil {
+		r.leaving = make(map[string]chan struct{})
+	}
+	r.leaving[s.event] = done
+
+	return done
+}
+
+// route calls the handlers of the notification of event.
+func (v *Nvim) route(event string, args []interface{}) {
+	r := &v.router
+	r.mu.Lock()
+	var subs []*Subscription
+	for _, s := range r.subs {
+		if s.match(event) {
+			subs = append(subs, s)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, s := range subs {
+		v.call(s, args)
+	}
+}
+
+// call calls the handler of s, reporting a panic to the user.
+func (v *Nvim) call(s *Subscription, args []interface{}) {
+	defer func() {
+		if p := recover(); p != nil {
+			msg := fmt.Sprintf("%s handler panicked: %v", s.event, p)
+			go v.ErrWriteln(context.Background(), msg)
+		}
+	}()
+
+	s.handler(args)
+}
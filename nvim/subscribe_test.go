@@ -0,0 +1,143 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nvim_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/rpc"
+)
+
+// count returns the number of calls of method.
+func count(s *nvimtest.MockServer, method string) int {
+	n := 0
+	for _, m := range s.Methods() {
+		if m == method {
+			n++
+		}
+	}
+
+	return n
+}
+
+// sync waits for the notifications sent to v before it to be handled.
+func flush(t *testing.T, s *nvimtest.MockServer, v *nvim.Nvim) {
+	t.Helper()
+
+	done := make(chan struct{})
+	v.Handle("sync", func([]interface{}) { close(done) })
+	s.Notify("sync")
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notifications not handled")
+	}
+	v.Handle("sync", nil)
+}
+
+func TestSubscribeRefcount(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_subscribe", nil)
+	s.Return("nvim_unsubscribe", nil)
+	ctx := context.Background()
+
+	got := make(chan int, 4)
+	s1, err := v.Subscribe(ctx, "event", func([]interface{}) { got <- 1 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := v.Subscribe(ctx, "event", func([]interface{}) { got <- 2 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := count(s, "nvim_subscribe"); n != 1 {
+		t.Errorf("nvim_subscribe called %d times, want once", n)
+	}
+
+	s.Notify("event")
+	if a, b := <-got, <-got; a != 1 || b != 2 {
+		t.Errorf("handlers called in order %d, %d", a, b)
+	}
+
+	s1.Unsubscribe(ctx)
+	if n := count(s, "nvim_unsubscribe"); n != 0 {
+		t.Errorf("unsubscribed with a handler left")
+	}
+	s2.Unsubscribe(ctx)
+	if n := count(s, "nvim_unsubscribe"); n != 1 {
+		t.Errorf("nvim_unsubscribe called %d times, want once", n)
+	}
+}
+
+func TestSubscribeFailure(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	release := make(chan struct{})
+	s.Handle("nvim_subscribe", func([]interface{}) (interface{}, error) {
+		<-release
+		return nil, &rpc.Error{Type: rpc.ExceptionError, Message: "boom"}
+	})
+	ctx := context.Background()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := v.Subscribe(ctx, "event", func([]interface{}) {})
+		errs <- err
+	}()
+	if err := s.Wait(ctx, "nvim_subscribe"); err != nil {
+		t.Fatal(err)
+	}
+	// A concurrent handler of the same event waits for the outcome of the
+	// first nvim_subscribe.
+	go func() {
+		_, err := v.Subscribe(ctx, "event", func([]interface{}) {})
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err == nil {
+			t.Errorf("Subscribe %d succeeded without a subscription", i)
+		}
+	}
+
+	// The next handler subscribes again.
+	s.Return("nvim_subscribe", nil)
+	if _, err := v.Subscribe(ctx, "event", func([]interface{}) {}); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(s, "nvim_subscribe"); n != 2 {
+		t.Errorf("nvim_subscribe called %d times, want 2", n)
+	}
+}
+
+func TestSubscribeWildcard(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	ctx := context.Background()
+
+	var got []interface{}
+	sub, err := v.Subscribe(ctx, "go:*", func(args []interface{}) { got = append(got, args...) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Notify("go:a", 1)
+	s.Notify("other", 2)
+	flush(t, s, v)
+
+	if err := sub.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s.Notify("go:b", 3)
+	flush(t, s, v)
+
+	if len(got) != 1 || got[0] != int64(1) {
+		t.Errorf("wildcard handler got %v, want [1]", got)
+	}
+	if n := count(s, "nvim_subscribe"); n != 0 {
+		t.Errorf("wildcard subscribed in Neovim")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package paste handles the text pasted in Neovim in Go, by replacing the
+// vim.paste handler.
+package paste
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-nvim/pkg/nvim"
+)
+
+// Phase is the phase of a chunk of a streamed paste.
+type Phase int
+
+// List of phases, as passed to vim.paste.
+const (
+	Single   Phase = -1 // the whole paste in one chunk
+	Start    Phase = 1
+	Continue Phase = 2
+	End      Phase = 3
+)
+
+// Chunk is a chunk of pasted text.
+type Chunk struct {
+	Lines []string
+	Phase Phase
+}
+
+// Func handles a chunk of a paste and returns the lines to paste instead.
+// Returning an error cancels the rest of the paste.
+type Func func(ctx context.Context, c Chunk) ([]string, error)
+
+// Transform transforms the lines of a chunk.
+type Transform func(lines []string) []string
+
+// NormalizeLineEndings removes the "\r" ending lines pasted with Windows
+// line endings.
+func NormalizeLineEndings(lines []string) []string {
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+
+	return lines
+}
+
+// ansiRE matches the ANSI escape sequences: CSI sequences, such as colors,
+// and OSC sequences.
+var ansiRE = regexp.MustCompile("\x1b\\[[0-?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+// StripANSI removes the ANSI escape sequences, such as colors copied from
+// a terminal.
+func StripANSI(lines []string) []string {
+	for i, l := range lines {
+		lines[i] = ansiRE.ReplaceAllString(l, "")
+	}
+
+	return lines
+}
+
+// pasteLua installs a handler calling the Go client. The handlers are
+// chained, the last installed first, each pasting the lines returned by the
+// previous one, and the original vim.paste pastes the lines returned by the
+// last. The indent options are turned off during the pastes if a handler
+// asks for it.
+const pasteLua = `
+local chan, method, noindent = ...
+local state = _G.__go_nvim_paste
+if not state then
+  state = { orig = vim.paste, handlers = {} }
+  _G.__go_nvim_paste = state
+  local indentopts = { 'autoindent', 'smartindent', 'cindent', 'indentexpr' }
+  local function restore()
+    local saved = state.saved
+    state.saved = nil
+    if saved and vim.api.nvim_buf_is_valid(saved.buf) then
+      for k, v in pairs(saved.opts) do
+        vim.bo[saved.buf][k] = v
+      end
+    end
+  end
+  state.paste = function(lines, phase)
+    if phase == -1 or phase == 1 then
+      restore()
+      for _, h in ipairs(state.handlers) do
+        if h.noindent then
+          local buf = vim.api.nvim_get_current_buf()
+          state.saved = { buf = buf, opts = {} }
+          for _, o in ipairs(indentopts) do
+            state.saved.opts[o] = vim.bo[buf][o]
+            vim.bo[buf][o] = o == 'indentexpr' and '' or false
+          end
+          break
+        end
+      end
+    end
+    for i = #state.handlers, 1, -1 do
+      local h = state.handlers[i]
+      local ok, res = pcall(vim.rpcrequest, h.chan, h.method, lines, phase)
+      if not ok or type(res) ~= 'table' then
+        restore()
+        return false
+      end
+      lines = res
+    end
+    local ok, res = pcall(state.orig, lines, phase)
+    if not ok or res == false or phase == -1 or phase == 3 then
+      restore()
+    end
+    if not ok then
+      error(res, 0)
+    end
+    return res
+  end
+  vim.paste = state.paste
+end
+table.insert(state.handlers, { chan = chan, method = method, noindent = noindent })
+`
+
+// restoreLua removes a handler, and restores the original vim.paste once
+// there are none left, unless vim.paste was replaced since.
+const restoreLua = `
+local chan, method = ...
+local state = _G.__go_nvim_paste
+if state then
+  for i, h in ipairs(state.handlers) do
+    if h.chan == chan and h.method == method then
+      table.remove(state.handlers, i)
+      break
+    end
+  end
+  if #state.handlers == 0 then
+    if vim.paste == state.paste then
+      vim.paste = state.orig
+    end
+    _G.__go_nvim_paste = nil
+  end
+end
+`
+
+var pasteSeq uint64
+
+// Handler handles the pastes of Neovim.
+type Handler struct {
+	v          *nvim.Nvim
+	channel    int
+	method     string
+	fn         Func
+	transforms []Transform
+	noIndent   bool
+	onError    func(error)
+
+	mu       sync.Mutex
+	canceled bool
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithTransforms applies transforms to the lines of each chunk, in order,
+// before the Func of the Handler.
+func WithTransforms(transforms ...Transform) Option {
+	return func(h *Handler) { h.transforms = append(h.transforms, transforms...) }
+}
+
+// WithoutIndent turns off 'autoindent', 'smartindent', 'cindent' and
+// 'indentexpr' in the buffer pasted into during the pastes, so that the
+// pasted lines keep their indent.
+func WithoutIndent() Option {
+	return func(h *Handler) { h.noIndent = true }
+}
+
+// WithErrorHandler calls fn with the errors returned by the Func of the
+// Handler, which cancel the paste. Without it, they are ignored.
+func WithErrorHandler(fn func(error)) Option {
+	return func(h *Handler) { h.onError = fn }
+}
+
+// Install makes h handle the text pasted in Neovim, until Close. A nil fn
+// pastes the lines as transformed. Handlers installed together are chained,
+// the last installed first.
+//
+// Pastes are streamed in chunks: fn is called with each chunk, and may
+// cancel the rest of the paste, as may Cancel.
+func Install(ctx context.Context, v *nvim.Nvim, fn Func, opts ...Option) (*Handler, error) {
+	h := &Handler{
+		v:       v,
+		method:  fmt.Sprintf("go_nvim_paste_%d", atomic.AddUint64(&pasteSeq, 1)),
+		fn:      fn,
+		onError: func(error) {},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	var err error
+	if h.channel, err = v.ChannelID(ctx); err != nil {
+		return nil, err
+	}
+	if err := v.HandleRequest(h.method, h.paste); err != nil {
+		return nil, err
+	}
+	if _, err := v.ExecLua(ctx, pasteLua, []interface{}{h.channel, h.method, h.noIndent}); err != nil {
+		v.HandleRequest(h.method, nil)
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// paste serves a chunk. It returns false to cancel the paste.
+func (h *Handler) paste(ctx context.Context, lines []string, phase int) (interface{}, error) {
+	h.mu.Lock()
+	if phase == int(Single) || phase == int(Start) {
+		h.canceled = false
+	}
+	canceled := h.canceled
+	h.mu.Unlock()
+	if canceled {
+		return false, nil
+	}
+
+	for _, t := range h.transforms {
+		lines = t(lines)
+	}
+	if h.fn != nil {
+		var err error
+		if lines, err = h.fn(ctx, Chunk{Lines: lines, Phase: Phase(phase)}); err != nil {
+			h.onError(err)
+			return false, nil
+		}
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+
+	return lines, nil
+}
+
+// Cancel cancels the rest of the paste in progress, if any.
+func (h *Handler) Cancel() {
+	h.mu.Lock()
+	h.canceled = true
+	h.mu.Unlock()
+}
+
+// Close removes h from the handlers of vim.paste, restoring the original
+// vim.paste once there are none left.
+func (h *Handler) Close(ctx context.Context) error {
+	h.v.HandleRequest(h.method, nil)
+	_, err := h.v.ExecLua(ctx, restoreLua, []interface{}{h.channel, h.method})
+
+	return err
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package paste_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/paste"
+)
+
+// mockPaste makes s record the methods of the installed handlers, and
+// returns them.
+func mockPaste(s *nvimtest.MockServer) <-chan []interface{} {
+	installed := make(chan []interface{}, 4)
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		if params, ok := args[1].([]interface{}); ok && len(params) == 3 {
+			installed <- params
+		}
+		return nil, nil
+	})
+
+	return installed
+}
+
+func TestPaste(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	installed := mockPaste(s)
+	ctx := context.Background()
+
+	h, err := paste.Install(ctx, v, nil, paste.WithTransforms(paste.NormalizeLineEndings, paste.StripANSI), paste.WithoutIndent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := <-installed
+	if params[2] != true {
+		t.Errorf("noindent = %v, want true", params[2])
+	}
+	method := params[1].(string)
+
+	var got []string
+	if err := s.Request(ctx, method, &got, []string{"\x1b[31ma\x1b[0m\r", "b"}, int(paste.Single)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pasted %q, want %q", got, want)
+	}
+
+	if err := h.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Close removes this handler only.
+	calls := s.Calls()
+	last := calls[len(calls)-1]
+	if want := []interface{}{int64(1), method}; last.Method != "nvim_exec_lua" || !reflect.DeepEqual(last.Args[1], want) {
+		t.Errorf("Close called %v, want nvim_exec_lua with %v", last, want)
+	}
+}
+
+func TestPasteError(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	installed := mockPaste(s)
+	ctx := context.Background()
+
+	errFull := errors.New("full")
+	errs := make(chan error, 1)
+	_, err := paste.Install(ctx, v, func(ctx context.Context, c paste.Chunk) ([]string, error) {
+		return nil, errFull
+	}, paste.WithErrorHandler(func(err error) { errs <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := (<-installed)[1].(string)
+
+	var got interface{}
+	if err := s.Request(ctx, method, &got, []string{"a"}, int(paste.Start)); err != nil {
+		t.Fatal(err)
+	}
+	if got != false {
+		t.Errorf("got %v, want false", got)
+	}
+	if err := <-errs; err != errFull {
+		t.Errorf("error handler got %v, want %v", err, errFull)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	installed := mockPaste(s)
+	ctx := context.Background()
+
+	h, err := paste.Install(ctx, v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := (<-installed)[1].(string)
+
+	var got interface{}
+	h.Cancel()
+	if err := s.Request(ctx, method, &got, []string{"a"}, int(paste.Continue)); err != nil {
+		t.Fatal(err)
+	}
+	if got != false {
+		t.Errorf("after Cancel: got %v, want false", got)
+	}
+	// A new paste is not canceled.
+	if err := s.Request(ctx, method, &got, []string{"a"}, int(paste.Start)); err != nil {
+		t.Fatal(err)
+	}
+	if got == false {
+		t.Error("new paste canceled")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package quickfix
+
+import (
+	"strconv"
+	"strings"
+)
+
+// key identifies an item across lists, and across sessions when its
+// Filename is set.
+type key struct {
+	file      string
+	lnum, col int
+	typ, text string
+}
+
+func (it *Item) key() key {
+	file := it.Filename
+	if file == "" {
+		file = "#" + strconv.Itoa(it.Bufnr)
+	}
+
+	return key{file: file, lnum: it.Lnum, col: it.Col, typ: it.Type, text: strings.TrimSpace(it.Text)}
+}
+
+// Merge returns a list of the items of lists, in order, without duplicates.
+// The title of the result joins the titles of lists.
+func Merge(lists ...*List) *List {
+	var titles []string
+	seen := make(map[key]bool)
+	merged := &List{}
+	for _, l := range lists {
+		if l.Title != "" {
+			titles = append(titles, l.Title)
+		}
+		for _, it := range l.Items {
+			k := it.key()
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			merged.Items = append(merged.Items, it)
+		}
+	}
+	merged.Title = strings.Join(titles, " + ")
+
+	return merged
+}
+
+// Diff returns the items of b missing from a, and the items of a missing
+// from b.
+//
+// Items are compared by file, position, type and text, so that comparing
+// the lint results of two commits reports the new and the fixed problems.
+// Items that moved to another line compare as removed and added.
+func Diff(a, b *List) (added, removed []Item) {
+	inA := make(map[key]bool, len(a.Items))
+	for i := range a.Items {
+		inA[a.Items[i].key()] = true
+	}
+	inB := make(map[key]bool, len(b.Items))
+	for i := range b.Items {
+		inB[b.Items[i].key()] = true
+	}
+
+	for _, it := range b.Items {
+		if !inA[it.key()] {
+			added = append(added, it)
+		}
+	}
+	for _, it := range a.Items {
+		if !inB[it.key()] {
+			removed = append(removed, it)
+		}
+	}
+
+	return added, removed
+}
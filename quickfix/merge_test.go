@@ -0,0 +1,63 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package quickfix_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/quickfix"
+)
+
+func TestMerge(t *testing.T) {
+	a := &quickfix.List{Title: "vet", Items: []quickfix.Item{
+		{Filename: "a.go", Lnum: 1, Col: 2, Text: "unused", Type: "W"},
+		{Bufnr: 3, Lnum: 4, Text: "x"},
+	}}
+	b := &quickfix.List{Items: []quickfix.Item{
+		{Filename: "a.go", Lnum: 1, Col: 2, Text: " unused\n", Type: "W", Nr: 9}, // same as a's first
+		{Bufnr: 4, Lnum: 4, Text: "x"},                                           // another buffer
+	}}
+	c := &quickfix.List{Title: "lint", Items: []quickfix.Item{
+		{Filename: "a.go", Lnum: 1, Col: 2, Text: "unused", Type: "E"}, // another type
+		{Bufnr: 3, Lnum: 4, Text: "x"},
+	}}
+
+	got := quickfix.Merge(a, b, c)
+	want := &quickfix.List{Title: "vet + lint", Items: []quickfix.Item{
+		a.Items[0], a.Items[1], b.Items[1], c.Items[0],
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+	if got := quickfix.Merge(); got.Title != "" || len(got.Items) != 0 {
+		t.Errorf("Merge() of no lists = %+v", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &quickfix.List{Items: []quickfix.Item{
+		{Filename: "a.go", Lnum: 1, Text: "fixed"},
+		{Filename: "a.go", Lnum: 2, Text: "kept"},
+		{Filename: "b.go", Lnum: 3, Text: "moved"},
+	}}
+	cur := &quickfix.List{Items: []quickfix.Item{
+		{Filename: "a.go", Lnum: 2, Text: "kept  ", Valid: true},
+		{Filename: "b.go", Lnum: 4, Text: "moved"},
+		{Filename: "c.go", Lnum: 1, Text: "new"},
+	}}
+
+	added, removed := quickfix.Diff(old, cur)
+	if want := []quickfix.Item{cur.Items[1], cur.Items[2]}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added %+v, want %+v", added, want)
+	}
+	if want := []quickfix.Item{old.Items[0], old.Items[2]}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed %+v, want %+v", removed, want)
+	}
+
+	added, removed = quickfix.Diff(old, old)
+	if added != nil || removed != nil {
+		t.Errorf("Diff() of a list with itself = %+v, %+v", added, removed)
+	}
+}
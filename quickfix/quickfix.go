@@ -0,0 +1,130 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package quickfix navigates and manages the quickfix stack.
+package quickfix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-nvim/pkg/mods"
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Item is an entry of a quickfix list.
+type Item struct {
+	Bufnr    int    `msgpack:"bufnr,omitempty" json:"-"`
+	Filename string `msgpack:"filename,omitempty" json:"filename,omitempty"`
+	Module   string `msgpack:"module,omitempty" json:"module,omitempty"`
+	Lnum     int    `msgpack:"lnum" json:"lnum"`
+	EndLnum  int    `msgpack:"end_lnum,omitempty" json:"end_lnum,omitempty"`
+	Col      int    `msgpack:"col,omitempty" json:"col,omitempty"`
+	EndCol   int    `msgpack:"end_col,omitempty" json:"end_col,omitempty"`
+	Vcol     bool   `msgpack:"vcol,omitempty" json:"vcol,omitempty"`
+	Nr       int    `msgpack:"nr,omitempty" json:"nr,omitempty"`
+	Pattern  string `msgpack:"pattern,omitempty" json:"pattern,omitempty"`
+	Text     string `msgpack:"text" json:"text"`
+	Type     string `msgpack:"type,omitempty" json:"type,omitempty"`
+	Valid    bool   `msgpack:"valid,omitempty" json:"valid,omitempty"`
+}
+
+// List is a quickfix list.
+type List struct {
+	ID    int    `msgpack:"id" json:"-"`
+	Nr    int    `msgpack:"nr" json:"-"`
+	Title string `msgpack:"title" json:"title"`
+	Items []Item `msgpack:"items" json:"items"`
+}
+
+// getqflist calls getqflist(what) and stores the result in result.
+func getqflist(ctx context.Context, v *nvim.Nvim, what map[string]interface{}, result interface{}) error {
+	return v.Call(ctx, "nvim_call_function", result, "getqflist", []interface{}{what})
+}
+
+// Get returns the list number nr of the quickfix stack, or the current
+// list if nr is 0.
+//
+// The Filename of the items is resolved from their buffer.
+func Get(ctx context.Context, v *nvim.Nvim, nr int) (*List, error) {
+	var l List
+	what := map[string]interface{}{"nr": nr, "id": 0, "title": 1, "items": 1}
+	if err := getqflist(ctx, v, what, &l); err != nil {
+		return nil, err
+	}
+	if l.ID == 0 {
+		return nil, fmt.Errorf("quickfix: no list %d", nr)
+	}
+
+	names := make(map[int]string)
+	for i := range l.Items {
+		it := &l.Items[i]
+		if it.Bufnr == 0 || it.Filename != "" {
+			continue
+		}
+		name, ok := names[it.Bufnr]
+		if !ok {
+			var err error
+			if name, err = v.BufGetName(ctx, types.Buffer(it.Bufnr)); err != nil {
+				return nil, err
+			}
+			names[it.Bufnr] = name
+		}
+		it.Filename = name
+	}
+
+	return &l, nil
+}
+
+// Stack returns the number of the current list and the number of lists of
+// the quickfix stack.
+func Stack(ctx context.Context, v *nvim.Nvim) (current, size int, err error) {
+	var cur, last struct {
+		Nr int `msgpack:"nr"`
+	}
+	if err := getqflist(ctx, v, map[string]interface{}{"nr": 0}, &cur); err != nil {
+		return 0, 0, err
+	}
+	if err := getqflist(ctx, v, map[string]interface{}{"nr": "$"}, &last); err != nil {
+		return 0, 0, err
+	}
+
+	return cur.Nr, last.Nr, nil
+}
+
+// Older makes the list count levels older than the current list, like :colder.
+func Older(ctx context.Context, v *nvim.Nvim, count int) error {
+	return mods.Mods{}.Silent().Command(ctx, v, fmt.Sprintf("%dcolder", count))
+}
+
+// Newer makes the list count levels newer than the current list, like :cnewer.
+func Newer(ctx context.Context, v *nvim.Nvim, count int) error {
+	return mods.Mods{}.Silent().Command(ctx, v, fmt.Sprintf("%dcnewer", count))
+}
+
+// Push adds l at the end of the quickfix stack and makes it the current
+// list. Items are located by Filename when it is set, and by Bufnr
+// otherwise.
+func Push(ctx context.Context, v *nvim.Nvim, l *List) error {
+	items := make([]Item, len(l.Items))
+	for i, it := range l.Items {
+		if it.Filename != "" {
+			it.Bufnr = 0
+		}
+		items[i] = it
+	}
+
+	// Without "nr", setqflist() adds the list after the current one and
+	// frees the newer lists.
+	what := map[string]interface{}{"nr": "$", "title": l.Title, "items": items}
+	var ok int
+	if err := v.Call(ctx, "nvim_call_function", &ok, "setqflist", []interface{}{[]interface{}{}, " ", what}); err != nil {
+		return err
+	}
+	if ok != 0 {
+		return fmt.Errorf("quickfix: cannot set list %q", l.Title)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package quickfix_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/quickfix"
+)
+
+func TestPushAtEnd(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_call_function", 0)
+
+	l := &quickfix.List{Title: "grep", Items: []quickfix.Item{{Filename: "a.go", Lnum: 1, Text: "x"}}}
+	if err := quickfix.Push(context.Background(), v, l); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_call_function", "setqflist", []interface{}{
+		[]interface{}{},
+		" ",
+		map[string]interface{}{
+			"nr":    "$",
+			"title": "grep",
+			"items": []interface{}{map[string]interface{}{"filename": "a.go", "lnum": 1, "text": "x"}},
+		},
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package quickfix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-nvim/pkg/nvim"
+)
+
+// Store persists named quickfix lists as JSON files in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a Store of the lists saved in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// StateStore returns the Store of the lists saved in the quickfix
+// directory of stdpath('state').
+func StateStore(ctx context.Context, v *nvim.Nvim) (*Store, error) {
+	var state string
+	if err := v.Call(ctx, "nvim_call_function", &state, "stdpath", []interface{}{"state"}); err != nil {
+		return nil, err
+	}
+
+	return NewStore(filepath.Join(state, "quickfix")), nil
+}
+
+// path returns the file of the list name.
+func (s *Store) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("quickfix: invalid list name %q", name)
+	}
+
+	return filepath.Join(s.dir, name+".json"), nil
+}
+
+// Save saves l as name, replacing any list of that name.
+func (s *Store) Save(name string, l *List) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	// Write atomically, so that a crash cannot leave a truncated list.
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load returns the list saved as name.
+func (s *Store) Load(name string) (*List, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("quickfix: no list named %q", name)
+		}
+		return nil, err
+	}
+
+	var l List
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("quickfix: list %q: %w", name, err)
+	}
+
+	return &l, nil
+}
+
+// Delete deletes the list saved as name.
+func (s *Store) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Names returns the sorted names of the saved lists.
+func (s *Store) Names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package quickfix_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/quickfix"
+)
+
+func TestStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "quickfix")
+	s := quickfix.NewStore(dir)
+
+	if names, err := s.Names(); err != nil || names != nil {
+		t.Errorf("Names() of a missing directory = %v, %v", names, err)
+	}
+
+	l := &quickfix.List{ID: 3, Nr: 1, Title: "grep", Items: []quickfix.Item{
+		{Bufnr: 2, Filename: "a.go", Lnum: 1, Col: 3, Text: "x", Type: "E", Valid: true},
+	}}
+	if err := s.Save("grep", l); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("b", &quickfix.List{Title: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Load("grep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The IDs, numbers and buffers are not persisted.
+	want := &quickfix.List{Title: "grep", Items: []quickfix.Item{
+		{Filename: "a.go", Lnum: 1, Col: 3, Text: "x", Type: "E", Valid: true},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	// Saving replaces the list and leaves no temporary file.
+	l.Title = "grep again"
+	if err := s.Save("grep", l); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Load("grep"); err != nil || got.Title != "grep again" {
+		t.Errorf("Load() after Save = %+v, %v", got, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, e := range entries {
+		files = append(files, e.Name())
+	}
+	if want := []string{"b.json", "grep.json"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("files %v, want %v", files, want)
+	}
+
+	// Other files are not lists.
+	os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644)
+	os.Mkdir(filepath.Join(dir, "dir.json"), 0o755)
+	if names, err := s.Names(); err != nil || !reflect.DeepEqual(names, []string{"b", "grep"}) {
+		t.Errorf("Names() = %v, %v; want [b grep]", names, err)
+	}
+
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("b"); err == nil {
+		t.Error("Load() of a deleted list succeeded")
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	s := quickfix.NewStore(dir)
+
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`} {
+		if err := s.Save(name, &quickfix.List{}); err == nil {
+			t.Errorf("Save(%q) succeeded", name)
+		}
+		if _, err := s.Load(name); err == nil {
+			t.Errorf("Load(%q) succeeded", name)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("invalid names wrote %d files", len(entries))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("bad"); err == nil {
+		t.Error("Load() of a corrupt list succeeded")
+	}
+}
@@ -0,0 +1,458 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package rpc implements a msgpack-RPC client for Neovim.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// List of msgpack-RPC message types.
+const (
+	requestMessage      = 0
+	responseMessage     = 1
+	notificationMessage = 2
+)
+
+// Client is a msgpack-RPC client.
+//
+// A Client multiplexes concurrent calls over a single connection. All its
+// methods are safe for concurrent use, including from notification
+// handlers. Calls issued after the connection is closed fail with an error
+// wrapping ErrClosed.
+type Client struct {
+	conn io.ReadWriteCloser
+	dec  *msgpack.Decoder
+
+	wmu sync.Mutex // serializes writes
+	out countingWriter
+	enc *msgpack.Encoder
+
+	mu           sync.Mutex
+	seq          uint32
+	pending      map[uint32]*call
+	handlers     map[string]func(args []interface{})
+	fallback     func(method string, args []interface{})
+	invalid      func(method string, err error)
+	requests     map[string]*requestHandler
+	interceptors []Interceptor
+	queue        []*notification
+	closed       bool
+	err          error
+
+	trackCallers atomic.Bool // see TrackCallers
+
+	wake chan struct{} // signals queued notifications
+	done chan struct{} // closed when the connection is closed
+
+	ctx    context.Context // canceled when the connection is closed
+	cancel context.CancelFunc
+}
+
+// call is a pending call.
+type call struct {
+	ch      chan *response
+	ctx     context.Context
+	stream  func(elem msgpack.RawMessage) error // see CallStream
+	method  string
+	tag     string
+	caller  *callerPCs
+	started time.Time
+}
+
+type response struct {
+	err       interface{}
+	result    msgpack.RawMessage // nil for streamed results
+	size      int                // size of the encoded result
+	streamErr error
+}
+
+type notification struct {
+	method string
+	args   []interface{}
+	size   int // size of the encoded args
+}
+
+// NewClient returns a new Client communicating over conn.
+//
+// The Client reads from conn until it is closed, or until conn returns an
+// error.
+func NewClient(conn io.ReadWriteCloser) *Client {
+	c := &Client{
+		conn:     conn,
+		dec:      msgpack.NewDecoder(conn),
+		pending:  make(map[uint32]*call),
+		handlers: make(map[string]func(args []interface{})),
+		requests: make(map[string]*requestHandler),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	c.out.w = conn
+	c.enc = msgpack.NewEncoder(&c.out)
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	go c.read()
+	go c.dispatch()
+
+	return c
+}
+
+// Call calls the remote method with args and stores the result in the value
+// pointed to by result. A nil result discards the response.
+//
+// Call returns ctx.Err() if ctx is done before the response is received,
+// and an *Error if the remote end returns an error.
+func (c *Client) Call(ctx context.Context, method string, result interface{}, args ...interface{}) error {
+	info := &Info{Kind: KindCall, Method: method}
+
+	return c.intercept(ctx, info, func(ctx context.Context) error {
+		r, err := c.roundTrip(ctx, &call{method: method}, args, info)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return nil
+		}
+		if err := msgpack.Unmarshal(r.result, result); err != nil {
+			return fmt.Errorf("rpc: %s: %w", method, err)
+		}
+		return nil
+	})
+}
+
+// roundTrip sends the request of pc with args and waits for its response,
+// recording the sizes of both in info.
+func (c *Client) roundTrip(ctx context.Context, pc *call, args []interface{}, info *Info) (*response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	pc.ch = make(chan *response, 1)
+	pc.ctx = ctx
+	pc.tag = Tag(ctx)
+	pc.caller = c.caller()
+	pc.started = time.Now()
+	c.mu.Lock()
+	if c.closed {
+		err := c.err
+		c.mu.Unlock()
+		return nil, fmt.Errorf("rpc: %s called after shutdown: %w", pc.method, err)
+	}
+	c.seq++
+	id := c.seq
+	c.pending[id] = pc
+	c.mu.Unlock()
+
+	n, err := c.write([]interface{}{requestMessage, id, pc.method, params(args)})
+	info.RequestSize = n
+	if err != nil {
+		c.forget(id)
+		if cerr := c.Err(); cerr != nil {
+			// The write failed because the connection closed.
+			return nil, fmt.Errorf("rpc: %s: %w", pc.method, cerr)
+		}
+		return nil, err
+	}
+
+	select {
+	case r := <-pc.ch:
+		info.ResponseSize = r.size
+		if r.err != nil {
+			return nil, newError(r.err)
+		}
+		if r.streamErr != nil {
+			return nil, r.streamErr
+		}
+		return r, nil
+
+	case <-ctx.Done():
+		c.forget(id)
+		return nil, ctx.Err()
+
+	case <-c.done:
+		c.forget(id)
+		return nil, c.Err()
+	}
+}
+
+// Notify sends a notification of the remote method with args.
+func (c *Client) Notify(ctx context.Context, method string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-c.done:
+		return fmt.Errorf("rpc: %s notified after shutdown: %w", method, c.Err())
+	default:
+	}
+
+	info := &Info{Kind: KindNotify, Method: method}
+
+	return c.intercept(ctx, info, func(ctx context.Context) error {
+		n, err := c.write([]interface{}{notificationMessage, method, params(args)})
+		info.RequestSize = n
+		return err
+	})
+}
+
+// Handle registers fn as the handler for notifications sent to method.
+// A nil fn removes the handler.
+//
+// Handlers are called one at a time, in the order the notifications are
+// received, and may make calls with the Client. So that they can, the
+// notifications received meanwhile are queued without limit: a handler
+// that falls behind the notifications makes the queue grow.
+func (c *Client) Handle(method string, fn func(args []interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fn == nil {
+		delete(c.handlers, method)
+		return
+	}
+	c.handlers[method] = fn
+}
+
+// HandleInvalid registers fn to be called with the errors of the
+// notifications whose arguments cannot be decoded, which are dropped. A nil
+// fn removes the handler.
+//
+// fn is called while reading the connection, so it must not make calls
+// with the Client.
+func (c *Client) HandleInvalid(fn func(method string, err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalid = fn
+}
+
+// HandleDefault registers fn as the handler for notifications of methods
+// without a handler. A nil fn removes the handler.
+func (c *Client) HandleDefault(fn func(method string, args []interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fallback = fn
+}
+
+// Close closes the connection. Pending calls return ErrClosed.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.err = ErrClosed
+	c.mu.Unlock()
+
+	return c.conn.Close()
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that closed the connection, or nil if it is open.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// params returns args as the params array of a message.
+func params(args []interface{}) []interface{} {
+	if args == nil {
+		return []interface{}{}
+	}
+
+	return args
+}
+
+// write writes msg and returns the number of bytes written.
+func (c *Client) write(msg []interface{}) (int, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	start := c.out.n
+	if err := c.enc.Encode(msg); err != nil {
+		return c.out.n - start, fmt.Errorf("rpc: write: %w", err)
+	}
+
+	return c.out.n - start, nil
+}
+
+// forget removes the pending call id.
+func (c *Client) forget(id uint32) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// read reads messages until the connection fails.
+func (c *Client) read() {
+	var err error
+	for err == nil {
+		err = c.readMessage()
+	}
+
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			c.err = ErrClosed
+		} else {
+			c.err = fmt.Errorf("rpc: read: %w", err)
+		}
+		c.conn.Close()
+	}
+	c.pending = make(map[uint32]*call)
+	c.mu.Unlock()
+
+	c.cancel()
+	close(c.done)
+}
+
+func (c *Client) readMessage() error {
+	n, err := c.dec.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+	typ, err := c.dec.DecodeInt()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case typ == requestMessage && n == 4:
+		var req struct {
+			ID     uint32
+			Method string
+			Params msgpack.RawMessage
+		}
+		if err := c.decodeRest(&req.ID, &req.Method, &req.Params); err != nil {
+			return err
+		}
+		go c.serve(req.ID, req.Method, req.Params)
+
+	case typ == responseMessage && n == 4:
+		var id uint32
+		r := &response{}
+		if err := c.decodeRest(&id, &r.err); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		pc, ok := c.pending[id]
+		delete(c.pending, id)
+		c.mu.Unlock()
+		if ok && pc.stream != nil && r.err == nil {
+			if err := c.readStream(pc, r); err != nil {
+				return err
+			}
+		} else {
+			if err := c.dec.Decode(&r.result); err != nil {
+				return err
+			}
+			r.size = len(r.result)
+		}
+		if ok {
+			pc.ch <- r
+		}
+
+	case typ == notificationMessage && n == 3:
+		m := &notification{}
+		var raw msgpack.RawMessage
+		if err := c.decodeRest(&m.method, &raw); err != nil {
+			return err
+		}
+		if err := msgpack.Unmarshal(raw, &m.args); err != nil {
+			// The message was read whole, so the connection is
+			// still in sync; drop it.
+			c.mu.Lock()
+			fn := c.invalid
+			c.mu.Unlock()
+			if fn != nil {
+				fn(m.method, err)
+			}
+			return nil
+		}
+		m.size = len(raw)
+		c.mu.Lock()
+		c.queue = append(c.queue, m)
+		c.mu.Unlock()
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+
+	default:
+		return fmt.Errorf("invalid message of type %d and length %d", typ, n)
+	}
+
+	return nil
+}
+
+// decodeRest decodes the remaining elements of a message into vs.
+func (c *Client) decodeRest(vs ...interface{}) error {
+	for _, v := range vs {
+		if err := c.dec.Decode(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatch calls the handlers of queued notifications in order. It runs
+// apart from read so that handlers can make calls.
+func (c *Client) dispatch() {
+	for {
+		closed := false
+		select {
+		case <-c.wake:
+		case <-c.done:
+			// Deliver the notifications received before the
+			// connection closed.
+			closed = true
+		}
+
+		for {
+			c.mu.Lock()
+			if len(c.queue) == 0 {
+				c.mu.Unlock()
+				break
+			}
+			m := c.queue[0]
+			c.queue[0] = nil
+			c.queue = c.queue[1:]
+			fn := c.handlers[m.method]
+			fallback := c.fallback
+			c.mu.Unlock()
+
+			if fn == nil && fallback == nil {
+				continue
+			}
+			info := &Info{Kind: KindNotification, Method: m.method, RequestSize: m.size}
+			c.intercept(c.ctx, info, func(context.Context) error {
+				if fn != nil {
+					fn(m.args)
+				} else {
+					fallback(m.method, m.args)
+				}
+				return nil
+			})
+		}
+		if closed {
+			return
+		}
+	}
+}
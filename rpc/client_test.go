@@ -0,0 +1,293 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// peer is the remote end of a Client under test.
+type peer struct {
+	t    *testing.T
+	conn net.Conn
+	dec  *msgpack.Decoder
+
+	wmu sync.Mutex
+	enc *msgpack.Encoder
+}
+
+// newPair returns a Client connected to a peer serving its requests with
+// fn, each in its own goroutine. The client and the peer are closed when
+// the test ends.
+func newPair(t *testing.T, fn func(p *peer, id uint32, method string, params []interface{})) (*Client, *peer) {
+	t.Helper()
+
+	a, b := net.Pipe()
+	c := NewClient(a)
+	t.Cleanup(func() { c.Close() })
+
+	return c, newPeer(t, b, fn)
+}
+
+// newPeer returns a peer serving the requests read from conn with fn, each
+// in its own goroutine. conn is closed when the test ends.
+func newPeer(t *testing.T, conn net.Conn, fn func(p *peer, id uint32, method string, params []interface{})) *peer {
+	p := &peer{t: t, conn: conn, dec: msgpack.NewDecoder(conn), enc: msgpack.NewEncoder(conn)}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		for {
+			var msg []interface{}
+			if err := p.dec.Decode(&msg); err != nil {
+				return
+			}
+			if len(msg) != 4 || msg[0] != int64(requestMessage) {
+				continue
+			}
+			id, _ := msg[1].(int64)
+			method, _ := msg[2].(string)
+			params, _ := msg[3].([]interface{})
+			if fn != nil {
+				go fn(p, uint32(id), method, params)
+			}
+		}
+	}()
+
+	return p
+}
+
+// send sends the message msg to the Client.
+func (p *peer) send(msg ...interface{}) {
+	p.wmu.Lock()
+	defer p.wmu.Unlock()
+
+	// Errors only happen once the test is over and the pipe closed.
+	p.enc.Encode(msg)
+}
+
+// respond sends the response of request id.
+func (p *peer) respond(id uint32, err, result interface{}) {
+	p.send(responseMessage, id, err, result)
+}
+
+// echo responds to every request with its params.
+func echo(p *peer, id uint32, method string, params []interface{}) {
+	p.respond(id, nil, params)
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	c, _ := newPair(t, func(p *peer, id uint32, method string, params []interface{}) {
+		// Respond out of order.
+		time.Sleep(time.Duration(id%7) * time.Millisecond)
+		echo(p, id, method, params)
+	})
+
+	const goroutines, calls = 32, 50
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				want := fmt.Sprintf("%d/%d", g, i)
+				var got []string
+				if err := c.Call(context.Background(), "echo", &got, want); err != nil {
+					errs <- err
+					return
+				}
+				if len(got) != 1 || got[0] != want {
+					errs <- fmt.Errorf("got %q, want [%q]", got, want)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if n := len(c.Pending()); n != 0 {
+		t.Errorf("%d calls still pending", n)
+	}
+}
+
+func TestCallError(t *testing.T) {
+	c, _ := newPair(t, func(p *peer, id uint32, method string, params []interface{}) {
+		p.respond(id, []interface{}{int64(ValidationError), "bad " + method}, nil)
+	})
+
+	err := c.Call(context.Background(), "m", nil)
+	var e *Error
+	if !errors.As(err, &e) || e.Type != ValidationError || e.Message != "bad m" {
+		t.Errorf("got %v, want a validation error", err)
+	}
+}
+
+func TestCallCanceled(t *testing.T) {
+	c, _ := newPair(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Call(ctx, "never", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := len(c.Pending()); n != 0 {
+		t.Errorf("%d calls still pending", n)
+	}
+}
+
+func TestConcurrentClose(t *testing.T) {
+	c, _ := newPair(t, nil) // never responds
+
+	const calls = 32
+	var started, done sync.WaitGroup
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			errs <- c.Call(context.Background(), "never", nil)
+		}()
+	}
+	started.Wait()
+	for len(c.Pending()) < calls {
+		time.Sleep(time.Millisecond)
+	}
+
+	var closers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		closers.Add(1)
+		go func() {
+			defer closers.Done()
+			c.Close()
+		}()
+	}
+	closers.Wait()
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("got %v, want %v", err, ErrClosed)
+		}
+	}
+	<-c.Done()
+	if err := c.Err(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Err() = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestAfterShutdown(t *testing.T) {
+	c, p := newPair(t, echo)
+
+	// The remote end going away closes the Client.
+	p.conn.Close()
+	<-c.Done()
+
+	if err := c.Call(context.Background(), "echo", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Call: got %v, want %v", err, ErrClosed)
+	}
+	if err := c.Notify(context.Background(), "echo"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Notify: got %v, want %v", err, ErrClosed)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestNotificationDuringCall(t *testing.T) {
+	release := make(chan struct{})
+	c, _ := newPair(t, func(p *peer, id uint32, method string, params []interface{}) {
+		switch method {
+		case "slow":
+			// Notify before responding; the handler calls back into
+			// the Client while this call is pending.
+			p.send(notificationMessage, "event", []interface{}{"a"})
+			<-release
+			p.respond(id, nil, "slow")
+		default:
+			echo(p, id, method, params)
+		}
+	})
+
+	got := make(chan string, 1)
+	c.Handle("event", func(args []interface{}) {
+		var res []string
+		if err := c.Call(context.Background(), "echo", &res, args...); err != nil {
+			t.Error(err)
+		}
+		got <- strings.Join(res, ",")
+		close(release)
+	})
+
+	var res string
+	if err := c.Call(context.Background(), "slow", &res); err != nil {
+		t.Fatal(err)
+	}
+	if res != "slow" {
+		t.Errorf("got %q, want %q", res, "slow")
+	}
+	if s := <-got; s != "a" {
+		t.Errorf("handler got %q, want %q", s, "a")
+	}
+}
+
+func TestNotificationOrder(t *testing.T) {
+	c, p := newPair(t, nil)
+
+	const n = 200
+	got := make(chan int, n)
+	c.Handle("seq", func(args []interface{}) {
+		i, _ := args[0].(int64)
+		got <- int(i)
+	})
+	go func() {
+		for i := 0; i < n; i++ {
+			p.send(notificationMessage, "seq", []interface{}{i})
+		}
+	}()
+
+	for want := 0; want < n; want++ {
+		if i := <-got; i != want {
+			t.Fatalf("got notification %d, want %d", i, want)
+		}
+	}
+}
+
+func TestInvalidNotification(t *testing.T) {
+	c, p := newPair(t, echo)
+
+	got := make(chan []interface{}, 1)
+	c.Handle("event", func(args []interface{}) { got <- args })
+	invalid := make(chan string, 1)
+	c.HandleInvalid(func(method string, err error) { invalid <- method })
+
+	// Params that are not an array are dropped without closing the
+	// connection.
+	p.send(notificationMessage, "event", map[string]interface{}{"a": 1})
+	p.send(notificationMessage, "event", []interface{}{"ok"})
+	if args := <-got; len(args) != 1 || args[0] != "ok" {
+		t.Errorf("got %v, want [ok]", args)
+	}
+	if method := <-invalid; method != "event" {
+		t.Errorf("invalid notification of %q, want %q", method, "event")
+	}
+	if err := c.Call(context.Background(), "echo", nil); err != nil {
+		t.Errorf("Call after an invalid notification: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClosed is returned by calls on a closed Client.
+var ErrClosed = errors.New("rpc: client closed")
+
+// ErrorType is the type of an error returned by Neovim.
+type ErrorType int
+
+// List of Neovim error types.
+const (
+	ExceptionError  ErrorType = 0
+	ValidationError ErrorType = 1
+)
+
+func (t ErrorType) String() string {
+	switch t {
+	case ExceptionError:
+		return "Exception"
+	case ValidationError:
+		return "Validation"
+	}
+
+	return fmt.Sprintf("ErrorType(%d)", int(t))
+}
+
+// Error is an error returned by the remote end of a call.
+type Error struct {
+	Type    ErrorType
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target matches e, for errors.Is. Targets classifying
+// Neovim errors, such as those of package nverror, implement a MatchError
+// method reporting whether they match.
+func (e *Error) Is(target error) bool {
+	m, ok := target.(interface{ MatchError(*Error) bool })
+
+	return ok && m.MatchError(e)
+}
+
+// newError returns the Error of the error value v of a response.
+//
+// Neovim sends errors as a [type, message] array; other peers may send any
+// value.
+func newError(v interface{}) *Error {
+	switch v := v.(type) {
+	case []interface{}:
+		if len(v) == 2 {
+			t, ok1 := v[0].(int64)
+			msg, ok2 := v[1].(string)
+			if ok1 && ok2 {
+				return &Error{Type: ErrorType(t), Message: msg}
+			}
+		}
+	case string:
+		return &Error{Message: v}
+	}
+
+	return &Error{Message: fmt.Sprint(v)}
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"context"
+	"io"
+)
+
+// Kind is the kind of a message seen by an Interceptor.
+type Kind int
+
+// List of message kinds.
+const (
+	KindCall         Kind = iota // outgoing call
+	KindNotify                   // outgoing notification
+	KindNotification             // incoming notification
+	KindRequest                  // incoming request
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindCall:
+		return "call"
+	case KindNotify:
+		return "notify"
+	case KindNotification:
+		return "notification"
+	case KindRequest:
+		return "request"
+	}
+
+	return "unknown"
+}
+
+// Info describes a message seen by an Interceptor.
+type Info struct {
+	Kind   Kind
+	Method string
+
+	// RequestSize is the size in bytes of the encoded message, or of the
+	// params of an incoming message. ResponseSize is the size in bytes of
+	// the encoded result of a call. Both are set by the time invoke
+	// returns.
+	RequestSize  int
+	ResponseSize int
+}
+
+// Interceptor intercepts the calls and notifications of a Client, and the
+// handling of the notifications and requests it receives, to log or
+// measure them. It must call invoke once, which sends the message or
+// handles it, and should return its error.
+type Interceptor func(ctx context.Context, info *Info, invoke func(ctx context.Context) error) error
+
+// Use adds interceptors to the Client. The first interceptor added is the
+// outermost.
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interceptors = append(c.interceptors[:len(c.interceptors):len(c.interceptors)], interceptors...)
+}
+
+// intercept calls invoke through the interceptors of the Client.
+func (c *Client) intercept(ctx context.Context, info *Info, invoke func(ctx context.Context) error) error {
+	c.mu.Lock()
+	ics := c.interceptors
+	c.mu.Unlock()
+
+	for i := len(ics) - 1; i >= 0; i-- {
+		ic, next := ics[i], invoke
+		invoke = func(ctx context.Context) error { return ic(ctx, info, next) }
+	}
+
+	return invoke(ctx)
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+
+	return n, err
+}
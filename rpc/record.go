@@ -0,0 +1,400 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// Directions of the frames of a recording. A recording is a sequence of
+// [direction, message] arrays.
+const (
+	sentFrame     = 0 // written by the client
+	receivedFrame = 1 // read by the client
+)
+
+// RecordOption configures Record.
+type RecordOption func(*recorder)
+
+// Redact replaces every string of the recorded messages, including map
+// keys, with fn of it, such as to remove the paths of the machine
+// recording a session from fixtures.
+func Redact(fn func(s string) string) RecordOption {
+	return func(r *recorder) { r.redact = fn }
+}
+
+// RedactPath replaces path with repl in every string of the recorded
+// messages.
+func RedactPath(path, repl string) RecordOption {
+	return Redact(func(s string) string { return strings.ReplaceAll(s, path, repl) })
+}
+
+// recorder is a connection recording its messages.
+type recorder struct {
+	conn   io.ReadWriteCloser
+	redact func(string) string
+
+	mu             sync.Mutex // serializes the frames
+	enc            *msgpack.Encoder
+	sent, received framer
+	err            error // error of the recording, which stops it
+}
+
+// Record returns a connection forwarding to conn, which writes the
+// messages sent and received over it to w, to be served back by Replay.
+//
+// Messages sent are recorded before they are forwarded, so that the
+// recording always has a call before its response.
+func Record(conn io.ReadWriteCloser, w io.Writer, opts ...RecordOption) io.ReadWriteCloser {
+	r := &recorder{conn: conn, enc: msgpack.NewEncoder(w)}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	n, err := r.conn.Read(p)
+	if n > 0 {
+		r.record(receivedFrame, &r.received, p[:n])
+	}
+
+	return n, err
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.record(sentFrame, &r.sent, p)
+
+	return r.conn.Write(p)
+}
+
+func (r *recorder) Close() error {
+	return r.conn.Close()
+}
+
+// record records the messages completed by p in the stream of f.
+func (r *recorder) record(dir int, f *framer, p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	msgs, err := f.add(p)
+	for _, msg := range msgs {
+		if r.redact != nil {
+			if msg, err = redactMessage(msg, r.redact); err != nil {
+				break
+			}
+		}
+		if err = r.enc.Encode([]interface{}{dir, msg}); err != nil {
+			break
+		}
+	}
+	r.err = err
+}
+
+// framer splits a stream into messages.
+type framer struct {
+	buf  []byte
+	off  int // size of the elements of the first message of buf scanned
+	left int // number of elements of that message left to scan
+}
+
+// add appends p to the stream and returns the messages it completes. The
+// elements are scanned once, however the messages are split.
+func (f *framer) add(p []byte) ([]msgpack.RawMessage, error) {
+	f.buf = append(f.buf, p...)
+
+	var msgs []msgpack.RawMessage
+	for len(f.buf) > 0 {
+		if f.left == 0 {
+			f.left = 1
+		}
+		for f.left > 0 {
+			_, size, children, err := element(f.buf[f.off:])
+			if err == io.ErrUnexpectedEOF {
+				return msgs, nil
+			}
+			if err != nil {
+				return msgs, err
+			}
+			f.off += size
+			f.left += children - 1
+		}
+		msgs = append(msgs, msgpack.RawMessage(f.buf[:f.off:f.off]))
+		f.buf, f.off = f.buf[f.off:], 0
+	}
+	f.buf = nil
+
+	return msgs, nil
+}
+
+// format is the layout of the msgpack elements of a code: the size of
+// their length, the size of their data besides the length, and whether the
+// length counts bytes, elements or pairs of elements.
+type format struct {
+	n, fixed int
+	elems    int // 0: bytes, 1: elements, 2: pairs
+}
+
+// formats are the formats of the codes other than the fixed ones.
+var formats = map[byte]format{
+	0xc4: {1, 0, 0}, 0xc5: {2, 0, 0}, 0xc6: {4, 0, 0}, // bin
+	0xc7: {1, 1, 0}, 0xc8: {2, 1, 0}, 0xc9: {4, 1, 0}, // ext
+	0xca: {0, 4, 0}, 0xcb: {0, 8, 0}, // float
+	0xcc: {0, 1, 0}, 0xcd: {0, 2, 0}, 0xce: {0, 4, 0}, 0xcf: {0, 8, 0}, // uint
+	0xd0: {0, 1, 0}, 0xd1: {0, 2, 0}, 0xd2: {0, 4, 0}, 0xd3: {0, 8, 0}, // int
+	0xd4: {0, 2, 0}, 0xd5: {0, 3, 0}, 0xd6: {0, 5, 0}, 0xd7: {0, 9, 0}, 0xd8: {0, 17, 0}, // fixext
+	0xd9: {1, 0, 0}, 0xda: {2, 0, 0}, 0xdb: {4, 0, 0}, // str
+	0xdc: {2, 0, 1}, 0xdd: {4, 0, 1}, // array
+	0xde: {2, 0, 2}, 0xdf: {4, 0, 2}, // map
+}
+
+// element returns the size of the header and the total size of the
+// msgpack element at the start of b, not counting the elements it
+// contains, and the number of elements it contains. It returns
+// io.ErrUnexpectedEOF if b is too short to tell.
+func element(b []byte) (head, size, children int, err error) {
+	if len(b) == 0 {
+		return 0, 0, 0, io.ErrUnexpectedEOF
+	}
+	// length returns the n-byte big-endian length following the code.
+	length := func(n int) (int, bool) {
+		if len(b) < 1+n {
+			return 0, false
+		}
+		l := 0
+		for _, c := range b[1 : 1+n] {
+			l = l<<8 | int(c)
+		}
+		return l, true
+	}
+
+	code := b[0]
+	head, size = 1, 1
+	switch {
+	case code <= 0x7f || code >= 0xe0, code == 0xc0, code == 0xc2, code == 0xc3:
+	case code >= 0x80 && code <= 0x8f:
+		children = 2 * int(code&0x0f)
+	case code >= 0x90 && code <= 0x9f:
+		children = int(code & 0x0f)
+	case code >= 0xa0 && code <= 0xbf:
+		size += int(code & 0x1f)
+	default:
+		f, ok := formats[code]
+		if !ok {
+			return 0, 0, 0, fmt.Errorf("rpc: invalid msgpack code 0x%x", code)
+		}
+		l, ok := length(f.n)
+		if !ok {
+			return 0, 0, 0, io.ErrUnexpectedEOF
+		}
+		head = 1 + f.n
+		size = head + f.fixed
+		switch {
+		case f.n == 0:
+		case f.elems == 0:
+			size += l
+		default:
+			children = f.elems * l
+		}
+	}
+	if len(b) < size {
+		return 0, 0, 0, io.ErrUnexpectedEOF
+	}
+
+	return head, size, children, nil
+}
+
+// redactMessage returns msg with fn applied to its strings. The other
+// elements are copied as they are.
+func redactMessage(msg msgpack.RawMessage, fn func(string) string) (msgpack.RawMessage, error) {
+	var out []byte
+	for b := []byte(msg); len(b) > 0; {
+		head, size, _, err := element(b)
+		if err != nil {
+			return nil, err
+		}
+		if c := b[0]; c >= 0xa0 && c <= 0xbf || c >= 0xd9 && c <= 0xdb {
+			s, err := msgpack.Marshal(fn(string(b[head:size])))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s...)
+		} else {
+			out = append(out, b[:size]...)
+		}
+		b = b[size:]
+	}
+
+	return out, nil
+}
+
+// ErrReplayMismatch is returned by the reads of a replayed session when
+// the client diverges from the recording.
+var ErrReplayMismatch = errors.New("rpc: replay mismatch")
+
+// replayer is a connection serving a recorded session.
+type replayer struct {
+	sent     []msgpack.RawMessage
+	received []msgpack.RawMessage
+	after    []int // number of sent frames before each received frame
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	nsent  int // frames written by the client
+	nrecv  int // received frames served
+	buf    []byte
+	err    error
+	closed bool
+
+	w *io.PipeWriter
+}
+
+// Replay returns a connection serving the session recorded by Record read
+// from r, for deterministic tests and bug reproduction.
+//
+// The recorded messages are read by the client in order, each once the
+// client has written as many messages as it had when the message was
+// recorded. The messages written are checked against the recorded ones by
+// type and method: when they differ, reads fail with an error wrapping
+// ErrReplayMismatch. Request IDs are not checked, but a client making the
+// same calls in the same order uses the same IDs.
+func Replay(r io.Reader) (io.ReadWriteCloser, error) {
+	p := &replayer{}
+	p.cond = sync.NewCond(&p.mu)
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("rpc: read recording: %w", err)
+	}
+	br := bytes.NewReader(data)
+	dec := msgpack.NewDecoder(br)
+	for br.Len() > 0 {
+		var frame struct {
+			Dir int
+			Msg msgpack.RawMessage
+		}
+		if err := dec.Decode(&frame); err != nil {
+			return nil, fmt.Errorf("rpc: read recording: %w", err)
+		}
+		switch frame.Dir {
+		case sentFrame:
+			p.sent = append(p.sent, frame.Msg)
+		case receivedFrame:
+			p.received = append(p.received, frame.Msg)
+			p.after = append(p.after, len(p.sent))
+		default:
+			return nil, fmt.Errorf("rpc: read recording: invalid direction %d", frame.Dir)
+		}
+	}
+
+	var pr *io.PipeReader
+	pr, p.w = io.Pipe()
+	go p.check(pr)
+
+	return p, nil
+}
+
+func (p *replayer) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.buf) == 0 {
+		switch {
+		case p.err != nil:
+			return 0, p.err
+		case p.closed:
+			return 0, io.EOF
+		case p.nrecv < len(p.received) && p.after[p.nrecv] <= p.nsent:
+			p.buf = p.received[p.nrecv]
+			p.nrecv++
+		default:
+			p.cond.Wait()
+		}
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+
+	return n, nil
+}
+
+func (p *replayer) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	err := p.err
+	p.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	return p.w.Write(b)
+}
+
+func (p *replayer) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	return p.w.Close()
+}
+
+// check checks the messages written by the client against the recording.
+func (p *replayer) check(pr *io.PipeReader) {
+	dec := msgpack.NewDecoder(pr)
+	for {
+		msg, err := dec.DecodeRaw()
+		if err != nil {
+			pr.CloseWithError(err)
+			return
+		}
+
+		p.mu.Lock()
+		got := describe(msg)
+		switch {
+		case p.nsent >= len(p.sent):
+			p.err = fmt.Errorf("%w: got %s after the end of the recording", ErrReplayMismatch, got)
+		case got != describe(p.sent[p.nsent]):
+			p.err = fmt.Errorf("%w: got %s, recorded %s", ErrReplayMismatch, got, describe(p.sent[p.nsent]))
+		default:
+			p.nsent++
+		}
+		failed := p.err != nil
+		p.cond.Broadcast()
+		p.mu.Unlock()
+
+		if failed {
+			pr.CloseWithError(p.err)
+			return
+		}
+	}
+}
+
+// describe returns the type and method of the message msg.
+func describe(msg msgpack.RawMessage) string {
+	var m []interface{}
+	if err := msgpack.Unmarshal(msg, &m); err != nil || len(m) == 0 {
+		return "invalid message"
+	}
+	typ, _ := m[0].(int64)
+	switch {
+	case typ == requestMessage && len(m) == 4:
+		return fmt.Sprintf("call of %v", m[2])
+	case typ == responseMessage && len(m) == 4:
+		return "response"
+	case typ == notificationMessage && len(m) == 3:
+		return fmt.Sprintf("notification of %v", m[1])
+	}
+
+	return "invalid message"
+}
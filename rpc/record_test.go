@@ -0,0 +1,180 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// testMessages returns messages covering the msgpack formats.
+func testMessages(t *testing.T) []msgpack.RawMessage {
+	var msgs []msgpack.RawMessage
+	for _, v := range []interface{}{
+		[]interface{}{requestMessage, 1, "nvim_buf_set_lines", []interface{}{0, 0, -1, true, []string{"a", "b"}}},
+		[]interface{}{responseMessage, 1, nil, map[string]interface{}{"k": []interface{}{1.5, -1, 1 << 40, false}}},
+		[]interface{}{notificationMessage, "big", []interface{}{strings.Repeat("x", 70000), make([]byte, 300)}},
+		[]interface{}{responseMessage, 2, nil, make([]interface{}, 20)},
+	} {
+		data, err := msgpack.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, data)
+	}
+	// A float32 and an ext, which the encoder does not write.
+	msgs = append(msgs, msgpack.RawMessage{0x93, 0x01, 0xca, 0x3f, 0xc0, 0x00, 0x00, 0xd4, 0x00, 0x02})
+
+	return msgs
+}
+
+func TestFramer(t *testing.T) {
+	msgs := testMessages(t)
+	stream := bytes.Join(func() [][]byte {
+		b := make([][]byte, len(msgs))
+		for i, m := range msgs {
+			b[i] = m
+		}
+		return b
+	}(), nil)
+
+	for _, chunk := range []int{1, 7, 4096, len(stream)} {
+		var f framer
+		var got []msgpack.RawMessage
+		for p := stream; len(p) > 0; {
+			n := chunk
+			if n > len(p) {
+				n = len(p)
+			}
+			m, err := f.add(p[:n])
+			if err != nil {
+				t.Fatalf("chunks of %d: %v", chunk, err)
+			}
+			got = append(got, m...)
+			p = p[n:]
+		}
+		if len(got) != len(msgs) {
+			t.Fatalf("chunks of %d: got %d messages, want %d", chunk, len(got), len(msgs))
+		}
+		for i := range msgs {
+			if !bytes.Equal(got[i], msgs[i]) {
+				t.Errorf("chunks of %d: message %d differs", chunk, i)
+			}
+		}
+	}
+
+	var f framer
+	if _, err := f.add([]byte{0xc1}); err == nil {
+		t.Error("invalid code accepted")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	// Elements other than strings are kept as they are.
+	for _, msg := range testMessages(t) {
+		got, err := redactMessage(msg, func(s string) string { return s })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Errorf("redact without change: got % x, want % x", got, msg)
+		}
+	}
+
+	msg, _ := msgpack.Marshal([]interface{}{notificationMessage, "ev", []interface{}{map[string]interface{}{"/home/me/a": "/home/me/b"}}})
+	got, err := redactMessage(msg, func(s string) string { return strings.ReplaceAll(s, "/home/me", "~") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v []interface{}
+	if err := msgpack.Unmarshal(got, &v); err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{int64(notificationMessage), "ev", []interface{}{map[string]interface{}{"~/a": "~/b"}}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("got %v, want %v", v, want)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	var rec bytes.Buffer
+	a, b := net.Pipe()
+	c := NewClient(Record(a, &rec, RedactPath("/secret", "/x")))
+	newPeer(t, b, func(p *peer, id uint32, method string, params []interface{}) {
+		p.send(notificationMessage, "progress", []interface{}{method})
+		echo(p, id, method, params)
+	})
+
+	// session makes the calls of the recorded session with c, returning
+	// their results and the notifications received.
+	session := func(c *Client) ([]string, error) {
+		var got []string
+		events := make(chan string, 2)
+		c.Handle("progress", func(args []interface{}) { events <- args[0].(string) })
+		for _, arg := range []string{"/secret/a", "b"} {
+			var res []string
+			if err := c.Call(context.Background(), "echo", &res, arg); err != nil {
+				return got, err
+			}
+			got = append(got, res...)
+			got = append(got, "event:"+<-events)
+		}
+		return got, nil
+	}
+
+	want, err := session(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	conn, err := Replay(bytes.NewReader(rec.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewClient(conn)
+	defer rc.Close()
+	got, err := session(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want[0] = "/x/a" // redacted in the recording
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("replayed %v, want %v", got, want)
+	}
+
+	// A call not in the recording fails.
+	if err := rc.Call(context.Background(), "other", nil); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("call after the recording: got %v, want a replay mismatch", err)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	var rec bytes.Buffer
+	a, b := net.Pipe()
+	c := NewClient(Record(a, &rec))
+	newPeer(t, b, echo)
+	if err := c.Call(context.Background(), "first", nil); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	conn, err := Replay(bytes.NewReader(rec.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewClient(conn)
+	defer rc.Close()
+	err = rc.Call(context.Background(), "second", nil)
+	if !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("got %v, want %v", err, ErrReplayMismatch)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// requestHandler is a function serving a request method.
+type requestHandler struct {
+	fn       reflect.Value
+	ctx      bool // whether fn takes a context first
+	params   []reflect.Type
+	variadic bool
+	result   bool // whether fn returns a result before its error
+	err      bool // whether fn returns an error last
+}
+
+// HandleRequest registers fn as the handler of requests of method, such as
+// sent by rpcrequest() in Neovim. A nil fn removes the handler.
+//
+// The function fn may take a context.Context first, canceled when the
+// connection closes, followed by parameters the arguments of the request
+// are decoded into. A final variadic parameter receives the remaining
+// arguments. It may return a result, an error, or both in that order.
+//
+// Requests are served concurrently, each in its own goroutine, so that
+// handlers can make calls while Neovim waits for their response.
+func (c *Client) HandleRequest(method string, fn interface{}) error {
+	if fn == nil {
+		c.mu.Lock()
+		delete(c.requests, method)
+		c.mu.Unlock()
+		return nil
+	}
+
+	h, err := newRequestHandler(fn)
+	if err != nil {
+		return fmt.Errorf("rpc: handler of %s: %w", method, err)
+	}
+
+	c.mu.Lock()
+	c.requests[method] = h
+	c.mu.Unlock()
+
+	return nil
+}
+
+func newRequestHandler(fn interface{}) (*requestHandler, error) {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%T is not a function", fn)
+	}
+
+	h := &requestHandler{fn: v, variadic: t.IsVariadic()}
+	for i := 0; i < t.NumIn(); i++ {
+		if i == 0 && t.In(i) == contextType {
+			h.ctx = true
+			continue
+		}
+		h.params = append(h.params, t.In(i))
+	}
+
+	switch t.NumOut() {
+	case 0:
+	case 1:
+		if t.Out(0) == errorType {
+			h.err = true
+		} else {
+			h.result = true
+		}
+	case 2:
+		if t.Out(1) != errorType {
+			return nil, errors.New("second result is not an error")
+		}
+		h.result, h.err = true, true
+	default:
+		return nil, errors.New("too many results")
+	}
+
+	return h, nil
+}
+
+// args decodes the params of a request into the arguments of h.
+func (h *requestHandler) args(ctx context.Context, params msgpack.RawMessage) ([]reflect.Value, error) {
+	d := msgpack.NewDecoder(bytes.NewReader(params))
+	n, err := d.DecodeArrayLen()
+	if err != nil {
+		return nil, err
+	}
+
+	fixed := len(h.params)
+	if h.variadic {
+		fixed--
+	}
+	if n < fixed || n > fixed && !h.variadic {
+		return nil, fmt.Errorf("wrong number of arguments: got %d, want %d", n, fixed)
+	}
+
+	var args []reflect.Value
+	if h.ctx {
+		args = append(args, reflect.ValueOf(ctx))
+	}
+	for i := 0; i < n; i++ {
+		typ := h.params[min(i, len(h.params)-1)]
+		if i >= fixed {
+			typ = typ.Elem()
+		}
+		arg := reflect.New(typ)
+		if err := d.Decode(arg.Interface()); err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		args = append(args, arg.Elem())
+	}
+
+	return args, nil
+}
+
+// call calls h with params and returns its result and error.
+func (h *requestHandler) call(ctx context.Context, params msgpack.RawMessage) (result interface{}, err error) {
+	args, err := h.args(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+
+	out := h.fn.Call(args)
+	if h.err {
+		if e := out[len(out)-1]; !e.IsNil() {
+			return nil, e.Interface().(error)
+		}
+	}
+	if h.result {
+		return out[0].Interface(), nil
+	}
+
+	return nil, nil
+}
+
+// serve serves the request id of method, and writes its response.
+func (c *Client) serve(id uint32, method string, params msgpack.RawMessage) {
+	c.mu.Lock()
+	h := c.requests[method]
+	c.mu.Unlock()
+
+	var result interface{}
+	info := &Info{Kind: KindRequest, Method: method, RequestSize: len(params)}
+	err := c.intercept(c.ctx, info, func(ctx context.Context) error {
+		if h == nil {
+			return &Error{Type: ExceptionError, Message: "method not found: " + method}
+		}
+		var err error
+		result, err = h.call(ctx, params)
+		return err
+	})
+
+	resp := []interface{}{responseMessage, id, nil, result}
+	if err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			e = &Error{Type: ExceptionError, Message: err.Error()}
+		}
+		resp[2] = []interface{}{e.Type, e.Message}
+		resp[3] = nil
+	}
+	if _, err := c.write(resp); err != nil {
+		// The result cannot be encoded; report that instead.
+		c.write([]interface{}{responseMessage, id, []interface{}{ExceptionError, err.Error()}, nil})
+	}
+}
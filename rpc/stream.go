@@ -0,0 +1,59 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// CallStream calls the remote method with args, whose result is an array,
+// and calls fn with the encoding of each element of the array as it is read
+// from the connection. Unlike Call, the whole result is never held in
+// memory, which bounds the memory used by very large results.
+//
+// fn runs on the goroutine reading the connection: responses and
+// notifications are not read until it returns, so it must not make calls
+// with the Client. elem is only valid until fn returns. If fn returns an
+// error, the remaining elements are discarded and CallStream returns the
+// error. fn is not called after ctx is done.
+func (c *Client) CallStream(ctx context.Context, method string, fn func(elem msgpack.RawMessage) error, args ...interface{}) error {
+	info := &Info{Kind: KindCall, Method: method}
+
+	return c.intercept(ctx, info, func(ctx context.Context) error {
+		_, err := c.roundTrip(ctx, &call{method: method, stream: fn}, args, info)
+		return err
+	})
+}
+
+// readStream reads the result of the streamed call pc into r. It returns
+// only the errors of the connection.
+func (c *Client) readStream(pc *call, r *response) error {
+	err := c.dec.DecodeArrayFunc(func(elem msgpack.RawMessage) error {
+		r.size += len(elem)
+		if err := pc.ctx.Err(); err != nil {
+			r.streamErr = err
+			return err
+		}
+		if err := pc.stream(elem); err != nil {
+			r.streamErr = err
+			return err
+		}
+		return nil
+	})
+
+	var te *msgpack.TypeError
+	switch {
+	case err == nil, err == r.streamErr:
+	case errors.As(err, &te):
+		r.streamErr = fmt.Errorf("rpc: %s: %w", pc.method, err)
+	default:
+		return err
+	}
+
+	return nil
+}
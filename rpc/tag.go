@@ -0,0 +1,118 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+type tagKey struct{}
+
+// WithTag returns a copy of ctx tagging the calls made with it, such as
+// with the name of the handler or goroutine issuing them. Tags are reported
+// by Client.Pending to debug calls that do not return.
+func WithTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, tagKey{}, tag)
+}
+
+// Tag returns the tag of ctx, or "" if it has none.
+func Tag(ctx context.Context) string {
+	tag, _ := ctx.Value(tagKey{}).(string)
+	return tag
+}
+
+// PendingCall describes a call waiting for its response.
+type PendingCall struct {
+	ID      uint32
+	Method  string
+	Tag     string    // tag of the context of the call
+	Caller  string    // function calling the client, as file:line, see TrackCallers
+	Started time.Time // time the call was issued
+}
+
+func (p PendingCall) String() string {
+	s := fmt.Sprintf("#%d %s", p.ID, p.Method)
+	if p.Caller != "" {
+		s += " from " + p.Caller
+	}
+	s += fmt.Sprintf(" for %v", time.Since(p.Started).Round(time.Millisecond))
+	if p.Tag != "" {
+		s += " [" + p.Tag + "]"
+	}
+
+	return s
+}
+
+// Pending returns the calls waiting for their response, oldest first.
+func (c *Client) Pending() []PendingCall {
+	c.mu.Lock()
+	calls := make([]PendingCall, 0, len(c.pending))
+	for id, pc := range c.pending {
+		calls = append(calls, PendingCall{
+			ID:      id,
+			Method:  pc.method,
+			Tag:     pc.tag,
+			Caller:  pc.caller.String(),
+			Started: pc.started,
+		})
+	}
+	c.mu.Unlock()
+
+	sort.Slice(calls, func(i, j int) bool { return calls[i].ID < calls[j].ID })
+
+	return calls
+}
+
+// TrackCallers sets whether calls record the location of their caller,
+// reported by Pending. It is off by default, as it walks the stack of every
+// call.
+func (c *Client) TrackCallers(on bool) {
+	c.trackCallers.Store(on)
+}
+
+// callerPCs is the stack of a call, resolved to a location only when
+// reported.
+type callerPCs struct {
+	pcs [16]uintptr
+	n   int
+}
+
+// caller returns the stack of the caller of the Client, or nil if callers
+// are not tracked.
+func (c *Client) caller() *callerPCs {
+	if !c.trackCallers.Load() {
+		return nil
+	}
+	p := &callerPCs{}
+	p.n = runtime.Callers(3, p.pcs[:])
+
+	return p
+}
+
+// String returns the location of the first caller outside of this package
+// and the packages wrapping it, or "" for a nil p.
+func (p *callerPCs) String() string {
+	if p == nil {
+		return ""
+	}
+	frames := runtime.CallersFrames(p.pcs[:p.n])
+	for {
+		f, more := frames.Next()
+		if f.Function != "" &&
+			!strings.HasPrefix(f.Function, "github.com/go-nvim/pkg/rpc.") &&
+			!strings.HasPrefix(f.Function, "github.com/go-nvim/pkg/nvim.") &&
+			!strings.HasPrefix(f.Function, "runtime.") {
+			return fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
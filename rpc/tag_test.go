@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/rpc"
+)
+
+func TestTrackCallers(t *testing.T) {
+	a, b := net.Pipe()
+	go io.Copy(io.Discard, b)
+	c := rpc.NewClient(a)
+	defer c.Close()
+
+	pending := func(ctx context.Context) rpc.PendingCall {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() { c.Call(ctx, "never", nil) }()
+		for {
+			if p := c.Pending(); len(p) > 0 {
+				return p[0]
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	ctx := rpc.WithTag(context.Background(), "test")
+	if p := pending(ctx); p.Caller != "" || p.Tag != "test" {
+		t.Errorf("untracked call has caller %q and tag %q", p.Caller, p.Tag)
+	}
+	for len(c.Pending()) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	c.TrackCallers(true)
+	if p := pending(ctx); !strings.HasPrefix(p.Caller, "tag_test.go:") {
+		t.Errorf("tracked call has caller %q", p.Caller)
+	}
+}
@@ -1074,6 +1074,11 @@ const (
 	// DiffUpdated after diffs have been updated.
 	DiffUpdated = "DiffUpdated"
 
+	// DiagnosticChanged after diagnostics have changed.
+	//
+	// This autocmd Neovim specific.
+	DiagnosticChanged = "DiagnosticChanged"
+
 	// FileChangedShell Vim notices that a file changed since editing started.
 	FileChangedShell = "FileChangedShell"
 
@@ -1086,8 +1091,13 @@ const (
 	// ShellCmdPost after executing a shell command.
 	ShellCmdPost = "ShellCmdPost"
 
-	// ShellFilterPostafter filtering with a shell command.
-	ShellFilterPostafter = "ShellFilterPostafter"
+	// ShellFilterPost after filtering with a shell command.
+	ShellFilterPost = "ShellFilterPost"
+
+	// ShellFilterPostafter is a misspelling of ShellFilterPost.
+	//
+	// Deprecated: Use ShellFilterPost instead.
+	ShellFilterPostafter = ShellFilterPost
 
 	// FuncUndefined a user function is used but it isn't defined.
 	FuncUndefined = "FuncUndefined"
@@ -1098,6 +1108,9 @@ const (
 	// SourcePre before sourcing a Vim script.
 	SourcePre = "SourcePre"
 
+	// SourcePost after sourcing a Vim script.
+	SourcePost = "SourcePost"
+
 	// SourceCmd before sourcing a Vim script |Cmd-event|.
 	SourceCmd = "SourceCmd"
 
@@ -1125,13 +1138,21 @@ const (
 	// WinEnter after entering another window.
 	WinEnter = "WinEnter"
 
+	// WinNew after creating a new window.
+	WinNew = "WinNew"
+
 	// WinScrolled after scrolling the viewport of the current window.
 	//
 	// This autocmd Neovim specific.
 	WinScrolled = "WinScrolled"
 
-	// WinLeavet before leaving a window.
-	WinLeavet = "WinLeavet"
+	// WinLeave before leaving a window.
+	WinLeave = "WinLeave"
+
+	// WinLeavet is a misspelling of WinLeave.
+	//
+	// Deprecated: Use WinLeave instead.
+	WinLeavet = WinLeave
 
 	// WinClosed after closing a window. <afile> expands to the window-ID. after WinLeave.
 	//
@@ -1148,11 +1169,21 @@ const (
 	// This autocmd Neovim specific.
 	TabNewEntered = "TabNewEntered"
 
-	// TabEntert after entering another tab page.
-	TabEntert = "TabEntert"
+	// TabEnter after entering another tab page.
+	TabEnter = "TabEnter"
+
+	// TabEntert is a misspelling of TabEnter.
+	//
+	// Deprecated: Use TabEnter instead.
+	TabEntert = TabEnter
+
+	// TabLeave before leaving a tab page.
+	TabLeave = "TabLeave"
 
-	// TabLeavet before leaving a tab page.
-	TabLeavet = "TabLeavet"
+	// TabLeavet is a misspelling of TabLeave.
+	//
+	// Deprecated: Use TabLeave instead.
+	TabLeavet = TabLeave
 
 	// TabClosed after closing a tab page.
 	//
@@ -1180,6 +1211,9 @@ const (
 	// InsertLeave when leaving Insert mode.
 	InsertLeave = "InsertLeave"
 
+	// InsertLeavePre just before leaving Insert mode.
+	InsertLeavePre = "InsertLeavePre"
+
 	// InsertCharPre when a character was typed in Insert mode, before inserting it.
 	InsertCharPre = "InsertCharPre"
 
@@ -1195,6 +1229,11 @@ const (
 	// TextChangedP after a change was made to the text in Insert mode when popup menu visible.
 	TextChangedP = "TextChangedP"
 
+	// TextChangedT after a change was made to the text in Terminal mode.
+	//
+	// This autocmd Neovim specific.
+	TextChangedT = "TextChangedT"
+
 	// ColorSchemePre before loading a color scheme.
 	ColorSchemePre = "ColorSchemePre"
 
@@ -1213,12 +1252,18 @@ const (
 	// SessionLoadPost after loading a session file.
 	SessionLoadPost = "SessionLoadPost"
 
+	// SessionWritePost after writing a session file.
+	SessionWritePost = "SessionWritePost"
+
 	// MenuPopup just before showing the popup menu.
 	MenuPopup = "MenuPopup"
 
 	// CompleteChanged after popup menu changed, not fired on popup menu hide.
 	CompleteChanged = "CompleteChanged"
 
+	// CompleteDonePre after Insert mode completion is done, before clearing the completion info.
+	CompleteDonePre = "CompleteDonePre"
+
 	// CompleteDone after Insert mode completion is done.
 	CompleteDone = "CompleteDone"
 
@@ -1227,6 +1272,11 @@ const (
 	// This autocmd Neovim specific.
 	DirChanged = "DirChanged"
 
+	// DirChangedPre before the `current-directory` is changed.
+	//
+	// This autocmd Neovim specific.
+	DirChangedPre = "DirChangedPre"
+
 	// Signal after Nvim receives a signal.
 	//
 	// This autocmd Neovim specific.
@@ -1255,6 +1305,9 @@ const (
 	//
 	// This autocmd Neovim specific.
 	TermClose = "TermClose"
+
+	// TermChanged after the value of 'term' has changed.
+	TermChanged = "TermChanged"
 )
 
 // List of UD autocmd name.
@@ -1269,3 +1322,74 @@ const (
 	// This autocmd Neovim specific.
 	UILeave = "UILeave"
 )
+
+// List of LSP autocmd name.
+const (
+	// LspAttach after an LSP client attaches to a buffer.
+	//
+	// This autocmd Neovim specific.
+	LspAttach = "LspAttach"
+
+	// LspDetach just before an LSP client detaches from a buffer.
+	//
+	// This autocmd Neovim specific.
+	LspDetach = "LspDetach"
+
+	// LspNotify after an LSP notification is sent to the server.
+	//
+	// This autocmd Neovim specific.
+	LspNotify = "LspNotify"
+
+	// LspProgress after a progress notification is received from the server.
+	//
+	// This autocmd Neovim specific.
+	LspProgress = "LspProgress"
+
+	// LspRequest after an LSP request is started, canceled, or completed.
+	//
+	// This autocmd Neovim specific.
+	LspRequest = "LspRequest"
+
+	// LspTokenUpdate when a visible semantic token is sent or updated by the server.
+	//
+	// This autocmd Neovim specific.
+	LspTokenUpdate = "LspTokenUpdate"
+)
+
+// List of newer autocmd name.
+const (
+	// ModeChanged after changing the mode.
+	//
+	// The pattern is matched against "old_mode:new_mode".
+	// Sets these |v:event| keys: old_mode, new_mode.
+	ModeChanged = "ModeChanged"
+
+	// RecordingEnter when a macro starts recording.
+	//
+	// This autocmd Neovim specific.
+	RecordingEnter = "RecordingEnter"
+
+	// RecordingLeave when a macro stops recording.
+	// Sets these |v:event| keys: regcontents, regname.
+	//
+	// This autocmd Neovim specific.
+	RecordingLeave = "RecordingLeave"
+
+	// SafeState when nothing is pending, going to wait for the user to type a character.
+	SafeState = "SafeState"
+
+	// SearchWrapped after making a search with 'wrapscan' enabled and the search wrapped around the document.
+	//
+	// This autocmd Neovim specific.
+	SearchWrapped = "SearchWrapped"
+
+	// TermRequest when a terminal job emits an OSC or DCS sequence.
+	// Sets |v:termrequest|.
+	//
+	// This autocmd Neovim specific.
+	TermRequest = "TermRequest"
+
+	// WinResized after a window in the current tab page changed width or height.
+	// Sets these |v:event| keys: windows.
+	WinResized = "WinResized"
+)
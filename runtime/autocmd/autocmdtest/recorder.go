@@ -0,0 +1,185 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package autocmdtest provides utilities for testing autocmd handling.
+package autocmdtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/internal/sequence"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// Firing is an event recorded by a Recorder.
+type Firing struct {
+	// Time is the time the Recorder received the event.
+	Time time.Time
+
+	// Event is the name of the event.
+	Event autocmd.Event
+
+	// Match is the expanded value of <amatch>.
+	Match string
+
+	// Args is the full payload of the event.
+	Args *autocmd.Args
+}
+
+// Recorder records the firings of autocmd events.
+type Recorder struct {
+	mu      sync.Mutex
+	firings []Firing
+	changed chan struct{} // closed and replaced on each firing
+	handles []*autocmd.Handle
+}
+
+// NewRecorder returns a Recorder of events, creating one autocmd per
+// event with c.
+func NewRecorder(ctx context.Context, c autocmd.Client, events ...autocmd.Event) (*Recorder, error) {
+	r := &Recorder{changed: make(chan struct{})}
+	for _, e := range events {
+		h, err := autocmd.Register(e).
+			Pattern("*").
+			Desc("autocmdtest.Recorder").
+			Callback(r.record).
+			Create(ctx, c)
+		if err != nil {
+			_ = r.Close(ctx)
+			return nil, err
+		}
+		r.handles = append(r.handles, h)
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) record(a *autocmd.Args) {
+	r.mu.Lock()
+	r.firings = append(r.firings, Firing{
+		Time:  time.Now(),
+		Event: a.Event,
+		Match: a.Match,
+		Args:  a,
+	})
+	close(r.changed)
+	r.changed = make(chan struct{})
+	r.mu.Unlock()
+}
+
+// Close deletes the autocmds of the Recorder. The recorded firings are
+// kept.
+func (r *Recorder) Close(ctx context.Context) error {
+	var err error
+	for _, h := range r.handles {
+		if e := h.Delete(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	r.handles = nil
+
+	return err
+}
+
+// Firings returns the recorded firings in the order they were received.
+func (r *Recorder) Firings() []Firing {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Firing(nil), r.firings...)
+}
+
+// Events returns the names of the recorded events in the order they were
+// received.
+func (r *Recorder) Events() []autocmd.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]autocmd.Event, len(r.firings))
+	for i, f := range r.firings {
+		events[i] = f.Event
+	}
+
+	return events
+}
+
+// Reset discards the recorded firings.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.firings = nil
+	r.mu.Unlock()
+}
+
+// hasSequence reports whether events were recorded in this order, possibly
+// with other events in between.
+func (r *Recorder) hasSequence(events []autocmd.Event) (bool, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fired := make([]string, len(r.firings))
+	for i, f := range r.firings {
+		fired[i] = string(f.Event)
+	}
+
+	return sequence.Contains(fired, names(events)), r.changed
+}
+
+// Wait blocks until events have been recorded in this order, possibly with
+// other events in between, or ctx is done.
+func (r *Recorder) Wait(ctx context.Context, events ...autocmd.Event) error {
+	err := sequence.Wait(ctx, func() (bool, <-chan struct{}) { return r.hasSequence(events) })
+	if err != nil {
+		return fmt.Errorf("autocmdtest: waiting for %s: %w (recorded %s)", join(events), err, join(r.Events()))
+	}
+
+	return nil
+}
+
+// ExpectSequence reports a test error if events were not recorded in this
+// order. Other events may have been recorded in between.
+func (r *Recorder) ExpectSequence(t testing.TB, events ...autocmd.Event) bool {
+	t.Helper()
+
+	if ok, _ := r.hasSequence(events); !ok {
+		t.Errorf("autocmdtest: expected sequence %s, recorded %s", join(events), join(r.Events()))
+		return false
+	}
+
+	return true
+}
+
+// ExpectFired reports a test error if e was not recorded.
+func (r *Recorder) ExpectFired(t testing.TB, e autocmd.Event) bool {
+	t.Helper()
+
+	return r.ExpectSequence(t, e)
+}
+
+// ExpectNotFired reports a test error if e was recorded.
+func (r *Recorder) ExpectNotFired(t testing.TB, e autocmd.Event) bool {
+	t.Helper()
+
+	if ok, _ := r.hasSequence([]autocmd.Event{e}); ok {
+		t.Errorf("autocmdtest: expected %s not to fire, recorded %s", e, join(r.Events()))
+		return false
+	}
+
+	return true
+}
+
+func names(events []autocmd.Event) []string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = string(e)
+	}
+
+	return names
+}
+
+func join(events []autocmd.Event) string {
+	return sequence.Join(names(events))
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"sync"
+)
+
+// BufferRegistrar creates buffer-local autocmds of a single buffer.
+//
+// Neovim removes buffer-local autocmds when their buffer is wiped out. The
+// registrar watches BufWipeout of its buffer and releases the Go callbacks
+// of those autocmds at the same time, so they do not outlive the buffer.
+type BufferRegistrar struct {
+	c   Client
+	buf int
+
+	mu      sync.Mutex
+	watch   *Handle
+	handles []*Handle
+}
+
+// ForBuffer returns a BufferRegistrar of buf creating its autocmds with c.
+// Buffer 0 is resolved to the current buffer, so that the registrar keeps
+// to that buffer when another one becomes current.
+func ForBuffer(ctx context.Context, c Client, buf int) (*BufferRegistrar, error) {
+	if buf == 0 {
+		if err := c.Call(ctx, "nvim_call_function", &buf, "bufnr", []interface{}{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &BufferRegistrar{c: c, buf: buf}, nil
+}
+
+// Buffer returns the buffer of r.
+func (r *BufferRegistrar) Buffer() int { return r.buf }
+
+// Register returns a Builder of an autocmd for events local to the buffer
+// of r. The autocmd must be created with Create of the returned Builder.
+func (r *BufferRegistrar) Register(events ...Event) *Builder {
+	b := Register(events...).Buffer(r.buf)
+	b.registrar = r
+
+	return b
+}
+
+// add tracks h, watching BufWipeout of the buffer first if needed.
+func (r *BufferRegistrar) add(ctx context.Context, h *Handle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.watch == nil {
+		watch, err := Register(BufWipeout).
+			Buffer(r.buf).
+			Once().
+			Desc("release buffer-local callbacks").
+			Callback(func(*Args) { r.wipeout() }).
+			Create(ctx, r.c)
+		if err != nil {
+			return err
+		}
+		r.watch = watch
+	}
+	r.handles = append(r.handles, h)
+
+	return nil
+}
+
+// wipeout releases the callbacks of the autocmds removed by Neovim.
+func (r *BufferRegistrar) wipeout() {
+	r.mu.Lock()
+	handles := r.handles
+	r.handles = nil
+	r.watch = nil
+	r.mu.Unlock()
+
+	for _, h := range handles {
+		h.markDeleted()
+		forget(r.c, h.id)
+	}
+}
+
+// Close deletes all the autocmds created through r.
+func (r *BufferRegistrar) Close(ctx context.Context) error {
+	r.mu.Lock()
+	handles := r.handles
+	if r.watch != nil {
+		handles = append(handles, r.watch)
+	}
+	r.handles = nil
+	r.watch = nil
+	r.mu.Unlock()
+
+	var err error
+	for _, h := range handles {
+		if e := h.Delete(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+func TestForCurrentBuffer(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	newMockAutocmds(s)
+	s.Return("nvim_call_function", 5)
+	ctx := context.Background()
+
+	r, err := autocmd.ForBuffer(ctx, v, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Buffer() != 5 {
+		t.Fatalf("Buffer() = %d, want the current buffer 5", r.Buffer())
+	}
+	if _, err := r.Register(autocmd.BufEnter).Callback(func(*autocmd.Args) {}).Create(ctx, v); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both the autocmd and the BufWipeout watch are local to buffer 5.
+	n := 0
+	for _, c := range s.Calls() {
+		if c.Method != "nvim_exec_lua" {
+			continue
+		}
+		params := c.Args[1].([]interface{})
+		opts := params[3].(map[string]interface{})
+		if opts["buffer"] != int64(5) {
+			t.Errorf("autocmd of %v created with buffer %v, want 5", params[2], opts["buffer"])
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("%d autocmds created, want 2", n)
+	}
+}
+
+func TestBuilderCurrentBuffer(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_create_autocmd", 1)
+
+	if _, err := autocmd.Register(autocmd.BufEnter).Buffer(0).Command("echo").Create(context.Background(), v); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_create_autocmd", []interface{}{"BufEnter"}, map[string]interface{}{"buffer": 0, "command": "echo"})
+}
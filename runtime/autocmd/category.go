@@ -0,0 +1,123 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+// Category classifies autocmd events by what they are about.
+type Category int
+
+// List of event categories.
+const (
+	// CategoryUnknown is the category of events not known to this package.
+	CategoryUnknown Category = iota
+
+	// CategoryBuffer is the category of buffer events, such as BufEnter.
+	CategoryBuffer
+
+	// CategoryFile is the category of events fired when reading or writing
+	// part of a buffer, such as FileReadPre.
+	CategoryFile
+
+	// CategoryOption is the category of option events, such as FileType.
+	CategoryOption
+
+	// CategoryLifecycle is the category of startup, exit and session events,
+	// such as VimEnter.
+	CategoryLifecycle
+
+	// CategoryUI is the category of UI and focus events, such as UIEnter.
+	CategoryUI
+
+	// CategoryCursor is the category of cursor events, such as CursorMoved.
+	CategoryCursor
+
+	// CategoryWindow is the category of window events, such as WinEnter.
+	CategoryWindow
+
+	// CategoryTab is the category of tab page events, such as TabEnter.
+	CategoryTab
+
+	// CategoryCmdline is the category of command-line events, such as
+	// CmdlineEnter.
+	CategoryCmdline
+
+	// CategoryInsert is the category of Insert mode and completion events,
+	// such as InsertEnter.
+	CategoryInsert
+
+	// CategoryText is the category of text change events, such as
+	// TextChanged.
+	CategoryText
+
+	// CategoryTerminal is the category of terminal events, such as TermOpen.
+	CategoryTerminal
+
+	// CategoryLSP is the category of LSP client events, such as LspAttach.
+	CategoryLSP
+
+	// CategoryOther is the category of the remaining known events, such as
+	// User.
+	CategoryOther
+)
+
+var categoryNames = [...]string{
+	CategoryUnknown:   "Unknown",
+	CategoryBuffer:    "Buffer",
+	CategoryFile:      "File",
+	CategoryOption:    "Option",
+	CategoryLifecycle: "Lifecycle",
+	CategoryUI:        "UI",
+	CategoryCursor:    "Cursor",
+	CategoryWindow:    "Window",
+	CategoryTab:       "Tab",
+	CategoryCmdline:   "Cmdline",
+	CategoryInsert:    "Insert",
+	CategoryText:      "Text",
+	CategoryTerminal:  "Terminal",
+	CategoryLSP:       "LSP",
+	CategoryOther:     "Other",
+}
+
+// String implements fmt.Stringer.
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return "Unknown"
+	}
+	return categoryNames[c]
+}
+
+// Category returns the category of e.
+func (e Event) Category() Category {
+	return events[e].category
+}
+
+// IsBufferEvent reports whether e is a buffer event.
+func IsBufferEvent(e Event) bool { return e.Category() == CategoryBuffer }
+
+// IsWindowEvent reports whether e is a window event.
+func IsWindowEvent(e Event) bool { return e.Category() == CategoryWindow }
+
+// IsTabEvent reports whether e is a tab page event.
+func IsTabEvent(e Event) bool { return e.Category() == CategoryTab }
+
+// IsUIEvent reports whether e is a UI event.
+func IsUIEvent(e Event) bool { return e.Category() == CategoryUI }
+
+// IsTerminalEvent reports whether e is a terminal event.
+func IsTerminalEvent(e Event) bool { return e.Category() == CategoryTerminal }
+
+// IsNvimOnly reports whether e is specific to Neovim and not supported by
+// Vim.
+func IsNvimOnly(e Event) bool { return events[e].nvimOnly }
+
+// EventsIn returns the known events of category c sorted by name.
+func EventsIn(c Category) []Event {
+	var list []Event
+	for _, e := range Events() {
+		if e.Category() == c {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Client is the RPC client used to manage autocmds.
+type Client interface {
+	// Call calls the Neovim API method with args and stores the result in
+	// the value pointed to by result. A nil result discards the response.
+	Call(ctx context.Context, method string, result interface{}, args ...interface{}) error
+
+	// Handle registers fn as the handler for notifications sent to method.
+	// A nil fn removes the handler.
+	Handle(method string, fn func(args []interface{}))
+}
+
+// channelID returns the channel ID Neovim assigned to c. Clients with a
+// ChannelID method, such as *nvim.Nvim, cache it; it is looked up with
+// nvim_get_api_info for the others.
+func channelID(ctx context.Context, c Client) (int, error) {
+	if c, ok := c.(interface {
+		ChannelID(ctx context.Context) (int, error)
+	}); ok {
+		return c.ChannelID(ctx)
+	}
+
+	var info []interface{}
+	if err := c.Call(ctx, "nvim_get_api_info", &info); err != nil {
+		return 0, err
+	}
+	if len(info) == 0 {
+		return 0, fmt.Errorf("autocmd: unexpected nvim_get_api_info result")
+	}
+
+	return toInt(info[0]), nil
+}
+
+// handlers maps the autocmds created with a Go callback to the
+// notification method of that callback.
+var handlers = struct {
+	sync.Mutex
+	m map[handlerKey]string
+}{m: make(map[handlerKey]string)}
+
+type handlerKey struct {
+	c  Client
+	id int
+}
+
+// forget removes the callback of the autocmd id, if any.
+func forget(c Client, id int) {
+	handlers.Lock()
+	method, ok := handlers.m[handlerKey{c, id}]
+	delete(handlers.m, handlerKey{c, id})
+	handlers.Unlock()
+
+	if ok {
+		c.Handle(method, nil)
+	}
+}
+
+// Delete deletes the autocmd id.
+func Delete(ctx context.Context, c Client, id int) error {
+	if err := c.Call(ctx, "nvim_del_autocmd", nil, id); err != nil {
+		return err
+	}
+	forget(c, id)
+
+	return nil
+}
+
+// ClearOptions selects the autocmds removed by Clear.
+type ClearOptions struct {
+	// Events clears the autocmds of these events.
+	Events []Event
+
+	// Patterns clears the autocmds with these patterns.
+	Patterns []string
+
+	// Buffer clears the buffer-local autocmds of this buffer.
+	Buffer int
+
+	// Group clears the autocmds in the named group.
+	Group string
+}
+
+func (o *ClearOptions) dict() map[string]interface{} {
+	opts := make(map[string]interface{})
+	if len(o.Events) > 0 {
+		opts["event"] = o.Events
+	}
+	if len(o.Patterns) > 0 {
+		opts["pattern"] = o.Patterns
+	}
+	if o.Buffer != 0 {
+		opts["buffer"] = o.Buffer
+	}
+	if o.Group != "" {
+		opts["group"] = o.Group
+	}
+
+	return opts
+}
+
+// Clear clears the autocmds selected by opts.
+func Clear(ctx context.Context, c Client, opts ClearOptions) error {
+	// Look up the autocmds first so the callbacks of the cleared ones can
+	// be released afterwards.
+	matched, err := Get(ctx, c, GetOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	if err := c.Call(ctx, "nvim_clear_autocmds", nil, opts.dict()); err != nil {
+		return err
+	}
+	for _, d := range matched {
+		forget(c, d.ID)
+	}
+
+	return nil
+}
+
+// CreateGroup creates or gets the autocmd group name and returns its ID.
+//
+// If clear is true, the existing autocmds of the group are cleared.
+func CreateGroup(ctx context.Context, c Client, name string, clear bool) (int, error) {
+	var id int
+	if err := c.Call(ctx, "nvim_create_augroup", &id, name, map[string]interface{}{"clear": clear}); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// DeleteGroup deletes the autocmd group name and all of its autocmds.
+func DeleteGroup(ctx context.Context, c Client, name string) error {
+	if err := Clear(ctx, c, ClearOptions{Group: name}); err != nil {
+		return err
+	}
+
+	return c.Call(ctx, "nvim_del_augroup_by_name", nil, name)
+}
+
+// toInt converts a decoded msgpack integer to int.
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case int:
+		return v
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case uint:
+		return int(v)
+	case uint8:
+		return int(v)
+	case uint16:
+		return int(v)
+	case uint32:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+
+	return 0
+}
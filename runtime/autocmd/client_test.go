@@ -0,0 +1,62 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// plainClient is a Client that does not cache its channel ID.
+type plainClient struct {
+	v *nvim.Nvim
+}
+
+func (c *plainClient) Call(ctx context.Context, method string, result interface{}, args ...interface{}) error {
+	return c.v.Call(ctx, method, result, args...)
+}
+
+func (c *plainClient) Handle(method string, fn func(args []interface{})) { c.v.Handle(method, fn) }
+
+func TestChannelID(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	ctx := context.Background()
+
+	// An Nvim caches its channel ID; other Clients look it up each time.
+	for _, c := range []autocmd.Client{v, v, &plainClient{v}, &plainClient{v}} {
+		if id, err := autocmd.ChannelID(ctx, c); err != nil || id != 1 {
+			t.Fatalf("ChannelID() = %d, %v; want 1", id, err)
+		}
+	}
+	if n := len(s.Methods()); n != 3 {
+		t.Errorf("%d calls, want 3: %v", n, s.Methods())
+	}
+}
+
+func TestOnceFiredDuringCreate(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	ctx := context.Background()
+
+	fired := make(chan struct{})
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		params, _ := args[1].([]interface{})
+		method, _ := params[1].(string)
+		// The autocmd fires before nvim_exec_lua returns.
+		s.Notify(method, map[string]interface{}{"id": 7, "event": "BufEnter"}, map[string]interface{}{})
+		<-fired
+		return 7, nil
+	})
+
+	_, err := autocmd.Register(autocmd.BufEnter).Once().Callback(func(*autocmd.Args) { close(fired) }).Create(ctx, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := autocmd.Callbacks(v); n != 0 {
+		t.Errorf("%d callbacks left after the once autocmd fired", n)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import "context"
+
+// Definition is an autocmd as returned by nvim_get_autocmds.
+type Definition struct {
+	// ID is the autocmd ID. It is zero for autocmds defined with :autocmd.
+	ID int `msgpack:"id"`
+
+	// Event is the event of the autocmd.
+	Event Event `msgpack:"event"`
+
+	// Group is the autocmd group ID, if any.
+	Group int `msgpack:"group"`
+
+	// GroupName is the autocmd group name, if any.
+	GroupName string `msgpack:"group_name"`
+
+	// Pattern is the autocmd pattern.
+	Pattern string `msgpack:"pattern"`
+
+	// Buffer is the buffer of a buffer-local autocmd.
+	Buffer int `msgpack:"buffer"`
+
+	// BufLocal reports whether the autocmd is buffer-local.
+	BufLocal bool `msgpack:"buflocal"`
+
+	// Once reports whether the autocmd runs only once.
+	Once bool `msgpack:"once"`
+
+	// Nested reports whether the autocmd can trigger other autocmds. It
+	// is not reported by nvim_get_autocmds.
+	Nested bool `msgpack:"-"`
+
+	// Desc is the description of the autocmd.
+	Desc string `msgpack:"desc"`
+
+	// Command is the Ex command run by the autocmd, if any.
+	Command string `msgpack:"command"`
+
+	// Callback is the callback of the autocmd. It is the name of a
+	// Vimscript function, or a reference to a Lua function that cannot be
+	// called from Go.
+	Callback interface{} `msgpack:"callback"`
+}
+
+// Definitions is a list of autocmd definitions.
+type Definitions []Definition
+
+// ByGroup returns the definitions in the named group.
+func (d Definitions) ByGroup(name string) Definitions {
+	return d.filter(func(def *Definition) bool { return def.GroupName == name })
+}
+
+// ByEvent returns the definitions of event e.
+func (d Definitions) ByEvent(e Event) Definitions {
+	return d.filter(func(def *Definition) bool { return def.Event == e })
+}
+
+// ByBuffer returns the buffer-local definitions of buf.
+func (d Definitions) ByBuffer(buf int) Definitions {
+	return d.filter(func(def *Definition) bool { return def.BufLocal && def.Buffer == buf })
+}
+
+func (d Definitions) filter(fn func(*Definition) bool) Definitions {
+	var list Definitions
+	for i := range d {
+		if fn(&d[i]) {
+			list = append(list, d[i])
+		}
+	}
+
+	return list
+}
+
+// GetOptions selects the autocmds returned by Get.
+type GetOptions struct {
+	// Events returns the autocmds of these events.
+	Events []Event
+
+	// Patterns returns the autocmds with these patterns.
+	Patterns []string
+
+	// Buffer returns the buffer-local autocmds of this buffer.
+	Buffer int
+
+	// Group returns the autocmds in the named group.
+	Group string
+}
+
+// Get returns the autocmds selected by opts.
+func Get(ctx context.Context, c Client, opts GetOptions) (Definitions, error) {
+	o := ClearOptions(opts)
+	var defs Definitions
+	if err := c.Call(ctx, "nvim_get_autocmds", &defs, o.dict()); err != nil {
+		return nil, err
+	}
+
+	return defs, nil
+}
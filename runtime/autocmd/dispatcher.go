@@ -0,0 +1,285 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"sync"
+)
+
+// Dispatcher fans out autocmd events to Go subscribers.
+//
+// A Dispatcher creates one autocmd per event and pattern, the first time a
+// subscriber asks for it, and deletes it when the last subscriber of that
+// event and pattern unsubscribes.
+type Dispatcher struct {
+	c       Client
+	group   string
+	onPanic func(e Event, v interface{})
+
+	mu      sync.Mutex
+	routes  map[routeKey]*route
+	replay  map[Event]int // see WithReplay
+	history map[routeKey][]*Args
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithGroup creates the autocmds of the Dispatcher in the named group. The
+// group must already exist.
+func WithGroup(name string) DispatcherOption {
+	return func(d *Dispatcher) { d.group = name }
+}
+
+// WithPanicHandler sets the function called with the recovered value when
+// a subscriber panics. By default the panic is ignored.
+func WithPanicHandler(fn func(e Event, v interface{})) DispatcherOption {
+	return func(d *Dispatcher) { d.onPanic = fn }
+}
+
+// NewDispatcher returns a new Dispatcher creating its autocmds with c.
+func NewDispatcher(c Client, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		c:       c,
+		routes:  make(map[routeKey]*route),
+		onPanic: func(Event, interface{}) {},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+type routeKey struct {
+	event   Event
+	pattern string
+}
+
+// route is the autocmd of an event and pattern, and its subscribers.
+type route struct {
+	ready chan struct{} // closed once the autocmd is created
+	h     *Handle
+	err   error
+	subs  []*Subscription
+}
+
+// Subscription is a subscriber of a Dispatcher.
+type Subscription struct {
+	d   *Dispatcher
+	key routeKey
+	fn  func(*Args)
+
+	name   string
+	after  []string
+	before []string
+
+	// The events received while the past ones are replayed, delivered
+	// after them. Guarded by d.mu.
+	replaying bool
+	pending   []*Args
+}
+
+// Subscribe subscribes fn to event for files matching pattern.
+//
+// An empty pattern matches any file. Subscribers of the same event and
+// pattern are called in the order they subscribed, unless constrained
+// otherwise by the After and Before options. Subscribe returns an error if
+// the constraints would form a cycle, or relate subscribers of different
+// patterns of the same event, which run from different autocmds and cannot
+// be ordered.
+//
+// With WithReplay, the recorded occurrences of event for pattern are passed
+// to fn before Subscribe returns.
+func (d *Dispatcher) Subscribe(ctx context.Context, event Event, pattern string, fn func(*Args), opts ...SubscribeOption) (*Subscription, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	key := routeKey{event: event, pattern: pattern}
+	s := &Subscription{d: d, key: key, fn: fn}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	d.mu.Lock()
+	if err := d.checkPatterns(s); err != nil {
+		d.mu.Unlock()
+		return nil, err
+	}
+	r, ok := d.routes[key]
+	if !ok {
+		r = &route{ready: make(chan struct{})}
+	}
+	subs, err := orderSubscriptions(append(r.subs[:len(r.subs):len(r.subs)], s))
+	if err != nil {
+		d.mu.Unlock()
+		return nil, err
+	}
+	r.subs = subs
+	d.routes[key] = r
+	past := d.history[key]
+	s.replaying = len(past) > 0
+	d.mu.Unlock()
+
+	if ok {
+		select {
+		case <-r.ready:
+		case <-ctx.Done():
+			if r := d.remove(s); r != nil {
+				d.release(ctx, r)
+			}
+			return nil, ctx.Err()
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		d.replayTo(s, past)
+		return s, nil
+	}
+
+	b := Register(event).Pattern(pattern).Callback(d.dispatcher(key, r))
+	if d.group != "" {
+		b.Group(d.group)
+	}
+	h, err := b.Create(ctx, d.c)
+
+	d.mu.Lock()
+	r.h, r.err = h, err
+	if err != nil && d.routes[key] == r {
+		delete(d.routes, key)
+	}
+	close(r.ready)
+	d.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	d.replayTo(s, past)
+
+	return s, nil
+}
+
+// replayTo calls s with the past occurrences of its event and pattern,
+// then with the events received meanwhile.
+func (d *Dispatcher) replayTo(s *Subscription, past []*Args) {
+	for len(past) > 0 {
+		for _, a := range past {
+			d.call(s, a)
+		}
+		d.mu.Lock()
+		past, s.pending = s.pending, nil
+		s.replaying = len(past) > 0
+		d.mu.Unlock()
+	}
+}
+
+// dispatcher returns the autocmd callback of the route r of key. Events of
+// a released route are dropped, as its autocmd is being deleted.
+func (d *Dispatcher) dispatcher(key routeKey, r *route) func(*Args) {
+	return func(a *Args) {
+		d.mu.Lock()
+		var subs []*Subscription
+		if d.routes[key] == r {
+			for _, s := range r.subs {
+				if s.replaying {
+					s.pending = append(s.pending, a)
+					continue
+				}
+				subs = append(subs, s)
+			}
+			d.record(key, a)
+		}
+		d.mu.Unlock()
+
+		for _, s := range subs {
+			d.call(s, a)
+		}
+	}
+}
+
+// call calls the subscriber s, recovering from a panic.
+func (d *Dispatcher) call(s *Subscription, a *Args) {
+	defer func() {
+		if v := recover(); v != nil {
+			d.onPanic(a.Event, v)
+		}
+	}()
+
+	s.fn(a)
+}
+
+// remove removes s from its route and returns the route if s was its last
+// subscriber. The route must then be released.
+func (d *Dispatcher) remove(s *Subscription) *route {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	r, ok := d.routes[s.key]
+	if !ok {
+		return nil
+	}
+	for i, sub := range r.subs {
+		if sub == s {
+			r.subs = append(r.subs[:i:i], r.subs[i+1:]...)
+			break
+		}
+	}
+	if len(r.subs) > 0 {
+		return nil
+	}
+	delete(d.routes, s.key)
+
+	return r
+}
+
+// release deletes the autocmd of r, a route removed from d, waiting for it
+// to be created first. If ctx is done before, the autocmd is deleted in the
+// background once created.
+func (d *Dispatcher) release(ctx context.Context, r *route) error {
+	select {
+	case <-r.ready:
+	case <-ctx.Done():
+		go d.release(context.Background(), r)
+		return ctx.Err()
+	}
+	if r.h == nil {
+		return nil
+	}
+
+	return r.h.Delete(ctx)
+}
+
+// Unsubscribe removes the subscriber. The autocmd is deleted if it was the
+// last subscriber of its event and pattern.
+func (s *Subscription) Unsubscribe(ctx context.Context) error {
+	if r := s.d.remove(s); r != nil {
+		return s.d.release(ctx, r)
+	}
+
+	return nil
+}
+
+// Close deletes all the autocmds of the Dispatcher and removes all the
+// subscribers. The autocmds still being created when ctx is done are
+// deleted in the background once created.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	routes := d.routes
+	d.routes = make(map[routeKey]*route)
+	d.mu.Unlock()
+
+	var err error
+	for _, r := range routes {
+		if e := d.release(ctx, r); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,313 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// mockAutocmds stands in for the autocmds of a MockServer.
+type mockAutocmds struct {
+	s *nvimtest.MockServer
+
+	mu      sync.Mutex
+	methods map[int]string // callback method of each autocmd
+	events  map[int]string // first event of each autocmd
+	deleted map[int]bool
+}
+
+func newMockAutocmds(s *nvimtest.MockServer) *mockAutocmds {
+	m := &mockAutocmds{s: s, methods: make(map[int]string), events: make(map[int]string), deleted: make(map[int]bool)}
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		code, _ := args[0].(string)
+		if !strings.Contains(code, "nvim_create_autocmd") {
+			return nil, nil
+		}
+		params, _ := args[1].([]interface{})
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		id := len(m.methods) + 1
+		m.methods[id], _ = params[1].(string)
+		if events, _ := params[2].([]interface{}); len(events) > 0 {
+			m.events[id], _ = events[0].(string)
+		}
+		return id, nil
+	})
+	s.Handle("nvim_del_autocmd", func(args []interface{}) (interface{}, error) {
+		id, _ := args[0].(int64)
+		m.mu.Lock()
+		m.deleted[int(id)] = true
+		m.mu.Unlock()
+		return nil, nil
+	})
+
+	return m
+}
+
+// fire triggers the autocmd id with event and match.
+func (m *mockAutocmds) fire(id int, event autocmd.Event, match string) {
+	m.mu.Lock()
+	method := m.methods[id]
+	m.mu.Unlock()
+	m.s.Notify(method, map[string]interface{}{"id": id, "event": string(event), "match": match, "buf": 1}, []interface{}{})
+}
+
+// id returns the ID of the live autocmd of event, or 0 if there is none.
+func (m *mockAutocmds) id(event autocmd.Event) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, e := range m.events {
+		if e == string(event) && !m.deleted[id] {
+			return id
+		}
+	}
+
+	return 0
+}
+
+// live returns the number of autocmds created and not deleted.
+func (m *mockAutocmds) live() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.methods) - len(m.deleted)
+}
+
+func TestDispatcher(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v)
+	ctx := context.Background()
+
+	got := make(chan string, 8)
+	sub := func(name string) func(*autocmd.Args) {
+		return func(a *autocmd.Args) { got <- name + ":" + a.Match }
+	}
+	s1, err := d.Subscribe(ctx, autocmd.BufEnter, "*.go", sub("first"), autocmd.Name("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := d.Subscribe(ctx, autocmd.BufEnter, "*.go", sub("second"), autocmd.Before("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := m.live(); n != 1 {
+		t.Fatalf("%d autocmds created, want 1 per event and pattern", n)
+	}
+
+	m.fire(1, autocmd.BufEnter, "a.go")
+	for _, want := range []string{"second:a.go", "first:a.go"} {
+		select {
+		case g := <-got:
+			if g != want {
+				t.Errorf("got %s, want %s", g, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s not called", want)
+		}
+	}
+
+	if err := s1.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.live(); n != 1 {
+		t.Errorf("autocmd deleted with a subscriber left")
+	}
+	if err := s2.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.live(); n != 0 {
+		t.Errorf("autocmd not deleted after the last subscriber left")
+	}
+}
+
+func TestDispatcherCycle(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v)
+	ctx := context.Background()
+
+	if _, err := d.Subscribe(ctx, autocmd.BufEnter, "", func(*autocmd.Args) {}, autocmd.Name("a"), autocmd.After("b")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Subscribe(ctx, autocmd.BufEnter, "", func(*autocmd.Args) {}, autocmd.Name("b"), autocmd.After("a")); err == nil {
+		t.Error("Subscribe with a cycle succeeded")
+	}
+}
+
+func TestDispatcherClose(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v)
+	ctx := context.Background()
+
+	for _, e := range []autocmd.Event{autocmd.BufEnter, autocmd.BufLeave} {
+		if _, err := d.Subscribe(ctx, e, "", func(*autocmd.Args) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.live(); n != 0 {
+		t.Errorf("%d autocmds left after Close", n)
+	}
+}
+
+func TestDispatcherCloseDuringCreate(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v)
+
+	// Hold the creation of the first autocmd.
+	create := make(chan struct{})
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		<-create
+		params, _ := args[1].([]interface{})
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		id := len(m.methods) + 1
+		m.methods[id], _ = params[1].(string)
+		return id, nil
+	})
+
+	got := make(chan int, 8)
+	subscribed := make(chan error, 1)
+	go func() {
+		_, err := d.Subscribe(context.Background(), autocmd.BufEnter, "", func(*autocmd.Args) { got <- 1 })
+		subscribed <- err
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.Wait(ctx, "nvim_exec_lua"); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := d.Close(ctx); err == nil {
+		t.Fatal("Close with a done context waited for the creation")
+	}
+	close(create)
+	if err := <-subscribed; err != nil {
+		t.Fatal(err)
+	}
+
+	// The autocmd of the closed route is deleted once created.
+	wctx, wcancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer wcancel()
+	if err := s.Wait(wctx, "nvim_exec_lua", "nvim_del_autocmd"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new subscriber gets its own autocmd, and the events of the old
+	// one are not delivered to it.
+	if _, err := d.Subscribe(context.Background(), autocmd.BufEnter, "", func(*autocmd.Args) { got <- 2 }); err != nil {
+		t.Fatal(err)
+	}
+	m.fire(1, autocmd.BufEnter, "")
+	m.fire(2, autocmd.BufEnter, "")
+	if g := <-got; g != 2 {
+		t.Errorf("event delivered to subscriber %d, want 2", g)
+	}
+	select {
+	case g := <-got:
+		t.Errorf("event delivered again to subscriber %d", g)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestDispatcherReplayOrder(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v, autocmd.WithReplay(2, autocmd.FileType))
+	ctx := context.Background()
+
+	first := make(chan string, 8)
+	if _, err := d.Subscribe(ctx, autocmd.FileType, "", func(a *autocmd.Args) { first <- a.Match }); err != nil {
+		t.Fatal(err)
+	}
+	m.fire(1, autocmd.FileType, "go")
+	m.fire(1, autocmd.FileType, "lua")
+	<-first
+	<-first
+
+	// An event received while the late subscriber is replayed to must
+	// reach it after the replayed ones.
+	var got []string
+	replaying := make(chan struct{})
+	resume := make(chan struct{})
+	go func() {
+		<-replaying
+		m.fire(1, autocmd.FileType, "c")
+		<-first
+		close(resume)
+	}()
+	_, err := d.Subscribe(ctx, autocmd.FileType, "", func(a *autocmd.Args) {
+		if len(got) == 0 {
+			close(replaying)
+			<-resume
+		}
+		got = append(got, a.Match)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"go", "lua", "c"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("late subscriber got %v, want %v", got, want)
+	}
+}
+
+func TestDispatcherPanic(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	panics := make(chan interface{}, 1)
+	d := autocmd.NewDispatcher(v, autocmd.WithPanicHandler(func(e autocmd.Event, v interface{}) { panics <- v }))
+	ctx := context.Background()
+
+	got := make(chan string, 1)
+	if _, err := d.Subscribe(ctx, autocmd.BufEnter, "", func(*autocmd.Args) { panic("boom") }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Subscribe(ctx, autocmd.BufEnter, "", func(a *autocmd.Args) { got <- a.Match }); err != nil {
+		t.Fatal(err)
+	}
+	m.fire(1, autocmd.BufEnter, "a")
+	if v := <-panics; v != "boom" {
+		t.Errorf("panic handler got %v, want boom", v)
+	}
+	if match := <-got; match != "a" {
+		t.Errorf("next subscriber got %q, want %q", match, "a")
+	}
+}
+
+func TestDispatcherOrderAcrossPatterns(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v)
+	ctx := context.Background()
+
+	if _, err := d.Subscribe(ctx, autocmd.BufEnter, "*.go", func(*autocmd.Args) {}, autocmd.Name("go")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Subscribe(ctx, autocmd.BufEnter, "*.lua", func(*autocmd.Args) {}, autocmd.After("go")); err == nil {
+		t.Error("Subscribe after a subscriber of another pattern succeeded")
+	}
+	if _, err := d.Subscribe(ctx, autocmd.BufWinEnter, "*.lua", func(*autocmd.Args) {}, autocmd.After("go")); err != nil {
+		t.Errorf("Subscribe after a subscriber of another event: %v", err)
+	}
+
+	// The constraint is checked from either side.
+	if _, err := d.Subscribe(ctx, autocmd.BufLeave, "*.go", func(*autocmd.Args) {}, autocmd.Before("lua")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Subscribe(ctx, autocmd.BufLeave, "*.lua", func(*autocmd.Args) {}, autocmd.Name("lua")); err == nil {
+		t.Error("Subscribe of a subscriber constrained from another pattern succeeded")
+	}
+}
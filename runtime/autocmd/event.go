@@ -0,0 +1,202 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Event represents an autocmd event name.
+//
+// The event constants in this package are untyped, so they can be used
+// wherever an Event is expected.
+type Event string
+
+// String implements fmt.Stringer.
+func (e Event) String() string { return string(e) }
+
+// IsValid reports whether e is a known autocmd event.
+func (e Event) IsValid() bool {
+	_, ok := events[e]
+	return ok
+}
+
+// Validate returns an error if e is not a known autocmd event.
+func (e Event) Validate() error {
+	if !e.IsValid() {
+		return fmt.Errorf("autocmd: unknown event %q", string(e))
+	}
+	return nil
+}
+
+// Events returns all known autocmd events sorted by name.
+//
+// Aliases such as BufRead and BufWrite are not included, since they share
+// the name of the event they alias.
+func Events() []Event {
+	list := make([]Event, 0, len(events))
+	for e := range events {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+
+	return list
+}
+
+// eventInfo describes a known autocmd event.
+type eventInfo struct {
+	category   Category
+	nvimOnly   bool
+	since      Version
+	deprecated bool
+}
+
+// events is the registry of known autocmd events.
+var events = map[Event]eventInfo{
+	BufAdd:               {category: CategoryBuffer},
+	BufDelete:            {category: CategoryBuffer},
+	BufEnter:             {category: CategoryBuffer},
+	BufFilePost:          {category: CategoryBuffer},
+	BufFilePre:           {category: CategoryBuffer},
+	BufHidden:            {category: CategoryBuffer},
+	BufLeave:             {category: CategoryBuffer},
+	BufModifiedSet:       {category: CategoryBuffer, nvimOnly: true, since: Version{0, 5, 0}},
+	BufNew:               {category: CategoryBuffer},
+	BufNewFile:           {category: CategoryBuffer},
+	BufReadPost:          {category: CategoryBuffer},
+	BufReadCmd:           {category: CategoryBuffer},
+	BufReadPre:           {category: CategoryBuffer},
+	BufUnload:            {category: CategoryBuffer},
+	BufWinEnter:          {category: CategoryBuffer},
+	BufWinLeave:          {category: CategoryBuffer},
+	BufWipeout:           {category: CategoryBuffer},
+	BufWritePre:          {category: CategoryBuffer},
+	BufWriteCmd:          {category: CategoryBuffer},
+	BufWritePost:         {category: CategoryBuffer},
+	SwapExists:           {category: CategoryBuffer},
+	FileChangedShell:     {category: CategoryBuffer},
+	FileChangedShellPost: {category: CategoryBuffer},
+	FileChangedRO:        {category: CategoryBuffer},
+
+	FileReadPre:     {category: CategoryFile},
+	FileReadPost:    {category: CategoryFile},
+	FileReadCmd:     {category: CategoryFile},
+	FilterReadPre:   {category: CategoryFile},
+	FilterReadPost:  {category: CategoryFile},
+	StdinReadPre:    {category: CategoryFile},
+	StdinReadPost:   {category: CategoryFile},
+	FileWritePre:    {category: CategoryFile},
+	FileWritePost:   {category: CategoryFile},
+	FileWriteCmd:    {category: CategoryFile},
+	FileAppendPre:   {category: CategoryFile},
+	FileAppendPost:  {category: CategoryFile},
+	FileAppendCmd:   {category: CategoryFile},
+	FilterWritePre:  {category: CategoryFile},
+	FilterWritePost: {category: CategoryFile},
+
+	FileType:  {category: CategoryOption},
+	Syntax:    {category: CategoryOption},
+	OptionSet: {category: CategoryOption},
+
+	VimEnter:         {category: CategoryLifecycle},
+	QuitPre:          {category: CategoryLifecycle},
+	ExitPre:          {category: CategoryLifecycle},
+	VimLeavePre:      {category: CategoryLifecycle},
+	VimLeave:         {category: CategoryLifecycle},
+	VimResume:        {category: CategoryLifecycle},
+	VimSuspend:       {category: CategoryLifecycle},
+	SessionLoadPost:  {category: CategoryLifecycle},
+	SessionWritePost: {category: CategoryLifecycle, since: Version{0, 10, 0}},
+	Signal:           {category: CategoryLifecycle, nvimOnly: true, since: Version{0, 5, 0}},
+
+	GUIEnter:       {category: CategoryUI, deprecated: true},
+	GUIFailed:      {category: CategoryUI, deprecated: true},
+	VimResized:     {category: CategoryUI},
+	FocusGained:    {category: CategoryUI},
+	FocusLost:      {category: CategoryUI},
+	ColorSchemePre: {category: CategoryUI},
+	ColorScheme:    {category: CategoryUI},
+	MenuPopup:      {category: CategoryUI},
+	UIEnter:        {category: CategoryUI, nvimOnly: true, since: Version{0, 5, 0}},
+	UILeave:        {category: CategoryUI, nvimOnly: true, since: Version{0, 5, 0}},
+
+	CursorHold:   {category: CategoryCursor},
+	CursorHoldI:  {category: CategoryCursor},
+	CursorMoved:  {category: CategoryCursor},
+	CursorMovedI: {category: CategoryCursor},
+
+	WinEnter:    {category: CategoryWindow},
+	WinNew:      {category: CategoryWindow},
+	WinScrolled: {category: CategoryWindow, since: Version{0, 5, 0}},
+	WinResized:  {category: CategoryWindow, nvimOnly: true, since: Version{0, 9, 0}},
+	WinLeave:    {category: CategoryWindow},
+	WinClosed:   {category: CategoryWindow, since: Version{0, 5, 0}},
+
+	TabNew:        {category: CategoryTab},
+	TabNewEntered: {category: CategoryTab, nvimOnly: true},
+	TabEnter:      {category: CategoryTab},
+	TabLeave:      {category: CategoryTab},
+	TabClosed:     {category: CategoryTab},
+
+	CmdUndefined:   {category: CategoryCmdline},
+	CmdlineChanged: {category: CategoryCmdline},
+	CmdlineEnter:   {category: CategoryCmdline},
+	CmdlineLeave:   {category: CategoryCmdline},
+	CmdwinEnter:    {category: CategoryCmdline},
+	CmdwinLeave:    {category: CategoryCmdline},
+
+	InsertEnter:     {category: CategoryInsert},
+	InsertChange:    {category: CategoryInsert},
+	InsertLeave:     {category: CategoryInsert},
+	InsertLeavePre:  {category: CategoryInsert, since: Version{0, 5, 0}},
+	InsertCharPre:   {category: CategoryInsert},
+	CompleteChanged: {category: CategoryInsert, since: Version{0, 4, 0}},
+	CompleteDonePre: {category: CategoryInsert, since: Version{0, 5, 0}},
+	CompleteDone:    {category: CategoryInsert},
+
+	TextYankPost: {category: CategoryText},
+	TextChanged:  {category: CategoryText},
+	TextChangedI: {category: CategoryText},
+	TextChangedP: {category: CategoryText},
+
+	TermResponse: {category: CategoryTerminal},
+	TermRequest:  {category: CategoryTerminal, nvimOnly: true, since: Version{0, 10, 0}},
+	TextChangedT: {category: CategoryTerminal, since: Version{0, 9, 0}},
+	TermOpen:     {category: CategoryTerminal, nvimOnly: true},
+	TermEnter:    {category: CategoryTerminal, nvimOnly: true, since: Version{0, 5, 0}},
+	TermLeave:    {category: CategoryTerminal, nvimOnly: true, since: Version{0, 5, 0}},
+	TermClose:    {category: CategoryTerminal, nvimOnly: true},
+	TermChanged:  {category: CategoryTerminal, deprecated: true},
+
+	ChanInfo:          {category: CategoryOther, nvimOnly: true, since: Version{0, 4, 0}},
+	ChanOpen:          {category: CategoryOther, nvimOnly: true, since: Version{0, 4, 0}},
+	DiffUpdated:       {category: CategoryOther},
+	DiagnosticChanged: {category: CategoryOther, nvimOnly: true, since: Version{0, 7, 0}},
+	ShellCmdPost:      {category: CategoryOther},
+	ShellFilterPost:   {category: CategoryOther},
+	FuncUndefined:     {category: CategoryOther},
+	SpellFileMissing:  {category: CategoryOther},
+	SourcePre:         {category: CategoryOther},
+	SourcePost:        {category: CategoryOther, since: Version{0, 4, 0}},
+	SourceCmd:         {category: CategoryOther},
+	RemoteReply:       {category: CategoryOther, deprecated: true},
+	QuickFixCmdPre:    {category: CategoryOther},
+	QuickFixCmdPost:   {category: CategoryOther},
+	DirChanged:        {category: CategoryOther},
+	DirChangedPre:     {category: CategoryOther, since: Version{0, 8, 0}},
+	User:              {category: CategoryOther},
+
+	LspAttach:      {category: CategoryLSP, nvimOnly: true, since: Version{0, 8, 0}},
+	LspDetach:      {category: CategoryLSP, nvimOnly: true, since: Version{0, 8, 0}},
+	LspNotify:      {category: CategoryLSP, nvimOnly: true, since: Version{0, 10, 0}},
+	LspProgress:    {category: CategoryLSP, nvimOnly: true, since: Version{0, 10, 0}},
+	LspRequest:     {category: CategoryLSP, nvimOnly: true, since: Version{0, 9, 0}},
+	LspTokenUpdate: {category: CategoryLSP, nvimOnly: true, since: Version{0, 9, 0}},
+	ModeChanged:    {category: CategoryOther, since: Version{0, 6, 0}},
+	RecordingEnter: {category: CategoryOther, nvimOnly: true, since: Version{0, 7, 0}},
+	RecordingLeave: {category: CategoryOther, nvimOnly: true, since: Version{0, 7, 0}},
+	SafeState:      {category: CategoryOther, since: Version{0, 10, 0}},
+	SearchWrapped:  {category: CategoryOther, nvimOnly: true, since: Version{0, 7, 0}},
+}
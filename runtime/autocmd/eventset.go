@@ -0,0 +1,111 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EventSet is a set of autocmd events.
+type EventSet map[Event]struct{}
+
+// NewEventSet returns an EventSet of events.
+func NewEventSet(events ...Event) EventSet {
+	s := make(EventSet, len(events))
+	s.Add(events...)
+
+	return s
+}
+
+// Add adds events to s.
+func (s EventSet) Add(events ...Event) {
+	for _, e := range events {
+		s[e] = struct{}{}
+	}
+}
+
+// Remove removes events from s.
+func (s EventSet) Remove(events ...Event) {
+	for _, e := range events {
+		delete(s, e)
+	}
+}
+
+// Contains reports whether e is in s.
+func (s EventSet) Contains(e Event) bool {
+	_, ok := s[e]
+	return ok
+}
+
+// Len returns the number of events in s.
+func (s EventSet) Len() int { return len(s) }
+
+// Union returns the events in s or t.
+func (s EventSet) Union(t EventSet) EventSet {
+	u := make(EventSet, len(s)+len(t))
+	for e := range s {
+		u[e] = struct{}{}
+	}
+	for e := range t {
+		u[e] = struct{}{}
+	}
+
+	return u
+}
+
+// Intersect returns the events in both s and t.
+func (s EventSet) Intersect(t EventSet) EventSet {
+	if len(t) < len(s) {
+		s, t = t, s
+	}
+	u := make(EventSet)
+	for e := range s {
+		if t.Contains(e) {
+			u[e] = struct{}{}
+		}
+	}
+
+	return u
+}
+
+// Difference returns the events in s but not in t.
+func (s EventSet) Difference(t EventSet) EventSet {
+	u := make(EventSet)
+	for e := range s {
+		if !t.Contains(e) {
+			u[e] = struct{}{}
+		}
+	}
+
+	return u
+}
+
+// Events returns the events in s sorted by name.
+func (s EventSet) Events() []Event {
+	list := make([]Event, 0, len(s))
+	for e := range s {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+
+	return list
+}
+
+// Validate returns an error listing the events in s that are not known
+// autocmd events.
+func (s EventSet) Validate() error {
+	var unknown []string
+	for _, e := range s.Events() {
+		if !e.IsValid() {
+			unknown = append(unknown, string(e))
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("autocmd: unknown events %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"errors"
+)
+
+// ExecOptions is the options of Exec.
+type ExecOptions struct {
+	// Group executes only the autocmds in the named group.
+	Group string
+
+	// Patterns is matched against the autocmd patterns instead of the
+	// current file name.
+	//
+	// Patterns cannot be used with Buffer.
+	Patterns []string
+
+	// Buffer executes the buffer-local autocmds of this buffer.
+	//
+	// Buffer cannot be used with Patterns.
+	Buffer int
+
+	// NoModeline disables processing the modeline after the autocmds.
+	NoModeline bool
+
+	// Data is passed to the callbacks of the autocmds as Args.Data.
+	Data interface{}
+}
+
+// dict returns the opts dictionary of nvim_exec_autocmds.
+func (o *ExecOptions) dict() map[string]interface{} {
+	opts := make(map[string]interface{})
+	if o.Group != "" {
+		opts["group"] = o.Group
+	}
+	if len(o.Patterns) > 0 {
+		opts["pattern"] = o.Patterns
+	}
+	if o.Buffer != 0 {
+		opts["buffer"] = o.Buffer
+	}
+	if o.NoModeline {
+		opts["modeline"] = false
+	}
+	if o.Data != nil {
+		opts["data"] = o.Data
+	}
+
+	return opts
+}
+
+// Exec executes the autocmds of event matching opts.
+func Exec(ctx context.Context, c Client, event Event, opts ExecOptions) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if len(opts.Patterns) > 0 && opts.Buffer != 0 {
+		return errors.New("autocmd: pattern cannot be used with buffer")
+	}
+
+	return c.Call(ctx, "nvim_exec_autocmds", nil, event, opts.dict())
+}
+
+// ExecUser executes the User autocmds matching pattern, passing data to
+// their callbacks.
+//
+// The modeline is not processed, as is usual for User events.
+func ExecUser(ctx context.Context, c Client, pattern string, data interface{}) error {
+	return Exec(ctx, c, User, ExecOptions{
+		Patterns:   []string{pattern},
+		NoModeline: true,
+		Data:       data,
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+// IsUnknownEvent is isUnknownEvent.
+var IsUnknownEvent = isUnknownEvent
+
+// ChannelID is channelID.
+var ChannelID = channelID
+
+// Callbacks returns the number of autocmds of c with a callback.
+func Callbacks(c Client) int {
+	handlers.Lock()
+	defer handlers.Unlock()
+
+	n := 0
+	for k := range handlers.m {
+		if k.c == c {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"sync"
+)
+
+// Handle is a handle to an autocmd created by Builder.Create.
+type Handle struct {
+	c  Client
+	id int
+
+	mu      sync.Mutex
+	deleted bool
+}
+
+// ID returns the autocmd ID.
+func (h *Handle) ID() int { return h.id }
+
+// Delete deletes the autocmd and releases its callback.
+//
+// Delete is a no-op if the autocmd has already been deleted, including a
+// once autocmd that has already run.
+func (h *Handle) Delete(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.deleted {
+		return nil
+	}
+	if err := Delete(ctx, h.c, h.id); err != nil {
+		return err
+	}
+	h.deleted = true
+
+	return nil
+}
+
+// markDeleted records that Neovim deleted the autocmd by itself.
+func (h *Handle) markDeleted() {
+	h.mu.Lock()
+	h.deleted = true
+	h.mu.Unlock()
+}
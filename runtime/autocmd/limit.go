@@ -0,0 +1,151 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate at which an autocmd callback is invoked.
+//
+// Pass Limiter.Callback to Builder.Callback in place of the callback.
+// Deliveries that are coalesced are dropped in favor of the most recent
+// one, which is what the callback receives. The callback is never invoked
+// concurrently, whether by Callback, Flush or the timer of the Limiter.
+type Limiter struct {
+	d        time.Duration
+	fn       func(*Args)
+	throttle bool
+
+	call sync.Mutex // serializes the invocations of fn
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	gen     int // generation of timer, to ignore the timers stopped too late
+	pending *Args
+	last    time.Time
+}
+
+// Debounce returns a Limiter that invokes fn once deliveries have stopped
+// for d, with the most recent Args.
+func Debounce(d time.Duration, fn func(*Args)) *Limiter {
+	return &Limiter{d: d, fn: fn}
+}
+
+// Throttle returns a Limiter that invokes fn at most once every d. The first
+// delivery is passed through immediately, and the most recent delivery
+// within d is passed at the end of it.
+func Throttle(d time.Duration, fn func(*Args)) *Limiter {
+	return &Limiter{d: d, fn: fn, throttle: true}
+}
+
+// Callback delivers an event to the Limiter.
+func (l *Limiter) Callback(a *Args) {
+	l.mu.Lock()
+
+	if l.throttle {
+		if l.timer == nil && time.Since(l.last) >= l.d {
+			l.last = time.Now()
+			l.mu.Unlock()
+			l.invoke(a)
+			return
+		}
+		l.pending = a
+		if l.timer == nil {
+			l.start(l.d - time.Since(l.last))
+		}
+		l.mu.Unlock()
+		return
+	}
+
+	l.pending = a
+	l.start(l.d)
+	l.mu.Unlock()
+}
+
+// start starts the timer firing after d, in place of the running one. It
+// is called with l.mu held.
+func (l *Limiter) start(d time.Duration) {
+	l.stop()
+	gen := l.gen
+	l.timer = time.AfterFunc(d, func() { l.fire(gen) })
+}
+
+// stop stops the timer, if any. It is called with l.mu held.
+func (l *Limiter) stop() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	l.gen++
+}
+
+// fire invokes the callback with the pending delivery when the timer of
+// generation gen expires.
+func (l *Limiter) fire(gen int) {
+	l.mu.Lock()
+	if gen != l.gen {
+		// Stopped or replaced after it expired.
+		l.mu.Unlock()
+		return
+	}
+	a := l.take()
+	l.mu.Unlock()
+
+	l.invoke(a)
+}
+
+// take returns the pending delivery and stops the timer. It is called with
+// l.mu held.
+func (l *Limiter) take() *Args {
+	a := l.pending
+	l.pending = nil
+	l.stop()
+	l.last = time.Now()
+
+	return a
+}
+
+// invoke calls the callback with a, if any.
+func (l *Limiter) invoke(a *Args) {
+	if a == nil {
+		return
+	}
+	l.call.Lock()
+	defer l.call.Unlock()
+
+	l.fn(a)
+}
+
+// Flush immediately invokes the callback with the pending delivery, if any.
+func (l *Limiter) Flush() {
+	l.mu.Lock()
+	if l.pending == nil {
+		l.mu.Unlock()
+		return
+	}
+	a := l.take()
+	l.mu.Unlock()
+
+	l.invoke(a)
+}
+
+// Stop drops the pending delivery, if any.
+func (l *Limiter) Stop() {
+	l.mu.Lock()
+	l.stop()
+	l.pending = nil
+	l.mu.Unlock()
+}
+
+// FlushOn creates an autocmd that flushes the Limiter when any of events
+// fires, such as flushing a pending CursorMoved on InsertEnter.
+func (l *Limiter) FlushOn(ctx context.Context, c Client, events ...Event) (*Handle, error) {
+	return Register(events...).
+		Desc("flush pending deliveries").
+		Callback(func(*Args) { l.Flush() }).
+		Create(ctx, c)
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// calls records the invocations of a limited callback.
+type calls struct {
+	mu  sync.Mutex
+	ids []int
+	c   chan struct{}
+}
+
+func newCalls() *calls {
+	return &calls{c: make(chan struct{}, 100)}
+}
+
+func (c *calls) fn(a *autocmd.Args) {
+	c.mu.Lock()
+	c.ids = append(c.ids, a.ID)
+	c.mu.Unlock()
+	c.c <- struct{}{}
+}
+
+// wait waits for n more invocations.
+func (c *calls) wait(t *testing.T, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-c.c:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the callback")
+		}
+	}
+}
+
+func (c *calls) got() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]int(nil), c.ids...)
+}
+
+func TestDebounce(t *testing.T) {
+	c := newCalls()
+	l := autocmd.Debounce(20*time.Millisecond, c.fn)
+	for i := 1; i <= 3; i++ {
+		l.Callback(&autocmd.Args{ID: i})
+	}
+	c.wait(t, 1)
+	time.Sleep(40 * time.Millisecond)
+	if got := c.got(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("invoked with %v, want [3]", got)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	c := newCalls()
+	l := autocmd.Throttle(20*time.Millisecond, c.fn)
+	for i := 1; i <= 3; i++ {
+		l.Callback(&autocmd.Args{ID: i})
+	}
+	if got := c.got(); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("invoked with %v before the end of the period, want [1]", got)
+	}
+	c.wait(t, 2)
+	time.Sleep(40 * time.Millisecond)
+	if got := c.got(); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("invoked with %v, want [1 3]", got)
+	}
+}
+
+func TestLimiterFlushStop(t *testing.T) {
+	c := newCalls()
+	l := autocmd.Debounce(time.Hour, c.fn)
+	l.Flush()
+	l.Callback(&autocmd.Args{ID: 1})
+	l.Flush()
+	l.Flush()
+	if got := c.got(); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("flushed %v, want [1]", got)
+	}
+
+	l.Callback(&autocmd.Args{ID: 2})
+	l.Stop()
+	l.Flush()
+	if got := c.got(); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("invoked with %v after Stop, want [1]", got)
+	}
+}
+
+func TestLimiterSerialized(t *testing.T) {
+	var running, overlaps, n int32
+	fn := func(*autocmd.Args) {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		time.Sleep(100 * time.Microsecond)
+		atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&n, 1)
+	}
+	l := autocmd.Throttle(50*time.Microsecond, fn)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				l.Callback(&autocmd.Args{ID: i})
+				if i%3 == 0 {
+					l.Flush()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	l.Flush()
+
+	if atomic.LoadInt32(&n) == 0 {
+		t.Fatal("callback never invoked")
+	}
+	if o := atomic.LoadInt32(&overlaps); o > 0 {
+		t.Errorf("callback invoked concurrently %d times", o)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+// Mode is a pattern matching the mode names reported by mode(1).
+type Mode string
+
+// List of modes.
+const (
+	// AnyMode matches any mode.
+	AnyMode Mode = "*"
+
+	// Normal matches Normal mode.
+	Normal Mode = "n"
+
+	// NormalAny matches Normal mode and its sub-modes, including
+	// Operator-pending mode.
+	NormalAny Mode = "n*"
+
+	// OperatorPending matches Operator-pending mode and its forced
+	// motion variants.
+	OperatorPending Mode = "no*"
+
+	// Visual matches characterwise Visual mode.
+	Visual Mode = "v"
+
+	// VisualLine matches linewise Visual mode.
+	VisualLine Mode = "V"
+
+	// VisualBlock matches blockwise Visual mode.
+	VisualBlock Mode = "\x16"
+
+	// VisualAny matches any Visual mode, including Visual mode started
+	// from Select mode.
+	VisualAny Mode = "[vV\x16]*"
+
+	// Select matches characterwise Select mode.
+	Select Mode = "s"
+
+	// SelectAny matches any Select mode.
+	SelectAny Mode = "[sS\x13]"
+
+	// Insert matches Insert mode.
+	Insert Mode = "i"
+
+	// InsertAny matches Insert mode and its completion sub-modes.
+	InsertAny Mode = "i*"
+
+	// Replace matches Replace mode.
+	Replace Mode = "R"
+
+	// ReplaceAny matches Replace and Virtual Replace modes and their
+	// completion sub-modes.
+	ReplaceAny Mode = "R*"
+
+	// Cmdline matches Command-line editing.
+	Cmdline Mode = "c"
+
+	// CmdlineAny matches Command-line editing and Ex mode.
+	CmdlineAny Mode = "c*"
+
+	// Terminal matches Terminal mode.
+	Terminal Mode = "t"
+)
+
+// ModeSpec specifies one side of a ModeChanged pattern.
+type ModeSpec func(p *modePattern)
+
+type modePattern struct {
+	from Mode
+	to   Mode
+}
+
+// From matches the mode left.
+func From(m Mode) ModeSpec {
+	return func(p *modePattern) { p.from = m }
+}
+
+// To matches the mode entered.
+func To(m Mode) ModeSpec {
+	return func(p *modePattern) { p.to = m }
+}
+
+// ModePattern returns the ModeChanged autocmd pattern matching the mode
+// changes described by specs. A side that is not specified matches any
+// mode, so ModePattern(To(InsertAny)) returns "*:i*".
+func ModePattern(specs ...ModeSpec) string {
+	p := modePattern{from: AnyMode, to: AnyMode}
+	for _, spec := range specs {
+		spec(&p)
+	}
+
+	return string(p.from) + ":" + string(p.to)
+}
+
+// ModeChangedEvent is the v:event of ModeChanged.
+type ModeChangedEvent struct {
+	OldMode string `msgpack:"old_mode"`
+	NewMode string `msgpack:"new_mode"`
+}
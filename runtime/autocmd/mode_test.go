@@ -0,0 +1,82 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+func TestModePattern(t *testing.T) {
+	tests := []struct {
+		specs []autocmd.ModeSpec
+		want  string
+	}{
+		{nil, "*:*"},
+		{[]autocmd.ModeSpec{autocmd.To(autocmd.InsertAny)}, "*:i*"},
+		{[]autocmd.ModeSpec{autocmd.From(autocmd.VisualAny)}, "[vV\x16]*:*"},
+		{[]autocmd.ModeSpec{autocmd.To(autocmd.Normal), autocmd.From(autocmd.Insert)}, "i:n"},
+		{[]autocmd.ModeSpec{autocmd.From(autocmd.Insert), autocmd.From(autocmd.Replace)}, "R:*"},
+	}
+	for _, tt := range tests {
+		if got := autocmd.ModePattern(tt.specs...); got != tt.want {
+			t.Errorf("ModePattern() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// TestModes checks the modes against the names reported by mode(1), with
+// the glob matching of autocmd patterns, which path.Match implements for
+// names without a slash.
+func TestModes(t *testing.T) {
+	names := []string{
+		"n", "no", "nov", "noV", "no\x16", "niI", "niR", "niV", "nt", "ntT",
+		"v", "vs", "V", "Vs", "\x16", "\x16s",
+		"s", "S", "\x13",
+		"i", "ic", "ix",
+		"R", "Rc", "Rx", "Rv", "Rvc", "Rvx",
+		"c", "cr", "cv", "cvr",
+		"r", "rm", "r?", "!", "t",
+	}
+	tests := []struct {
+		mode autocmd.Mode
+		want []string
+	}{
+		{autocmd.AnyMode, names},
+		{autocmd.Normal, []string{"n"}},
+		{autocmd.NormalAny, []string{"n", "no", "nov", "noV", "no\x16", "niI", "niR", "niV", "nt", "ntT"}},
+		{autocmd.OperatorPending, []string{"no", "nov", "noV", "no\x16"}},
+		{autocmd.Visual, []string{"v"}},
+		{autocmd.VisualLine, []string{"V"}},
+		{autocmd.VisualBlock, []string{"\x16"}},
+		{autocmd.VisualAny, []string{"v", "vs", "V", "Vs", "\x16", "\x16s"}},
+		{autocmd.Select, []string{"s"}},
+		{autocmd.SelectAny, []string{"s", "S", "\x13"}},
+		{autocmd.Insert, []string{"i"}},
+		{autocmd.InsertAny, []string{"i", "ic", "ix"}},
+		{autocmd.Replace, []string{"R"}},
+		{autocmd.ReplaceAny, []string{"R", "Rc", "Rx", "Rv", "Rvc", "Rvx"}},
+		{autocmd.Cmdline, []string{"c"}},
+		{autocmd.CmdlineAny, []string{"c", "cr", "cv", "cvr"}},
+		{autocmd.Terminal, []string{"t"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, name := range names {
+			ok, err := path.Match(string(tt.mode), name)
+			if err != nil {
+				t.Fatalf("%q: %v", tt.mode, err)
+			}
+			if ok {
+				got = append(got, name)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q matches %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
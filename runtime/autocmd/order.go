@@ -0,0 +1,144 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubscribeOption configures a Subscription.
+type SubscribeOption func(*Subscription)
+
+// Name names the subscriber, so that other subscribers can be ordered
+// relative to it with After and Before.
+func Name(name string) SubscribeOption {
+	return func(s *Subscription) { s.name = name }
+}
+
+// After runs the subscriber after the subscribers with the given names.
+func After(names ...string) SubscribeOption {
+	return func(s *Subscription) { s.after = append(s.after, names...) }
+}
+
+// Before runs the subscriber before the subscribers with the given names.
+func Before(names ...string) SubscribeOption {
+	return func(s *Subscription) { s.before = append(s.before, names...) }
+}
+
+// Name returns the name of the subscriber, if any.
+func (s *Subscription) Name() string { return s.name }
+
+// orderSubscriptions returns subs sorted so that every After and Before
+// constraint between them holds. Subscribers that are not constrained
+// relative to each other keep the order of subs. Constraints naming
+// subscribers not in subs are ignored.
+//
+// It returns an error naming the subscribers involved if the constraints
+// form a cycle.
+func orderSubscriptions(subs []*Subscription) ([]*Subscription, error) {
+	n := len(subs)
+	byName := make(map[string][]int)
+	for i, s := range subs {
+		if s.name != "" {
+			byName[s.name] = append(byName[s.name], i)
+		}
+	}
+
+	// next[i] lists the subscribers that must run after subs[i].
+	next := make([][]int, n)
+	indegree := make([]int, n)
+	edge := func(from, to int) {
+		next[from] = append(next[from], to)
+		indegree[to]++
+	}
+	for i, s := range subs {
+		for _, name := range s.after {
+			for _, j := range byName[name] {
+				edge(j, i)
+			}
+		}
+		for _, name := range s.before {
+			for _, j := range byName[name] {
+				edge(i, j)
+			}
+		}
+	}
+
+	// Kahn's algorithm, always picking the earliest ready subscriber so
+	// that the order is deterministic.
+	sorted := make([]*Subscription, 0, n)
+	done := make([]bool, n)
+	for len(sorted) < n {
+		pick := -1
+		for i := 0; i < n; i++ {
+			if !done[i] && indegree[i] == 0 {
+				pick = i
+				break
+			}
+		}
+		if pick < 0 {
+			var cycle []string
+			for i, s := range subs {
+				if !done[i] {
+					cycle = append(cycle, s.String())
+				}
+			}
+			return nil, fmt.Errorf("autocmd: ordering cycle between subscribers %s", strings.Join(cycle, ", "))
+		}
+		done[pick] = true
+		sorted = append(sorted, subs[pick])
+		for _, j := range next[pick] {
+			indegree[j]--
+		}
+	}
+
+	return sorted, nil
+}
+
+// checkPatterns returns an error if the After and Before constraints
+// relate s and a subscriber of another pattern of its event. d.mu must be
+// held.
+func (d *Dispatcher) checkPatterns(s *Subscription) error {
+	for key, r := range d.routes {
+		if key.event != s.key.event || key.pattern == s.key.pattern {
+			continue
+		}
+		for _, t := range r.subs {
+			if s.constrains(t) || t.constrains(s) {
+				return fmt.Errorf("autocmd: cannot order %s of pattern %s relative to %s of pattern %s", s, s.key.pattern, t, key.pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// constrains reports whether the After and Before constraints of s name t.
+func (s *Subscription) constrains(t *Subscription) bool {
+	if t.name == "" {
+		return false
+	}
+	for _, name := range s.after {
+		if name == t.name {
+			return true
+		}
+	}
+	for _, name := range s.before {
+		if name == t.name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the name of the subscriber, or a description of it if it
+// has no name.
+func (s *Subscription) String() string {
+	if s.name != "" {
+		return s.name
+	}
+	return fmt.Sprintf("<unnamed %s %s>", s.key.event, s.key.pattern)
+}
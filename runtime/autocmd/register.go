@@ -0,0 +1,287 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+// Args is the argument passed to an autocmd callback.
+type Args struct {
+	// ID is the autocmd ID.
+	ID int
+
+	// Event is the name of the triggered event.
+	Event Event
+
+	// Group is the autocmd group ID, if any.
+	Group int
+
+	// Match is the expanded value of <amatch>.
+	Match string
+
+	// Buf is the expanded value of <abuf>.
+	Buf int
+
+	// File is the expanded value of <afile>.
+	File string
+
+	// Data is the arbitrary data passed from nvim_exec_autocmds.
+	Data interface{}
+
+	// VEvent is the content of v:event when the callback ran.
+	VEvent map[string]interface{}
+}
+
+// newArgs decodes the arguments of a callback notification.
+func newArgs(args []interface{}) *Args {
+	a := new(Args)
+	if len(args) > 0 {
+		if m, ok := args[0].(map[string]interface{}); ok {
+			a.ID = toInt(m["id"])
+			a.Event = Event(toString(m["event"]))
+			a.Group = toInt(m["group"])
+			a.Match = toString(m["match"])
+			a.Buf = toInt(m["buf"])
+			a.File = toString(m["file"])
+			a.Data = m["data"]
+		}
+	}
+	if len(args) > 1 {
+		// An empty v:event is sent as an empty array.
+		a.VEvent, _ = args[1].(map[string]interface{})
+	}
+
+	return a
+}
+
+// Builder builds an autocmd created with nvim_create_autocmd.
+type Builder struct {
+	events   []Event
+	patterns []string
+	group    interface{}
+	buffer   interface{} // nil, or the buffer number
+	once     bool
+	nested   bool
+	desc     string
+	command  string
+	callback func(*Args)
+
+	registrar *BufferRegistrar
+}
+
+// Register returns a Builder of an autocmd for events.
+func Register(events ...Event) *Builder {
+	return &Builder{events: events}
+}
+
+// Pattern sets the patterns the autocmd matches against.
+//
+// Pattern cannot be used with Buffer.
+func (b *Builder) Pattern(patterns ...string) *Builder {
+	b.patterns = append(b.patterns, patterns...)
+	return b
+}
+
+// Group sets the name of the autocmd group.
+func (b *Builder) Group(name string) *Builder {
+	b.group = name
+	return b
+}
+
+// GroupID sets the ID of the autocmd group.
+func (b *Builder) GroupID(id int) *Builder {
+	b.group = id
+	return b
+}
+
+// Buffer makes the autocmd local to buf. Buffer 0 is the current buffer
+// when the autocmd is created.
+//
+// Buffer cannot be used with Pattern.
+func (b *Builder) Buffer(buf int) *Builder {
+	b.buffer = buf
+	return b
+}
+
+// Once deletes the autocmd after it has run once.
+func (b *Builder) Once() *Builder {
+	b.once = true
+	return b
+}
+
+// Nested allows the autocmd to trigger other autocmds.
+func (b *Builder) Nested() *Builder {
+	b.nested = true
+	return b
+}
+
+// Desc sets the description of the autocmd.
+func (b *Builder) Desc(desc string) *Builder {
+	b.desc = desc
+	return b
+}
+
+// Command sets the Ex command run by the autocmd.
+//
+// Command cannot be used with Callback.
+func (b *Builder) Command(cmd string) *Builder {
+	b.command = cmd
+	return b
+}
+
+// Callback sets the Go function called when the autocmd runs.
+//
+// The function is called by the notification handler of the Client, so it
+// must not block on requests answered by that handler.
+//
+// Callback cannot be used with Command.
+func (b *Builder) Callback(fn func(*Args)) *Builder {
+	b.callback = fn
+	return b
+}
+
+// validate checks the Builder for conflicting or invalid settings.
+func (b *Builder) validate() error {
+	if len(b.events) == 0 {
+		return errors.New("autocmd: no events")
+	}
+	for _, e := range b.events {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(b.patterns) > 0 && b.buffer != nil {
+		return errors.New("autocmd: pattern cannot be used with buffer")
+	}
+	if b.command != "" && b.callback != nil {
+		return errors.New("autocmd: command cannot be used with callback")
+	}
+	if b.command == "" && b.callback == nil {
+		return errors.New("autocmd: no command or callback")
+	}
+
+	return nil
+}
+
+// opts returns the opts dictionary of nvim_create_autocmd.
+func (b *Builder) opts() map[string]interface{} {
+	opts := make(map[string]interface{})
+	if len(b.patterns) > 0 {
+		opts["pattern"] = b.patterns
+	}
+	if b.group != nil {
+		opts["group"] = b.group
+	}
+	if b.buffer != nil {
+		opts["buffer"] = b.buffer
+	}
+	if b.once {
+		opts["once"] = true
+	}
+	if b.nested {
+		opts["nested"] = true
+	}
+	if b.desc != "" {
+		opts["desc"] = b.desc
+	}
+	if b.command != "" {
+		opts["command"] = b.command
+	}
+
+	return opts
+}
+
+// callbackSeq numbers the notification methods of callbacks.
+var callbackSeq uint64
+
+// createLua creates an autocmd whose callback notifies the Go client.
+const createLua = `
+local chan, method, events, opts = ...
+opts.callback = function(args)
+  vim.rpcnotify(chan, method, args, vim.v.event)
+end
+return vim.api.nvim_create_autocmd(events, opts)
+`
+
+// Create creates the autocmd and returns its Handle.
+func (b *Builder) Create(ctx context.Context, c Client) (*Handle, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	h, err := b.create(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if b.registrar != nil {
+		if err := b.registrar.add(ctx, h); err != nil {
+			_ = h.Delete(ctx)
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+func (b *Builder) create(ctx context.Context, c Client) (*Handle, error) {
+	h := &Handle{c: c}
+	if b.callback == nil {
+		if err := c.Call(ctx, "nvim_create_autocmd", &h.id, b.events, b.opts()); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+
+	chanID, err := channelID(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	method := "autocmd:" + strconv.FormatUint(atomic.AddUint64(&callbackSeq, 1), 10)
+	fn, once := b.callback, b.once
+	fired := false // guarded by handlers
+	c.Handle(method, func(args []interface{}) {
+		a := newArgs(args)
+		if once {
+			// Neovim has already deleted the autocmd. The callback may
+			// run before the autocmd is recorded in handlers.
+			h.markDeleted()
+			handlers.Lock()
+			fired = true
+			handlers.Unlock()
+			forget(c, a.ID)
+			c.Handle(method, nil)
+		}
+		fn(a)
+	})
+
+	if err := c.Call(ctx, "nvim_exec_lua", &h.id, createLua, []interface{}{chanID, method, b.events, b.opts()}); err != nil {
+		c.Handle(method, nil)
+		return nil, err
+	}
+
+	handlers.Lock()
+	if !fired {
+		handlers.m[handlerKey{c, h.id}] = method
+	}
+	handlers.Unlock()
+
+	return h, nil
+}
+
+// toString converts a decoded msgpack string to string.
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+
+	return ""
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import "context"
+
+// WithReplay keeps the last n occurrences of events received by the
+// Dispatcher, and replays them to the subscribers of the same event and
+// pattern that subscribe later, as if they had subscribed before. This lets
+// features loaded lazily react to events such as FileType or LspAttach that
+// already fired.
+//
+// Occurrences are only received while the event and pattern have a
+// subscriber; Retain records them from the start.
+func WithReplay(n int, events ...Event) DispatcherOption {
+	return func(d *Dispatcher) {
+		if d.replay == nil {
+			d.replay = make(map[Event]int)
+		}
+		for _, e := range events {
+			d.replay[e] = n
+		}
+	}
+}
+
+// Retain subscribes the Dispatcher itself to the events replayed for any
+// file, so that their occurrences are recorded before anyone subscribes to
+// them. The subscriptions last until the Dispatcher is closed.
+//
+// If VimEnter is replayed and Neovim has already entered, as reported by
+// v:vim_did_enter, an occurrence of VimEnter is recorded.
+func (d *Dispatcher) Retain(ctx context.Context) error {
+	for e := range d.replay {
+		if _, err := d.Subscribe(ctx, e, "", func(*Args) {}, Name("autocmd:retain")); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := d.replay[VimEnter]; ok {
+		var entered int
+		if err := d.c.Call(ctx, "nvim_get_vvar", &entered, "vim_did_enter"); err != nil {
+			return err
+		}
+		key := routeKey{event: VimEnter, pattern: "*"}
+		d.mu.Lock()
+		if entered == 1 && len(d.history[key]) == 0 {
+			d.record(key, &Args{Event: VimEnter})
+		}
+		d.mu.Unlock()
+	}
+
+	return nil
+}
+
+// record keeps a, if its event is replayed. d.mu must be held.
+func (d *Dispatcher) record(key routeKey, a *Args) {
+	n := d.replay[key.event]
+	if n <= 0 {
+		return
+	}
+	if d.history == nil {
+		d.history = make(map[routeKey][]*Args)
+	}
+	h := append(d.history[key], a)
+	if len(h) > n {
+		h = append(h[:0:0], h[len(h)-n:]...)
+	}
+	d.history[key] = h
+}
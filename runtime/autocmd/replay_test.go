@@ -0,0 +1,103 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// matches subscribes to event for pattern with d and returns the matches
+// replayed to the subscriber.
+func matches(t *testing.T, d *autocmd.Dispatcher, event autocmd.Event, pattern string) string {
+	t.Helper()
+
+	var got []string
+	s, err := d.Subscribe(context.Background(), event, pattern, func(a *autocmd.Args) { got = append(got, a.Match) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The replayed occurrences are passed before Subscribe returns.
+	replayed := strings.Join(got, " ")
+	if err := s.Unsubscribe(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return replayed
+}
+
+func TestReplay(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	d := autocmd.NewDispatcher(v, autocmd.WithReplay(2, autocmd.FileType))
+	ctx := context.Background()
+
+	seen := make(chan string, 8)
+	for _, e := range []autocmd.Event{autocmd.FileType, autocmd.BufEnter} {
+		if _, err := d.Subscribe(ctx, e, "", func(a *autocmd.Args) { seen <- a.Match }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, match := range []string{"go", "lua", "c"} {
+		m.fire(m.id(autocmd.FileType), autocmd.FileType, match)
+		<-seen
+	}
+	m.fire(m.id(autocmd.BufEnter), autocmd.BufEnter, "a.go")
+	<-seen
+
+	if got := matches(t, d, autocmd.FileType, ""); got != "lua c" {
+		t.Errorf("FileType replayed %q, want the last 2, %q", got, "lua c")
+	}
+	if got := matches(t, d, autocmd.FileType, "go"); got != "" {
+		t.Errorf("FileType of another pattern replayed %q", got)
+	}
+	if got := matches(t, d, autocmd.BufEnter, ""); got != "" {
+		t.Errorf("BufEnter, not replayed, replayed %q", got)
+	}
+}
+
+func TestRetain(t *testing.T) {
+	for _, entered := range []int{0, 1} {
+		s, v := nvimtest.NewMockServer(t)
+		m := newMockAutocmds(s)
+		s.Return("nvim_get_vvar", entered)
+		d := autocmd.NewDispatcher(v, autocmd.WithReplay(1, autocmd.FileType, autocmd.VimEnter))
+		if err := d.Retain(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		s.ExpectCall(t, "nvim_get_vvar", "vim_did_enter")
+
+		// Occurrences are recorded before anyone subscribes. The
+		// notifications are handled in order, so the occurrence is
+		// recorded once the next one is handled.
+		handled := make(chan struct{})
+		v.Handle("sync", func([]interface{}) { close(handled) })
+		m.fire(m.id(autocmd.FileType), autocmd.FileType, "go")
+		s.Notify("sync")
+		<-handled
+		ctx := context.Background()
+		if got := matches(t, d, autocmd.FileType, ""); got != "go" {
+			t.Errorf("FileType replayed %q, want %q", got, "go")
+		}
+
+		want := entered // VimEnter occurrences
+		var n int
+		sub, err := d.Subscribe(ctx, autocmd.VimEnter, "", func(*autocmd.Args) { n++ })
+		if err != nil {
+			t.Fatal(err)
+		}
+		sub.Unsubscribe(ctx)
+		if n != want {
+			t.Errorf("vim_did_enter %d: VimEnter replayed %d times, want %d", entered, n, want)
+		}
+		if m.live() != 2 {
+			t.Errorf("%d autocmds live, want the 2 retained", m.live())
+		}
+		d.Close(ctx)
+	}
+}
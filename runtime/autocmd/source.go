@@ -0,0 +1,154 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// action returns the command of d, or the Vimscript function called by it.
+func (d *Definition) action() (command, function string, err error) {
+	if d.Command != "" {
+		return d.Command, "", nil
+	}
+	switch fn := d.Callback.(type) {
+	case string:
+		if fn != "" {
+			return "", fn, nil
+		}
+	case nil:
+	default:
+		return "", "", fmt.Errorf("autocmd: %s callback cannot be rendered as source", d.Event)
+	}
+
+	return "", "", errors.New("autocmd: definition has no command or callback")
+}
+
+// ToVimscript returns the :autocmd command defining d.
+//
+// The description of d is rendered as a comment on the preceding line,
+// since :autocmd cannot set it. A callback must be the name of a Vimscript
+// function. The command and pattern must fit on one line; a "|" in the
+// command is kept, since :autocmd takes it as part of the command.
+func (d *Definition) ToVimscript() (string, error) {
+	command, function, err := d.action()
+	if err != nil {
+		return "", err
+	}
+	if function != "" {
+		command = "call " + function + "()"
+	}
+	if strings.ContainsAny(command+d.Pattern, "\r\n") {
+		return "", fmt.Errorf("autocmd: %s command or pattern spans lines", d.Event)
+	}
+
+	var b strings.Builder
+	if d.Desc != "" {
+		b.WriteString(`" ` + lineBreaks.Replace(d.Desc) + "\n")
+	}
+	b.WriteString("autocmd ")
+	if d.GroupName != "" {
+		b.WriteString(d.GroupName + " ")
+	}
+	b.WriteString(string(d.Event) + " ")
+	switch {
+	case d.BufLocal:
+		b.WriteString("<buffer=" + strconv.Itoa(d.Buffer) + "> ")
+	case d.Pattern != "":
+		b.WriteString(patternEscaper.Replace(d.Pattern) + " ")
+	default:
+		b.WriteString("* ")
+	}
+	if d.Once {
+		b.WriteString("++once ")
+	}
+	if d.Nested {
+		b.WriteString("++nested ")
+	}
+	b.WriteString(command)
+
+	return b.String(), nil
+}
+
+// lineBreaks joins the lines of a description.
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// patternEscaper escapes the white space ending :autocmd patterns.
+var patternEscaper = strings.NewReplacer(" ", `\ `, "\t", "\\\t")
+
+// ToLua returns the vim.api.nvim_create_autocmd call defining d.
+//
+// A callback must be the name of a Vimscript function.
+func (d *Definition) ToLua() (string, error) {
+	command, function, err := d.action()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("vim.api.nvim_create_autocmd(" + luaString(string(d.Event)) + ", {\n")
+	field := func(name, value string) {
+		b.WriteString("  " + name + " = " + value + ",\n")
+	}
+	if d.GroupName != "" {
+		field("group", luaString(d.GroupName))
+	}
+	switch {
+	case d.BufLocal:
+		field("buffer", strconv.Itoa(d.Buffer))
+	case d.Pattern != "":
+		field("pattern", luaString(d.Pattern))
+	}
+	if d.Once {
+		field("once", "true")
+	}
+	if d.Nested {
+		field("nested", "true")
+	}
+	if d.Desc != "" {
+		field("desc", luaString(d.Desc))
+	}
+	if function != "" {
+		field("callback", luaString(function))
+	} else {
+		field("command", luaString(command))
+	}
+	b.WriteString("})")
+
+	return b.String(), nil
+}
+
+// luaString returns s as a double-quoted Lua string literal.
+func luaString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				// Lua decimal escapes are portable across Lua 5.1 and
+				// LuaJIT, unlike \x escapes.
+				fmt.Fprintf(&b, `\%03d`, c)
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
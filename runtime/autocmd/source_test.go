@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"testing"
+
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+func TestToVimscript(t *testing.T) {
+	tests := []struct {
+		name string
+		def  autocmd.Definition
+		want string
+	}{
+		{
+			"command",
+			autocmd.Definition{Event: autocmd.BufWritePre, Pattern: "*.go", Command: `echo "saving"`},
+			`autocmd BufWritePre *.go echo "saving"`,
+		},
+		{
+			"bar",
+			autocmd.Definition{Event: autocmd.BufEnter, Command: "set nu | set rnu"},
+			"autocmd BufEnter * set nu | set rnu",
+		},
+		{
+			"group and flags",
+			autocmd.Definition{Event: autocmd.BufEnter, GroupName: "g", Pattern: "a b\tc", Once: true, Nested: true, Command: "e"},
+			"autocmd g BufEnter a\\ b\\\tc ++once ++nested e",
+		},
+		{
+			"buffer",
+			autocmd.Definition{Event: autocmd.BufLeave, BufLocal: true, Buffer: 3, Callback: "Leave"},
+			"autocmd BufLeave <buffer=3> call Leave()",
+		},
+		{
+			"description",
+			autocmd.Definition{Event: autocmd.BufEnter, Desc: "two\nlines \"quoted\"", Command: "e"},
+			"\" two lines \"quoted\"\nautocmd BufEnter * e",
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.def.ToVimscript()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+
+	for _, def := range []autocmd.Definition{
+		{Event: autocmd.BufEnter, Command: "echo 1\necho 2"},
+		{Event: autocmd.BufEnter, Pattern: "a\rb", Command: "e"},
+		{Event: autocmd.BufEnter, Callback: map[string]interface{}{}},
+		{Event: autocmd.BufEnter},
+	} {
+		if s, err := def.ToVimscript(); err == nil {
+			t.Errorf("ToVimscript(%+v) = %q, want an error", def, s)
+		}
+	}
+}
+
+func TestToLua(t *testing.T) {
+	tests := []struct {
+		name string
+		def  autocmd.Definition
+		want string
+	}{
+		{
+			"command",
+			autocmd.Definition{Event: autocmd.BufWritePre, Pattern: "*.go", Command: `echo "a\b" | echo 'c'`},
+			"vim.api.nvim_create_autocmd(\"BufWritePre\", {\n" +
+				"  pattern = \"*.go\",\n" +
+				"  command = \"echo \\\"a\\\\b\\\" | echo 'c'\",\n" +
+				"})",
+		},
+		{
+			"callback",
+			autocmd.Definition{Event: autocmd.BufLeave, GroupName: "g", BufLocal: true, Buffer: 3, Once: true, Nested: true, Desc: "line\nbreak\x01", Callback: "Leave"},
+			"vim.api.nvim_create_autocmd(\"BufLeave\", {\n" +
+				"  group = \"g\",\n" +
+				"  buffer = 3,\n" +
+				"  once = true,\n" +
+				"  nested = true,\n" +
+				"  desc = \"line\\nbreak\\001\",\n" +
+				"  callback = \"Leave\",\n" +
+				"})",
+		},
+		{
+			"multiline command",
+			autocmd.Definition{Event: autocmd.BufEnter, Command: "echo 1\r\necho 2\t"},
+			"vim.api.nvim_create_autocmd(\"BufEnter\", {\n" +
+				"  command = \"echo 1\\r\\necho 2\\t\",\n" +
+				"})",
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.def.ToLua()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+
+	def := autocmd.Definition{Event: autocmd.BufEnter, Callback: map[string]interface{}{}}
+	if s, err := def.ToLua(); err == nil {
+		t.Errorf("ToLua() of a Lua callback = %q, want an error", s)
+	}
+}
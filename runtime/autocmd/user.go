@@ -0,0 +1,52 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+)
+
+// PluginEvent is a plugin-specific User event.
+type PluginEvent struct {
+	pattern string
+}
+
+// UserEvent returns the User event name of plugin.
+//
+// The event is matched by the pattern plugin+name, following the usual
+// naming of plugin User events, so UserEvent("MyPlugin", "Ready") can be
+// handled with:
+//
+//	autocmd User MyPluginReady ...
+func UserEvent(plugin, name string) PluginEvent {
+	return PluginEvent{pattern: plugin + name}
+}
+
+// Pattern returns the User autocmd pattern of the event.
+func (e PluginEvent) Pattern() string { return e.pattern }
+
+// String implements fmt.Stringer.
+func (e PluginEvent) String() string { return "User " + e.pattern }
+
+// Fire executes the User autocmds of the event, passing data to their
+// callbacks as Args.Data.
+func (e PluginEvent) Fire(ctx context.Context, c Client, data interface{}) error {
+	return ExecUser(ctx, c, e.pattern, data)
+}
+
+// Register returns a Builder of an autocmd for the event.
+func (e PluginEvent) Register() *Builder {
+	return Register(User).Pattern(e.pattern)
+}
+
+// Subscribe subscribes fn to the event through d.
+func (e PluginEvent) Subscribe(ctx context.Context, d *Dispatcher, fn func(*Args)) (*Subscription, error) {
+	return d.Subscribe(ctx, User, e.pattern, fn)
+}
+
+// Wait blocks until the event fires or ctx is done, and returns the Args of
+// the event.
+func (e PluginEvent) Wait(ctx context.Context, c Client) (*Args, error) {
+	return WaitFor(ctx, c, User, e.pattern)
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import "fmt"
+
+// Version is a Neovim version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String implements fmt.Stringer.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is older than w.
+func (v Version) Less(w Version) bool {
+	if v.Major != w.Major {
+		return v.Major < w.Major
+	}
+	if v.Minor != w.Minor {
+		return v.Minor < w.Minor
+	}
+	return v.Patch < w.Patch
+}
+
+// Since returns the first Neovim version supporting e.
+//
+// It returns the zero Version for events supported by every Neovim
+// release, and for unknown events.
+func (e Event) Since() Version {
+	return events[e].since
+}
+
+// Deprecated reports whether e is only kept for Vim compatibility and is
+// never triggered by Neovim.
+func (e Event) Deprecated() bool {
+	return events[e].deprecated
+}
+
+// AvailableIn reports whether e is known and triggered by Neovim version v.
+func (e Event) AvailableIn(v Version) bool {
+	info, ok := events[e]
+	return ok && !info.deprecated && !v.Less(info.since)
+}
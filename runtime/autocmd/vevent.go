@@ -0,0 +1,199 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// TextYankPostEvent is the v:event of TextYankPost.
+type TextYankPostEvent struct {
+	Inclusive   bool     `msgpack:"inclusive"`
+	Operator    string   `msgpack:"operator"`
+	Regcontents []string `msgpack:"regcontents"`
+	Regname     string   `msgpack:"regname"`
+	Regtype     string   `msgpack:"regtype"`
+	Visual      bool     `msgpack:"visual"`
+}
+
+// DirChangedEvent is the v:event of DirChanged.
+type DirChangedEvent struct {
+	Cwd           string `msgpack:"cwd"`
+	Scope         string `msgpack:"scope"`
+	ChangedWindow bool   `msgpack:"changed_window"`
+}
+
+// DirChangedPreEvent is the v:event of DirChangedPre.
+type DirChangedPreEvent struct {
+	Directory string `msgpack:"directory"`
+	Scope     string `msgpack:"scope"`
+}
+
+// CompletedItem is a completion item as described in |complete-items|.
+type CompletedItem struct {
+	Word     string      `msgpack:"word"`
+	Abbr     string      `msgpack:"abbr"`
+	Menu     string      `msgpack:"menu"`
+	Info     string      `msgpack:"info"`
+	Kind     string      `msgpack:"kind"`
+	UserData interface{} `msgpack:"user_data"`
+}
+
+// CompleteChangedEvent is the v:event of CompleteChanged.
+type CompleteChangedEvent struct {
+	CompletedItem CompletedItem `msgpack:"completed_item"`
+	Height        int           `msgpack:"height"`
+	Width         int           `msgpack:"width"`
+	Row           int           `msgpack:"row"`
+	Col           int           `msgpack:"col"`
+	Size          int           `msgpack:"size"`
+	Scrollbar     bool          `msgpack:"scrollbar"`
+}
+
+// CmdlineEvent is the v:event of CmdlineChanged, CmdlineEnter and
+// CmdlineLeave.
+type CmdlineEvent struct {
+	// Abort is only set by CmdlineLeave.
+	Abort    bool   `msgpack:"abort"`
+	Cmdlevel int    `msgpack:"cmdlevel"`
+	Cmdtype  string `msgpack:"cmdtype"`
+}
+
+// ChanEvent is the v:event of ChanInfo and ChanOpen.
+//
+// See nvim_get_chan_info for the format of Info.
+type ChanEvent struct {
+	Info map[string]interface{} `msgpack:"info"`
+}
+
+// TermCloseEvent is the v:event of TermClose.
+type TermCloseEvent struct {
+	Status int `msgpack:"status"`
+}
+
+// UIEvent is the v:event of UIEnter and UILeave.
+type UIEvent struct {
+	Chan int `msgpack:"chan"`
+}
+
+// WinDelta is the change of a window's viewport reported by WinScrolled.
+type WinDelta struct {
+	Width   int `msgpack:"width"`
+	Height  int `msgpack:"height"`
+	Leftcol int `msgpack:"leftcol"`
+	Topline int `msgpack:"topline"`
+	Skipcol int `msgpack:"skipcol"`
+}
+
+// WinScrolledEvent is the v:event of WinScrolled.
+type WinScrolledEvent struct {
+	// All is the sum of the absolute changes of all windows.
+	All WinDelta
+
+	// Windows maps the ID of each changed window to its change.
+	Windows map[int]WinDelta
+}
+
+func (e *WinScrolledEvent) decodeVEvent(m map[string]interface{}) error {
+	e.Windows = make(map[int]WinDelta, len(m))
+	for k, v := range m {
+		var d WinDelta
+		if err := convert(&d, v); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		if k == "all" {
+			e.All = d
+			continue
+		}
+		id, err := strconv.Atoi(k)
+		if err != nil {
+			return fmt.Errorf("unexpected window ID %q", k)
+		}
+		e.Windows[id] = d
+	}
+
+	return nil
+}
+
+// WinResizedEvent is the v:event of WinResized.
+type WinResizedEvent struct {
+	// Windows lists the IDs of the windows whose size changed.
+	Windows []int `msgpack:"windows"`
+}
+
+// vEventTypes maps events to the type of their v:event.
+var vEventTypes = map[Event]reflect.Type{
+	TextYankPost:    reflect.TypeOf(TextYankPostEvent{}),
+	DirChanged:      reflect.TypeOf(DirChangedEvent{}),
+	DirChangedPre:   reflect.TypeOf(DirChangedPreEvent{}),
+	CompleteChanged: reflect.TypeOf(CompleteChangedEvent{}),
+	CmdlineChanged:  reflect.TypeOf(CmdlineEvent{}),
+	CmdlineEnter:    reflect.TypeOf(CmdlineEvent{}),
+	CmdlineLeave:    reflect.TypeOf(CmdlineEvent{}),
+	ChanInfo:        reflect.TypeOf(ChanEvent{}),
+	ChanOpen:        reflect.TypeOf(ChanEvent{}),
+	TermClose:       reflect.TypeOf(TermCloseEvent{}),
+	UIEnter:         reflect.TypeOf(UIEvent{}),
+	UILeave:         reflect.TypeOf(UIEvent{}),
+	WinScrolled:     reflect.TypeOf(WinScrolledEvent{}),
+	WinResized:      reflect.TypeOf(WinResizedEvent{}),
+	ModeChanged:     reflect.TypeOf(ModeChangedEvent{}),
+}
+
+// DecodeVEvent stores the v:event of a in the struct pointed to by v.
+//
+// v:event is decoded as by msgpack.Unmarshal: struct fields are matched
+// against the v:event keys named by their "msgpack" tag, and keys without
+// a matching field are ignored.
+func (a *Args) DecodeVEvent(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("autocmd: DecodeVEvent of non-pointer %T", v)
+	}
+	if d, ok := v.(interface {
+		decodeVEvent(map[string]interface{}) error
+	}); ok {
+		if err := d.decodeVEvent(a.VEvent); err != nil {
+			return fmt.Errorf("autocmd: decode %s v:event: %w", a.Event, err)
+		}
+		return nil
+	}
+	if err := convert(v, a.VEvent); err != nil {
+		return fmt.Errorf("autocmd: decode %s v:event: %w", a.Event, err)
+	}
+
+	return nil
+}
+
+// TypedVEvent returns the v:event of a decoded into the struct type of
+// a.Event, such as *TextYankPostEvent for TextYankPost.
+//
+// It returns nil if the event has no typed v:event.
+func (a *Args) TypedVEvent() (interface{}, error) {
+	typ, ok := vEventTypes[a.Event]
+	if !ok {
+		return nil, nil
+	}
+	v := reflect.New(typ).Interface()
+	if err := a.DecodeVEvent(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// convert stores the decoded msgpack value src in the value pointed to by
+// dst, as if dst was decoded from the encoding of src.
+func convert(dst, src interface{}) error {
+	data, err := msgpack.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	return msgpack.Unmarshal(data, dst)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+func TestTypedVEvent(t *testing.T) {
+	tests := []struct {
+		a    autocmd.Args
+		want interface{}
+	}{
+		{
+			autocmd.Args{Event: autocmd.TextYankPost, VEvent: map[string]interface{}{
+				"inclusive":   int64(1), // Vimscript numeric boolean
+				"operator":    "y",
+				"regcontents": []interface{}{"a", "b"},
+				"regname":     "",
+				"regtype":     "V",
+				"visual":      false,
+			}},
+			&autocmd.TextYankPostEvent{Inclusive: true, Operator: "y", Regcontents: []string{"a", "b"}, Regtype: "V"},
+		},
+		{
+			autocmd.Args{Event: autocmd.ChanOpen, VEvent: map[string]interface{}{"info": []interface{}{}}},
+			&autocmd.ChanEvent{Info: map[string]interface{}{}},
+		},
+		{
+			autocmd.Args{Event: autocmd.WinScrolled, VEvent: map[string]interface{}{
+				"all":  map[string]interface{}{"height": int64(0), "topline": int64(3)},
+				"1000": map[string]interface{}{"topline": int64(-3)},
+			}},
+			&autocmd.WinScrolledEvent{All: autocmd.WinDelta{Topline: 3}, Windows: map[int]autocmd.WinDelta{1000: {Topline: -3}}},
+		},
+		{autocmd.Args{Event: autocmd.BufEnter}, nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.TypedVEvent()
+		if err != nil {
+			t.Errorf("%s: %v", tt.a.Event, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.a.Event, got, tt.want)
+		}
+	}
+
+	bad := autocmd.Args{Event: autocmd.TermClose, VEvent: map[string]interface{}{"status": "x"}}
+	if _, err := bad.TypedVEvent(); err == nil {
+		t.Error("TypedVEvent of an invalid v:event succeeded")
+	}
+	var e autocmd.TermCloseEvent
+	if err := bad.DecodeVEvent(e); err == nil {
+		t.Error("DecodeVEvent into a non-pointer succeeded")
+	}
+}
+
+func TestGet(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_get_autocmds", []interface{}{
+		map[string]interface{}{"id": 3, "event": "BufEnter", "group": 2, "group_name": "g", "pattern": "*.go", "once": false, "desc": "d", "callback": "Fn"},
+		map[string]interface{}{"event": "BufLeave", "pattern": "<buffer=4>", "buffer": 4, "buflocal": true, "command": "echo"},
+	})
+
+	defs, err := autocmd.Get(context.Background(), v, autocmd.GetOptions{Group: "g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := autocmd.Definitions{
+		{ID: 3, Event: autocmd.BufEnter, Group: 2, GroupName: "g", Pattern: "*.go", Desc: "d", Callback: "Fn"},
+		{Event: autocmd.BufLeave, Pattern: "<buffer=4>", Buffer: 4, BufLocal: true, Command: "echo"},
+	}
+	if !reflect.DeepEqual(defs, want) {
+		t.Errorf("Get() = %+v, want %+v", defs, want)
+	}
+	if got := defs.ByBuffer(4); len(got) != 1 || got[0].Command != "echo" {
+		t.Errorf("ByBuffer(4) = %+v", got)
+	}
+	s.ExpectCall(t, "nvim_get_autocmds", map[string]interface{}{"group": "g"})
+}
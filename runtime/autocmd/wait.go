@@ -0,0 +1,38 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+)
+
+// WaitFor blocks until event fires for a file matching pattern, or ctx is
+// done, and returns the Args of the event.
+//
+// An empty pattern matches any file. The autocmd is created with Once, and
+// deleted if ctx is done before it fires.
+func WaitFor(ctx context.Context, c Client, event Event, pattern string) (*Args, error) {
+	fired := make(chan *Args, 1)
+	b := Register(event).
+		Once().
+		Desc("autocmd.WaitFor").
+		Callback(func(a *Args) { fired <- a })
+	if pattern != "" {
+		b.Pattern(pattern)
+	}
+
+	h, err := b.Create(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case a := <-fired:
+		return a, nil
+	case <-ctx.Done():
+		// Use a fresh context, since ctx is already done.
+		_ = h.Delete(context.Background())
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/go-nvim/pkg/nverror"
+)
+
+// WindowChange is the change of one window reported by WinScrolled or
+// WinResized.
+type WindowChange struct {
+	Event  Event
+	Window int
+
+	// Delta is the change of the viewport, reported by WinScrolled only.
+	Delta WinDelta
+}
+
+// WindowWatch fans out the changes of windows reported by WinScrolled and
+// WinResized, which report all the windows changed in one event, to
+// subscribers of single windows.
+type WindowWatch struct {
+	subs    []*Subscription
+	onError func(e Event, err error)
+
+	mu      sync.Mutex
+	windows map[int][]*windowSub
+}
+
+type windowSub struct {
+	fn func(WindowChange)
+}
+
+// WindowOption configures a WindowWatch.
+type WindowOption func(*WindowWatch)
+
+// WithDecodeErrorHandler sets the function called with the errors decoding
+// the v:event of an event, whose changes are then dropped. By default the
+// errors are ignored.
+func WithDecodeErrorHandler(fn func(e Event, err error)) WindowOption {
+	return func(w *WindowWatch) { w.onError = fn }
+}
+
+// WatchWindows returns a new WindowWatch subscribing to the events with d.
+// WinResized is only watched if Neovim supports it.
+func WatchWindows(ctx context.Context, d *Dispatcher, opts ...WindowOption) (*WindowWatch, error) {
+	w := &WindowWatch{
+		onError: func(Event, error) {},
+		windows: make(map[int][]*windowSub),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	s, err := d.Subscribe(ctx, WinScrolled, "", w.scrolled, Name("autocmd:windows"))
+	if err != nil {
+		return nil, err
+	}
+	w.subs = append(w.subs, s)
+
+	// Neovim before 0.9 rejects WinResized; WinScrolled then reports the
+	// size changes too.
+	s, err = d.Subscribe(ctx, WinResized, "", w.resized, Name("autocmd:windows"))
+	switch {
+	case err == nil:
+		w.subs = append(w.subs, s)
+	case !isUnknownEvent(err):
+		w.Close(ctx)
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// isUnknownEvent reports whether err is Neovim rejecting an event it does
+// not know, with "E216: No such event".
+func isUnknownEvent(err error) bool {
+	return errors.Is(err, nverror.Code(216))
+}
+
+// Subscribe calls fn with each change of the window win. The returned
+// function unsubscribes fn.
+func (w *WindowWatch) Subscribe(win int, fn func(WindowChange)) (unsubscribe func()) {
+	s := &windowSub{fn: fn}
+
+	w.mu.Lock()
+	w.windows[win] = append(w.windows[win], s)
+	w.mu.Unlock()
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		subs := w.windows[win]
+		for i, sub := range subs {
+			if sub == s {
+				subs = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(subs) == 0 {
+			delete(w.windows, win)
+			return
+		}
+		w.windows[win] = subs
+	}
+}
+
+func (w *WindowWatch) scrolled(a *Args) {
+	var e WinScrolledEvent
+	if err := a.DecodeVEvent(&e); err != nil {
+		w.onError(WinScrolled, err)
+		return
+	}
+	wins := make([]int, 0, len(e.Windows))
+	for win := range e.Windows {
+		wins = append(wins, win)
+	}
+	sort.Ints(wins)
+	for _, win := range wins {
+		w.send(WindowChange{Event: WinScrolled, Window: win, Delta: e.Windows[win]})
+	}
+}
+
+func (w *WindowWatch) resized(a *Args) {
+	var e WinResizedEvent
+	if err := a.DecodeVEvent(&e); err != nil {
+		w.onError(WinResized, err)
+		return
+	}
+	for _, win := range e.Windows {
+		w.send(WindowChange{Event: WinResized, Window: win})
+	}
+}
+
+// send calls the subscribers of the window of c.
+func (w *WindowWatch) send(c WindowChange) {
+	w.mu.Lock()
+	subs := append([]*windowSub(nil), w.windows[c.Window]...)
+	w.mu.Unlock()
+
+	for _, s := range subs {
+		s.fn(c)
+	}
+}
+
+// Close unsubscribes the WindowWatch from its events.
+func (w *WindowWatch) Close(ctx context.Context) error {
+	var err error
+	for _, s := range w.subs {
+		if e := s.Unsubscribe(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package autocmd_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/rpc"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// failWinResized makes the creation of WinResized autocmds fail with msg.
+func failWinResized(s *nvimtest.MockServer, msg string) *mockAutocmds {
+	m := newMockAutocmds(s)
+	s.Handle("nvim_exec_lua", func(args []interface{}) (interface{}, error) {
+		params, _ := args[1].([]interface{})
+		if events, _ := params[2].([]interface{}); len(events) == 1 && events[0] == "WinResized" {
+			return nil, &rpc.Error{Type: rpc.ExceptionError, Message: msg}
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		id := len(m.methods) + 1
+		m.methods[id], _ = params[1].(string)
+		return id, nil
+	})
+
+	return m
+}
+
+func TestWatchWindowsWithoutWinResized(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := failWinResized(s, "Error executing lua: vim/_editor.lua:0: E216: No such event: WinResized")
+
+	w, err := autocmd.WatchWindows(context.Background(), autocmd.NewDispatcher(v))
+	if err != nil {
+		t.Fatalf("WatchWindows() = %v, want WinScrolled alone", err)
+	}
+	if n := m.live(); n != 1 {
+		t.Errorf("%d autocmds created, want 1", n)
+	}
+	w.Close(context.Background())
+}
+
+func TestWatchWindowsError(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := failWinResized(s, "Error executing lua: out of memory")
+
+	if _, err := autocmd.WatchWindows(context.Background(), autocmd.NewDispatcher(v)); err == nil {
+		t.Fatal("WatchWindows() succeeded, want the error of WinResized")
+	}
+	if n := m.live(); n != 0 {
+		t.Errorf("%d autocmds left after the error", n)
+	}
+}
+
+func TestWatchWindowsDecodeError(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	m := newMockAutocmds(s)
+	errs := make(chan error, 1)
+	w, err := autocmd.WatchWindows(context.Background(), autocmd.NewDispatcher(v), autocmd.WithDecodeErrorHandler(func(e autocmd.Event, err error) {
+		if e != autocmd.WinScrolled {
+			t.Errorf("error of %s, want WinScrolled", e)
+		}
+		errs <- err
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close(context.Background())
+	changes := make(chan autocmd.WindowChange, 1)
+	w.Subscribe(1000, func(c autocmd.WindowChange) { changes <- c })
+
+	m.mu.Lock()
+	method := m.methods[1]
+	m.mu.Unlock()
+	args := map[string]interface{}{"id": 1, "event": "WinScrolled", "match": "1000", "buf": 1}
+	s.Notify(method, args, map[string]interface{}{"win": map[string]interface{}{"topline": 1}})
+	if err := <-errs; err == nil {
+		t.Error("nil error")
+	}
+	s.Notify(method, args, map[string]interface{}{"1000": map[string]interface{}{"topline": 2}})
+	if c := <-changes; c.Window != 1000 || c.Delta.Topline != 2 {
+		t.Errorf("got %+v, want window 1000 scrolled by 2 lines", c)
+	}
+}
+
+func TestIsUnknownEvent(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{&rpc.Error{Type: rpc.ExceptionError, Message: "Vim:E216: No such event: WinResized"}, true},
+		{&rpc.Error{Type: rpc.ExceptionError, Message: "Vim:E117: Unknown function: foo"}, false},
+		{errors.New("E216: No such event: WinResized"), false},
+	} {
+		if got := autocmd.IsUnknownEvent(tt.err); got != tt.want {
+			t.Errorf("isUnknownEvent(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package terminal handles the control sequences that programs running in
+// terminal buffers send to Neovim.
+package terminal
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+// OSC is an Operating System Command sequence sent by a program running in
+// a terminal buffer, such as "\x1b]7;file://host/dir".
+type OSC struct {
+	Buf     int    // terminal buffer
+	Code    int    // number of the command, such as 7
+	Payload string // text after the code and ";"
+}
+
+// ParseOSC parses the OSC sequence seq, as reported by TermRequest. The
+// terminator, BEL or ST, is optional. It reports false if seq is not an OSC
+// sequence.
+func ParseOSC(seq string) (OSC, bool) {
+	rest, ok := strings.CutPrefix(seq, "\x1b]")
+	if !ok {
+		return OSC{}, false
+	}
+	rest = strings.TrimSuffix(rest, "\a")
+	rest = strings.TrimSuffix(rest, "\x1b\\")
+	code, payload, _ := strings.Cut(rest, ";")
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return OSC{}, false
+	}
+
+	return OSC{Code: n, Payload: payload}, true
+}
+
+// Handler dispatches the OSC sequences reported by TermRequest to
+// handlers by code. TermRequest requires Neovim 0.10.
+type Handler struct {
+	sub     *autocmd.Subscription
+	timeout time.Duration
+	onError func(error)
+
+	mu       sync.Mutex
+	handlers map[int][]func(OSC)
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithTimeout sets the timeout of the calls to Neovim made by SyncCwd and
+// Clipboard. The default is 5 seconds.
+func WithTimeout(d time.Duration) Option {
+	return func(h *Handler) { h.timeout = d }
+}
+
+// WithErrorHandler calls fn with the errors of the calls to Neovim made by
+// SyncCwd and Clipboard. Without it, they are ignored.
+func WithErrorHandler(fn func(error)) Option {
+	return func(h *Handler) { h.onError = fn }
+}
+
+// NewHandler returns a new Handler subscribing to TermRequest with d.
+func NewHandler(ctx context.Context, d *autocmd.Dispatcher, opts ...Option) (*Handler, error) {
+	h := newHandler(opts)
+	sub, err := d.Subscribe(ctx, autocmd.TermRequest, "", h.request, autocmd.Name("terminal:osc"))
+	if err != nil {
+		return nil, err
+	}
+	h.sub = sub
+
+	return h, nil
+}
+
+func newHandler(opts []Option) *Handler {
+	h := &Handler{
+		timeout:  5 * time.Second,
+		onError:  func(error) {},
+		handlers: make(map[int][]func(OSC)),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// call calls fn with a context with the timeout of h, passing its error to
+// the error handler.
+func (h *Handler) call(fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	if err := fn(ctx); err != nil {
+		h.onError(err)
+	}
+}
+
+// Handle calls fn with the OSC sequences of the given code, after the
+// handlers registered before.
+func (h *Handler) Handle(code int, fn func(OSC)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[code] = append(h.handlers[code], fn)
+}
+
+func (h *Handler) request(a *autocmd.Args) {
+	osc, ok := ParseOSC(sequence(a.Data))
+	if !ok {
+		return
+	}
+	osc.Buf = a.Buf
+
+	h.mu.Lock()
+	fns := h.handlers[osc.Code]
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(osc)
+	}
+}
+
+// sequence returns the sequence of the data of TermRequest, a string
+// before Neovim 0.11 and a table with a sequence key since.
+func sequence(data interface{}) string {
+	switch d := data.(type) {
+	case string:
+		return d
+	case map[string]interface{}:
+		s, _ := d["sequence"].(string)
+		return s
+	}
+
+	return ""
+}
+
+// Close unsubscribes the Handler from TermRequest.
+func (h *Handler) Close(ctx context.Context) error {
+	return h.sub.Unsubscribe(ctx)
+}
+
+// cwdLua sets the directory of a terminal buffer, and the local directory
+// of the windows showing it.
+const cwdLua = `
+local buf, dir = ...
+vim.b[buf].term_cwd = dir
+for _, win in ipairs(vim.fn.win_findbuf(buf)) do
+  vim.api.nvim_win_call(win, function() vim.cmd.lcd(vim.fn.fnameescape(dir)) end)
+end
+`
+
+// SyncCwd handles the OSC 7 sequences, which shells send to report their
+// current directory as a file URL, by storing the directory in
+// b:term_cwd and making it the local directory of the windows showing the
+// terminal. Directories on other hosts, such as from an ssh session, are
+// ignored.
+func (h *Handler) SyncCwd(v *nvim.Nvim) {
+	h.Handle(7, func(osc OSC) {
+		u, err := url.Parse(osc.Payload)
+		if err != nil || u.Scheme != "file" || u.Path == "" || !isLocalHost(u.Hostname()) {
+			return
+		}
+		h.call(func(ctx context.Context) error {
+			if _, err := v.ExecLua(ctx, cwdLua, []interface{}{osc.Buf, u.Path}); err != nil {
+				return fmt.Errorf("terminal: set directory of buffer %d: %w", osc.Buf, err)
+			}
+			return nil
+		})
+	})
+}
+
+// isLocalHost reports whether host, of a file URL, is this machine.
+func isLocalHost(host string) bool {
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	name, err := os.Hostname()
+
+	return err == nil && strings.EqualFold(host, name)
+}
+
+// Clipboard handles the OSC 52 sequences, which programs send to write to
+// the clipboard, by setting the "+" register, or the "*" register for the
+// primary selection. Queries of the clipboard are ignored.
+func (h *Handler) Clipboard(v *nvim.Nvim) {
+	h.Handle(52, func(osc OSC) {
+		target, data, ok := strings.Cut(osc.Payload, ";")
+		if !ok || data == "?" {
+			return
+		}
+		text, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return
+		}
+		reg := "+"
+		if strings.ContainsAny(target, "ps") && !strings.Contains(target, "c") {
+			reg = "*"
+		}
+		h.call(func(ctx context.Context) error {
+			if _, err := v.CallFunction(ctx, "setreg", []interface{}{reg, string(text)}); err != nil {
+				return fmt.Errorf("terminal: set register %s: %w", reg, err)
+			}
+			return nil
+		})
+	})
+}
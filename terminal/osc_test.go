@@ -0,0 +1,106 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package terminal
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+)
+
+func TestParseOSC(t *testing.T) {
+	tests := []struct {
+		seq  string
+		want OSC
+		ok   bool
+	}{
+		{"\x1b]7;file://host/dir\a", OSC{Code: 7, Payload: "file://host/dir"}, true},
+		{"\x1b]52;c;aGk=\x1b\\", OSC{Code: 52, Payload: "c;aGk="}, true},
+		{"\x1b]133;A", OSC{Code: 133, Payload: "A"}, true},
+		{"\x1b]2", OSC{Code: 2}, true},
+		{"\x1b[31m", OSC{}, false},
+		{"\x1b]x;y\a", OSC{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseOSC(tt.seq)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseOSC(%q) = %+v, %v; want %+v, %v", tt.seq, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSequence(t *testing.T) {
+	if s := sequence("\x1b]7;x"); s != "\x1b]7;x" {
+		t.Errorf("sequence(string) = %q", s)
+	}
+	if s := sequence(map[string]interface{}{"sequence": "\x1b]7;x", "cursor": []interface{}{1, 0}}); s != "\x1b]7;x" {
+		t.Errorf("sequence(table) = %q", s)
+	}
+}
+
+// request reports seq from the terminal buffer buf to h.
+func request(h *Handler, buf int, seq string) {
+	h.request(&autocmd.Args{Event: autocmd.TermRequest, Buf: buf, Data: seq})
+}
+
+func TestSyncCwd(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_exec_lua", nil)
+	h := newHandler(nil)
+	h.SyncCwd(v)
+
+	host, _ := os.Hostname()
+	request(h, 3, "\x1b]7;file://"+host+"/home/me/a%20b\a")
+	request(h, 3, "\x1b]7;file:///tmp\a")
+	request(h, 3, "\x1b]7;file://remote.invalid/srv\a")
+	request(h, 3, "\x1b]7;http://host/x\a")
+
+	var dirs []string
+	for _, c := range s.Calls() {
+		params := c.Args[1].([]interface{})
+		if params[0] != int64(3) {
+			t.Errorf("buffer %v, want 3", params[0])
+		}
+		dirs = append(dirs, params[1].(string))
+	}
+	if got, want := strings.Join(dirs, ","), "/home/me/a b,/tmp"; got != want {
+		t.Errorf("directories set %q, want %q", got, want)
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_call_function", 0)
+	h := newHandler(nil)
+	h.Clipboard(v)
+
+	data := base64.StdEncoding.EncodeToString([]byte("hello"))
+	request(h, 1, "\x1b]52;c;"+data+"\a")
+	request(h, 1, "\x1b]52;p;"+data+"\a")
+	request(h, 1, "\x1b]52;c;?\a")
+	request(h, 1, "\x1b]52;c;!!\a")
+
+	s.ExpectCall(t, "nvim_call_function", "setreg", []interface{}{"+", "hello"})
+	s.ExpectCall(t, "nvim_call_function", "setreg", []interface{}{"*", "hello"})
+	if n := len(s.Calls()); n != 2 {
+		t.Errorf("%d calls, want 2: %v", n, s.Calls())
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Fail("nvim_exec_lua", "E344: Can't find directory")
+	var errs []error
+	h := newHandler([]Option{WithErrorHandler(func(err error) { errs = append(errs, err) })})
+	h.SyncCwd(v)
+
+	request(h, 2, "\x1b]7;file:///gone\a")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "E344") {
+		t.Errorf("errors = %v, want the E344 error", errs)
+	}
+}
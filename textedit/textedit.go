@@ -0,0 +1,179 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package textedit applies sets of text edits, such as those of formatters
+// and LSP workspace edits, to buffers.
+package textedit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/textpos"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Edit replaces the text in Range with NewText. Columns are byte columns;
+// convert LSP ranges with textpos.ConvertRange. An empty Range inserts
+// NewText.
+type Edit struct {
+	Range   textpos.Range
+	NewText string
+}
+
+// Prepare returns edits sorted by position, with the edits that touch
+// merged, for applying them in reverse order. Insertions at the same
+// position keep their order. It returns an error if edits overlap or a
+// range ends before it starts.
+func Prepare(edits []Edit) ([]Edit, error) {
+	sorted := append([]Edit(nil), edits...)
+	for _, e := range sorted {
+		if e.Range.End.Less(e.Range.Start) {
+			return nil, fmt.Errorf("textedit: invalid range %s", e.Range)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Range.Start.Less(sorted[j].Range.Start)
+	})
+
+	var merged []Edit
+	for _, e := range sorted {
+		if len(merged) == 0 {
+			merged = append(merged, e)
+			continue
+		}
+		last := &merged[len(merged)-1]
+		switch {
+		case e.Range.Start.Less(last.Range.End):
+			return nil, fmt.Errorf("textedit: overlapping edits at %s and %s", last.Range, e.Range)
+		case e.Range.Start == last.Range.End:
+			last.Range.End = e.Range.End
+			last.NewText += e.NewText
+		default:
+			merged = append(merged, e)
+		}
+	}
+
+	return merged, nil
+}
+
+// Apply applies edits to buf.
+//
+// Positions on the line past the last one, such as the end of the
+// whole-buffer edits of formatters, are clamped to the end of the last
+// line, as the final newline of the buffer is implied: the newline that
+// then ends the new text of the edit is dropped, and one is added before
+// the new text of an edit starting there.
+//
+// The edits are prepared with Prepare, and each is narrowed to the text it
+// actually changes, so that the marks and extmarks in unchanged text, such
+// as when a formatter replaces the whole buffer, are kept. The buffer is
+// read and edited in three round trips, each atomic.
+func Apply(ctx context.Context, v *nvim.Nvim, buf types.Buffer, edits []Edit) error {
+	if len(edits) == 0 {
+		return nil
+	}
+
+	var count int
+	var last []string
+	b := v.NewBatch()
+	b.BufLineCount(buf, &count)
+	b.BufGetLines(buf, -2, -1, true, &last)
+	if err := b.Execute(ctx); err != nil {
+		return err
+	}
+	if len(last) != 1 {
+		return fmt.Errorf("textedit: %s is not loaded", buf)
+	}
+
+	edits, err := Prepare(clamp(edits, count, len(last[0])))
+	if err != nil {
+		return err
+	}
+
+	old := make([][]string, len(edits))
+	for i, e := range edits {
+		r := e.Range
+		b.BufGetText(buf, r.Start.Line, r.Start.Col, r.End.Line, r.End.Col, map[string]interface{}{}, &old[i])
+	}
+	if err := b.Execute(ctx); err != nil {
+		return err
+	}
+
+	for i := len(edits) - 1; i >= 0; i-- {
+		e, ok := narrow(edits[i], strings.Join(old[i], "\n"))
+		if !ok {
+			continue
+		}
+		r := e.Range
+		b.BufSetText(buf, r.Start.Line, r.Start.Col, r.End.Line, r.End.Col, strings.Split(e.NewText, "\n"))
+	}
+
+	return b.Execute(ctx)
+}
+
+// clamp returns edits with the positions past the last line of a buffer of
+// count lines moved to the end of that line, of length lastLen, adjusting
+// their new text for the implied final newline.
+func clamp(edits []Edit, count, lastLen int) []Edit {
+	end := textpos.Pos{Line: count - 1, Col: lastLen}
+	clamped := make([]Edit, len(edits))
+	for i, e := range edits {
+		if e.Range.Start.Line >= count {
+			e.Range.Start = end
+			e.NewText = "\n" + e.NewText
+		}
+		if e.Range.End.Line >= count {
+			e.Range.End = end
+			e.NewText = strings.TrimSuffix(e.NewText, "\n")
+		}
+		clamped[i] = e
+	}
+
+	return clamped
+}
+
+// narrow returns e without the text its new text has in common with old,
+// the text it replaces, at its start and end. It returns false if e
+// changes nothing.
+func narrow(e Edit, old string) (Edit, bool) {
+	if old == e.NewText {
+		return e, false
+	}
+
+	prefix := 0
+	for prefix < len(old) && prefix < len(e.NewText) && old[prefix] == e.NewText[prefix] {
+		prefix++
+	}
+	for prefix > 0 && prefix < len(old) && !utf8.RuneStart(old[prefix]) {
+		prefix--
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(e.NewText)-prefix &&
+		old[len(old)-1-suffix] == e.NewText[len(e.NewText)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(old[len(old)-suffix]) {
+		suffix--
+	}
+
+	start := advance(e.Range.Start, old[:prefix])
+	return Edit{
+		Range:   textpos.Range{Start: start, End: advance(start, old[prefix:len(old)-suffix])},
+		NewText: e.NewText[prefix : len(e.NewText)-suffix],
+	}, true
+}
+
+// advance returns the position after text starting at p.
+func advance(p textpos.Pos, text string) textpos.Pos {
+	i := strings.LastIndexByte(text, '\n')
+	if i < 0 {
+		return textpos.Pos{Line: p.Line, Col: p.Col + len(text)}
+	}
+
+	return textpos.Pos{Line: p.Line + strings.Count(text, "\n"), Col: len(text) - i - 1}
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package textedit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/textedit"
+	"github.com/go-nvim/pkg/textpos"
+	"github.com/go-nvim/pkg/types"
+)
+
+// mockBuffer makes the mock serve buffer 1 with lines a and b.
+func mockBuffer(s *nvimtest.MockServer) {
+	s.Return("nvim_buf_line_count", 2)
+	s.Return("nvim_buf_get_lines", []string{"b"})
+	s.Return("nvim_buf_get_text", []string{"a", "b"})
+	s.Return("nvim_buf_set_text", nil)
+}
+
+func TestApplyWholeBuffer(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	mockBuffer(s)
+
+	// A formatter replacing the whole buffer, ending on the line past
+	// the last one.
+	edit := textedit.Edit{
+		Range:   textpos.Range{Start: textpos.Pos{Line: 0, Col: 0}, End: textpos.Pos{Line: 2, Col: 0}},
+		NewText: "a\nc\n",
+	}
+	if err := textedit.Apply(context.Background(), v, 1, []textedit.Edit{edit}); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_buf_get_text", types.Buffer(1), 0, 0, 1, 1, map[string]interface{}{})
+	s.ExpectCall(t, "nvim_buf_set_text", types.Buffer(1), 1, 0, 1, 1, []string{"c"})
+}
+
+func TestApplyAppend(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	mockBuffer(s)
+	s.Return("nvim_buf_get_text", []string{""})
+
+	edit := textedit.Edit{
+		Range:   textpos.Range{Start: textpos.Pos{Line: 2, Col: 0}, End: textpos.Pos{Line: 2, Col: 0}},
+		NewText: "c\n",
+	}
+	if err := textedit.Apply(context.Background(), v, 1, []textedit.Edit{edit}); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_buf_set_text", types.Buffer(1), 1, 1, 1, 1, []string{"", "c"})
+}
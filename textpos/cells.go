@@ -0,0 +1,153 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package textpos
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// wide lists the ranges of East Asian wide and fullwidth characters, and of
+// emoji, displayed in two cells.
+var wide = [][2]rune{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x2329, 0x232a},
+	{0x23e9, 0x23ec},
+	{0x23f0, 0x23f0},
+	{0x23f3, 0x23f3},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267f, 0x267f},
+	{0x2693, 0x2693},
+	{0x26a1, 0x26a1},
+	{0x26aa, 0x26ab},
+	{0x26bd, 0x26be},
+	{0x26c4, 0x26c5},
+	{0x26ce, 0x26ce},
+	{0x26d4, 0x26d4},
+	{0x26ea, 0x26ea},
+	{0x26f2, 0x26f3},
+	{0x26f5, 0x26f5},
+	{0x26fa, 0x26fa},
+	{0x26fd, 0x26fd},
+	{0x2705, 0x2705},
+	{0x270a, 0x270b},
+	{0x2728, 0x2728},
+	{0x274c, 0x274c},
+	{0x274e, 0x274e},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27b0, 0x27b0},
+	{0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c},
+	{0x2b50, 0x2b50},
+	{0x2b55, 0x2b55},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xa960, 0xa97f},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f004, 0x1f004},
+	{0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a},
+	{0x1f200, 0x1f251},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f7e0, 0x1f7eb},
+	{0x1f90c, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
+
+// RuneCells returns the number of cells r is displayed in, outside of a
+// tab: 0 for combining marks and other zero-width characters, 2 for wide
+// characters, and 1 otherwise.
+//
+// This follows the Unicode East Asian Width property, as Neovim does with
+// the default 'ambiwidth' and without setcellwidths().
+func RuneCells(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 0x20 || r == 0x7f:
+		// Displayed as ^X.
+		return 2
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	lo, hi := 0, len(wide)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < wide[m][0]:
+			hi = m
+		case r > wide[m][1]:
+			lo = m + 1
+		default:
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// Cells returns the display column, counted in cells, of the byte column
+// col of line, with tabs every tabstop cells.
+func Cells(line string, col, tabstop int) int {
+	if col > len(line) {
+		col = len(line)
+	}
+
+	cells := 0
+	for i := 0; i < col; {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if i+size > col {
+			break
+		}
+		if r == '\t' && tabstop > 0 {
+			cells += tabstop - cells%tabstop
+		} else {
+			cells += RuneCells(r)
+		}
+		i += size
+	}
+
+	return cells
+}
+
+// ByteCol returns the byte column of line displayed at the display column
+// cells, with tabs every tabstop cells. A display column inside a wide
+// character or tab is moved to its start.
+func ByteCol(line string, cells, tabstop int) int {
+	c := 0
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		w := RuneCells(r)
+		if r == '\t' && tabstop > 0 {
+			w = tabstop - c%tabstop
+		}
+		if c+w > cells {
+			return i
+		}
+		c += w
+		i += size
+	}
+
+	return len(line)
+}
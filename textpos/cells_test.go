@@ -0,0 +1,81 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package textpos_test
+
+import (
+	"testing"
+
+	"github.com/go-nvim/pkg/textpos"
+)
+
+func TestRuneCells(t *testing.T) {
+	tests := map[rune]int{
+		'a':     1,
+		'é':     1,
+		0:       0,
+		0x1f:    2, // ^_
+		0x7f:    2, // ^?
+		0x301:   0, // combining acute accent
+		0x200b:  0, // zero width space
+		'世':     2,
+		0xff01:  2, // fullwidth exclamation mark
+		0x1f600: 2, // 😀
+		0x2500:  1, // box drawing
+	}
+	for r, want := range tests {
+		if got := textpos.RuneCells(r); got != want {
+			t.Errorf("RuneCells(%U) = %d, want %d", r, got, want)
+		}
+	}
+}
+
+func TestCells(t *testing.T) {
+	tests := []struct {
+		line         string
+		col, tabstop int
+		want         int
+	}{
+		{"a\tb", 1, 8, 1},
+		{"a\tb", 2, 8, 8},
+		{"a\tb", 3, 8, 9},
+		{"a\tb", 2, 4, 4},
+		{"a\tb", 2, 0, 3}, // tab shown as ^I
+		{"世界", 3, 8, 2},
+		{"世界", 4, 8, 2}, // inside 界
+		{"世界", 6, 8, 4},
+		{"éx", 3, 8, 1},
+		{"éx", 99, 8, 2},
+	}
+	for _, tt := range tests {
+		if got := textpos.Cells(tt.line, tt.col, tt.tabstop); got != tt.want {
+			t.Errorf("Cells(%q, %d, %d) = %d, want %d", tt.line, tt.col, tt.tabstop, got, tt.want)
+		}
+	}
+}
+
+func TestByteCol(t *testing.T) {
+	tests := []struct {
+		line           string
+		cells, tabstop int
+		want           int
+	}{
+		{"a\tb", 0, 8, 0},
+		{"a\tb", 1, 8, 1},
+		{"a\tb", 4, 8, 1}, // inside the tab
+		{"a\tb", 8, 8, 2},
+		{"a\tb", 99, 8, 3},
+		{"世界", 1, 8, 0}, // inside 世
+		{"世界", 2, 8, 3},
+		{"éx", 1, 8, 3},
+	}
+	for _, tt := range tests {
+		if got := textpos.ByteCol(tt.line, tt.cells, tt.tabstop); got != tt.want {
+			t.Errorf("ByteCol(%q, %d, %d) = %d, want %d", tt.line, tt.cells, tt.tabstop, got, tt.want)
+		}
+		// The display column of a byte column maps back to it.
+		if c := textpos.Cells(tt.line, tt.want, tt.tabstop); textpos.ByteCol(tt.line, c, tt.tabstop) != tt.want {
+			t.Errorf("ByteCol(Cells(%q, %d)) != %d", tt.line, tt.want, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package textpos converts positions in text between the columns of
+// Neovim, counted in bytes, of LSP, counted in UTF-16 code units, and of
+// the screen, counted in display cells.
+package textpos
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Pos is a position in text. Line and Col are zero-based, and Col is
+// counted in the units of an Encoding.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Col)
+}
+
+// Less reports whether p is before q.
+func (p Pos) Less(q Pos) bool {
+	return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col
+}
+
+// Range is the text from Start, inclusive, to End, exclusive.
+type Range struct {
+	Start Pos
+	End   Pos
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%s-%s", r.Start, r.End)
+}
+
+// IsEmpty reports whether r contains no text.
+func (r Range) IsEmpty() bool {
+	return !r.Start.Less(r.End)
+}
+
+// Contains reports whether p is in r.
+func (r Range) Contains(p Pos) bool {
+	return !p.Less(r.Start) && p.Less(r.End)
+}
+
+// Encoding is the unit of columns.
+type Encoding int
+
+// List of encodings.
+const (
+	UTF8  Encoding = iota // bytes, as Neovim
+	UTF16                 // UTF-16 code units, as LSP by default
+	UTF32                 // code points
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case UTF8:
+		return "utf-8"
+	case UTF16:
+		return "utf-16"
+	case UTF32:
+		return "utf-32"
+	}
+
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// width returns the width of r in e.
+func (e Encoding) width(r rune, size int) int {
+	switch e {
+	case UTF16:
+		if r >= 0x10000 {
+			return 2
+		}
+		return 1
+	case UTF32:
+		return 1
+	}
+
+	return size
+}
+
+// Convert returns the column col of line counted in from, counted in to.
+// A column inside a character is moved to its start, and a column past
+// the end of line to the end.
+func Convert(line string, col int, from, to Encoding) int {
+	if from == to {
+		return col
+	}
+
+	var src, dst int
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		w := from.width(r, size)
+		if src+w > col {
+			break
+		}
+		src += w
+		dst += to.width(r, size)
+		i += size
+	}
+
+	return dst
+}
+
+// ConvertPos returns p, whose column is counted in from, with its column
+// counted in to, using lines, the lines of the text. A line past the end
+// of lines is returned unchanged.
+func ConvertPos(lines []string, p Pos, from, to Encoding) Pos {
+	if p.Line < 0 || p.Line >= len(lines) {
+		return p
+	}
+
+	return Pos{Line: p.Line, Col: Convert(lines[p.Line], p.Col, from, to)}
+}
+
+// ConvertRange returns r with its columns converted as by ConvertPos.
+func ConvertRange(lines []string, r Range, from, to Encoding) Range {
+	return Range{
+		Start: ConvertPos(lines, r.Start, from, to),
+		End:   ConvertPos(lines, r.End, from, to),
+	}
+}
+
+// Len returns the length of line in e.
+func Len(line string, e Encoding) int {
+	return Convert(line, len(line), UTF8, e)
+}
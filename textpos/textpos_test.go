@@ -0,0 +1,89 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package textpos_test
+
+import (
+	"testing"
+
+	"github.com/go-nvim/pkg/textpos"
+)
+
+// line has characters of 1, 2 and 4 bytes, the last one taking a surrogate
+// pair in UTF-16.
+const line = "aé😀b"
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		col      int
+		from, to textpos.Encoding
+		want     int
+	}{
+		{0, textpos.UTF8, textpos.UTF16, 0},
+		{1, textpos.UTF8, textpos.UTF16, 1},
+		{3, textpos.UTF8, textpos.UTF16, 2},
+		{7, textpos.UTF8, textpos.UTF16, 4},
+		{8, textpos.UTF8, textpos.UTF16, 5},
+		{2, textpos.UTF8, textpos.UTF16, 1},  // inside é
+		{5, textpos.UTF8, textpos.UTF16, 2},  // inside 😀
+		{99, textpos.UTF8, textpos.UTF16, 5}, // past the end
+		{4, textpos.UTF16, textpos.UTF8, 7},
+		{3, textpos.UTF16, textpos.UTF8, 3}, // inside the surrogate pair
+		{5, textpos.UTF16, textpos.UTF8, 8},
+		{3, textpos.UTF32, textpos.UTF8, 7},
+		{3, textpos.UTF32, textpos.UTF16, 4},
+		{4, textpos.UTF16, textpos.UTF32, 3},
+		{99, textpos.UTF8, textpos.UTF8, 99},
+	}
+	for _, tt := range tests {
+		if got := textpos.Convert(line, tt.col, tt.from, tt.to); got != tt.want {
+			t.Errorf("Convert(%q, %d, %s, %s) = %d, want %d", line, tt.col, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestLen(t *testing.T) {
+	for e, want := range map[textpos.Encoding]int{textpos.UTF8: 8, textpos.UTF16: 5, textpos.UTF32: 4} {
+		if got := textpos.Len(line, e); got != want {
+			t.Errorf("Len(%q, %s) = %d, want %d", line, e, got, want)
+		}
+	}
+}
+
+func TestConvertRange(t *testing.T) {
+	lines := []string{"x", line}
+	r := textpos.Range{Start: textpos.Pos{Line: 1, Col: 1}, End: textpos.Pos{Line: 2, Col: 3}}
+	got := textpos.ConvertRange(lines, r, textpos.UTF16, textpos.UTF8)
+	want := textpos.Range{Start: textpos.Pos{Line: 1, Col: 1}, End: textpos.Pos{Line: 2, Col: 3}}
+	if got != want {
+		t.Errorf("ConvertRange(%s) = %s, want %s", r, got, want)
+	}
+	r.End = textpos.Pos{Line: 1, Col: 4}
+	want.End = textpos.Pos{Line: 1, Col: 7}
+	if got := textpos.ConvertRange(lines, r, textpos.UTF16, textpos.UTF8); got != want {
+		t.Errorf("ConvertRange(%s) = %s, want %s", r, got, want)
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := textpos.Range{Start: textpos.Pos{Line: 1, Col: 2}, End: textpos.Pos{Line: 3, Col: 0}}
+	if s := r.String(); s != "1:2-3:0" {
+		t.Errorf("String() = %q", s)
+	}
+	if r.IsEmpty() {
+		t.Errorf("%s is empty", r)
+	}
+	for p, want := range map[textpos.Pos]bool{
+		{Line: 1, Col: 1}: false,
+		{Line: 1, Col: 2}: true,
+		{Line: 2, Col: 9}: true,
+		{Line: 3, Col: 0}: false,
+	} {
+		if got := r.Contains(p); got != want {
+			t.Errorf("%s contains %s: %v, want %v", r, p, got, want)
+		}
+	}
+	if e := (textpos.Range{Start: r.End, End: r.Start}); !e.IsEmpty() {
+		t.Errorf("%s is not empty", e)
+	}
+}
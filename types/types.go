@@ -0,0 +1,148 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package types defines the handle types of the Neovim API.
+//
+// Handles are encoded as msgpack extension values, so that a Buffer, a
+// Window and a Tabpage cannot be confused with each other or with plain
+// integers.
+package types
+
+import (
+	"fmt"
+
+	"github.com/go-nvim/pkg/msgpack"
+)
+
+// List of the extension types of handles, as reported by nvim --api-info.
+const (
+	BufferExt  int8 = 0
+	WindowExt  int8 = 1
+	TabpageExt int8 = 2
+)
+
+func init() {
+	msgpack.RegisterExt(BufferExt, func(data []byte) (interface{}, error) {
+		n, err := decodeHandleData(data)
+		return Buffer(n), err
+	})
+	msgpack.RegisterExt(WindowExt, func(data []byte) (interface{}, error) {
+		n, err := decodeHandleData(data)
+		return Window(n), err
+	})
+	msgpack.RegisterExt(TabpageExt, func(data []byte) (interface{}, error) {
+		n, err := decodeHandleData(data)
+		return Tabpage(n), err
+	})
+}
+
+// Buffer is the handle of a buffer.
+type Buffer int
+
+// MarshalMsgPack implements msgpack.Marshaler.
+func (b Buffer) MarshalMsgPack(e *msgpack.Encoder) error {
+	return encodeHandle(e, BufferExt, int(b))
+}
+
+// UnmarshalMsgPack implements msgpack.Unmarshaler.
+func (b *Buffer) UnmarshalMsgPack(d *msgpack.Decoder) error {
+	n, err := decodeHandle(d, BufferExt)
+	*b = Buffer(n)
+	return err
+}
+
+func (b Buffer) String() string { return fmt.Sprintf("Buffer:%d", int(b)) }
+
+// Window is the handle of a window.
+type Window int
+
+// MarshalMsgPack implements msgpack.Marshaler.
+func (w Window) MarshalMsgPack(e *msgpack.Encoder) error {
+	return encodeHandle(e, WindowExt, int(w))
+}
+
+// UnmarshalMsgPack implements msgpack.Unmarshaler.
+func (w *Window) UnmarshalMsgPack(d *msgpack.Decoder) error {
+	n, err := decodeHandle(d, WindowExt)
+	*w = Window(n)
+	return err
+}
+
+func (w Window) String() string { return fmt.Sprintf("Window:%d", int(w)) }
+
+// Tabpage is the handle of a tabpage.
+type Tabpage int
+
+// MarshalMsgPack implements msgpack.Marshaler.
+func (t Tabpage) MarshalMsgPack(e *msgpack.Encoder) error {
+	return encodeHandle(e, TabpageExt, int(t))
+}
+
+// UnmarshalMsgPack implements msgpack.Unmarshaler.
+func (t *Tabpage) UnmarshalMsgPack(d *msgpack.Decoder) error {
+	n, err := decodeHandle(d, TabpageExt)
+	*t = Tabpage(n)
+	return err
+}
+
+func (t Tabpage) String() string { return fmt.Sprintf("Tabpage:%d", int(t)) }
+
+// encodeHandle encodes the handle n as an extension value of type typ,
+// whose data is the msgpack encoding of n.
+func encodeHandle(e *msgpack.Encoder, typ int8, n int) error {
+	data, err := msgpack.Marshal(n)
+	if err != nil {
+		return err
+	}
+	e.EncodeExt(typ, data)
+
+	return nil
+}
+
+// decodeHandle decodes a handle encoded as an extension value of type typ.
+// Plain integers are accepted too, since some API functions return handles
+// as numbers.
+func decodeHandle(d *msgpack.Decoder, typ int8) (int, error) {
+	v, err := d.DecodeInterface()
+	if err != nil {
+		return 0, err
+	}
+
+	var got int8
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return int(v), nil
+	case Buffer:
+		if typ == BufferExt {
+			return int(v), nil
+		}
+		got = BufferExt
+	case Window:
+		if typ == WindowExt {
+			return int(v), nil
+		}
+		got = WindowExt
+	case Tabpage:
+		if typ == TabpageExt {
+			return int(v), nil
+		}
+		got = TabpageExt
+	case msgpack.Extension:
+		got = v.Type
+	default:
+		return 0, fmt.Errorf("types: cannot decode %T as a handle", v)
+	}
+
+	return 0, fmt.Errorf("types: cannot decode extension type %d as a handle of type %d", got, typ)
+}
+
+func decodeHandleData(data []byte) (int, error) {
+	var n int
+	if err := msgpack.Unmarshal(data, &n); err != nil {
+		return 0, fmt.Errorf("types: invalid handle: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-nvim/pkg/msgpack"
+	"github.com/go-nvim/pkg/types"
+)
+
+func TestEncoding(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want []byte
+	}{
+		{types.Buffer(5), []byte{0xd4, 0x00, 0x05}},
+		{types.Window(1000), []byte{0xc7, 0x03, 0x01, 0xcd, 0x03, 0xe8}},
+		{types.Tabpage(-1), []byte{0xd4, 0x02, 0xff}},
+	}
+	for _, tt := range tests {
+		data, err := msgpack.Marshal(tt.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, tt.want) {
+			t.Errorf("Marshal(%v) = % x, want % x", tt.v, data, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := struct {
+		B types.Buffer
+		W types.Window
+		T types.Tabpage
+		L []types.Window
+	}{7, 1001, 2, []types.Window{1000, 1002}}
+	data, err := msgpack.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := in
+	out.B, out.W, out.T, out.L = 0, 0, 0, nil
+	if err := msgpack.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.B != in.B || out.W != in.W || out.T != in.T || len(out.L) != 2 || out.L[0] != 1000 || out.L[1] != 1002 {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+
+	// Decoded without a type, the handles keep theirs.
+	var v map[string]interface{}
+	if err := msgpack.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["B"] != types.Buffer(7) || v["W"] != types.Window(1001) || v["T"] != types.Tabpage(2) {
+		t.Errorf("got %#v", v)
+	}
+}
+
+func TestDecodeHandle(t *testing.T) {
+	// Plain integers and nil are accepted.
+	for data, want := range map[string]types.Buffer{"\x05": 5, "\xcd\x03\xe8": 1000, "\xc0": 0} {
+		var b types.Buffer = 9
+		if err := msgpack.Unmarshal([]byte(data), &b); err != nil || b != want {
+			t.Errorf("Unmarshal(% x) = %v, %v; want %v", data, b, err, want)
+		}
+	}
+
+	// Handles of another type are not.
+	data, _ := msgpack.Marshal(types.Window(1000))
+	var b types.Buffer
+	if err := msgpack.Unmarshal(data, &b); err == nil {
+		t.Errorf("Window decoded as %v", b)
+	}
+	var w types.Window
+	if err := msgpack.Unmarshal([]byte{0xd4, 0x09, 0x01}, &w); err == nil {
+		t.Errorf("unknown extension decoded as %v", w)
+	}
+	if err := msgpack.Unmarshal([]byte{0xa1, 'x'}, &w); err == nil {
+		t.Errorf("string decoded as %v", w)
+	}
+}
+
+func TestString(t *testing.T) {
+	for v, want := range map[interface{ String() string }]string{
+		types.Buffer(1):  "Buffer:1",
+		types.Window(2):  "Window:2",
+		types.Tabpage(3): "Tabpage:3",
+	} {
+		if got := v.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
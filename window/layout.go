@@ -0,0 +1,149 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Kind is the kind of a Node of a layout.
+type Kind int
+
+// List of node kinds.
+const (
+	Leaf Kind = iota // a window
+	Row              // windows side by side, split vertically
+	Col              // windows stacked, split horizontally
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Leaf:
+		return "leaf"
+	case Row:
+		return "row"
+	case Col:
+		return "col"
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Node is a node of the layout of the windows of a tab page.
+type Node struct {
+	Kind     Kind
+	Window   types.Window // for a Leaf
+	Children []*Node      // for a Row or Col, from left or top
+}
+
+// Layout returns the layout of the windows of tab, or of the current tab
+// page if tab is 0, as reported by winlayout(). Floating windows are not
+// part of it.
+func Layout(ctx context.Context, v *nvim.Nvim, tab types.Tabpage) (*Node, error) {
+	args := []interface{}{}
+	if tab != 0 {
+		nr, err := v.TabpageGetNumber(ctx, tab)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, nr)
+	}
+
+	var layout interface{}
+	if err := v.Call(ctx, "nvim_call_function", &layout, "winlayout", args); err != nil {
+		return nil, err
+	}
+
+	return parseLayout(layout)
+}
+
+// parseLayout parses a layout as returned by winlayout(): ["leaf", winid],
+// ["row", children] or ["col", children].
+func parseLayout(layout interface{}) (*Node, error) {
+	l, ok := layout.([]interface{})
+	if !ok || len(l) != 2 {
+		return nil, fmt.Errorf("window: invalid layout %v", layout)
+	}
+	kind, _ := l[0].(string)
+
+	switch kind {
+	case "leaf":
+		id, ok := l[1].(int64)
+		if !ok {
+			return nil, fmt.Errorf("window: invalid window %v in layout", l[1])
+		}
+		return &Node{Kind: Leaf, Window: types.Window(id)}, nil
+
+	case "row", "col":
+		children, ok := l[1].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("window: invalid %s %v in layout", kind, l[1])
+		}
+		n := &Node{Kind: Row}
+		if kind == "col" {
+			n.Kind = Col
+		}
+		for _, c := range children {
+			child, err := parseLayout(c)
+			if err != nil {
+				return nil, err
+			}
+			n.Children = append(n.Children, child)
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("window: invalid layout node %v", l[0])
+}
+
+// Windows returns the windows of the layout, from left to right and top to
+// bottom.
+func (n *Node) Windows() []types.Window {
+	var wins []types.Window
+	n.Walk(func(n *Node, _ []*Node) bool {
+		if n.Kind == Leaf {
+			wins = append(wins, n.Window)
+		}
+		return true
+	})
+
+	return wins
+}
+
+// Walk calls fn for each node of the layout in depth-first order, with
+// the ancestors of the node from the root. The children of a node are
+// skipped if fn returns false.
+func (n *Node) Walk(fn func(n *Node, parents []*Node) bool) {
+	n.walk(fn, nil)
+}
+
+func (n *Node) walk(fn func(*Node, []*Node) bool, parents []*Node) {
+	if !fn(n, parents) {
+		return
+	}
+	parents = append(parents, n)
+	for _, c := range n.Children {
+		c.walk(fn, parents[:len(parents):len(parents)])
+	}
+}
+
+// Find returns the leaf of win and its ancestors from the root, or nil if
+// win is not in the layout.
+func (n *Node) Find(win types.Window) (leaf *Node, parents []*Node) {
+	n.Walk(func(c *Node, p []*Node) bool {
+		if leaf != nil {
+			return false
+		}
+		if c.Kind == Leaf && c.Window == win {
+			leaf, parents = c, p
+		}
+		return true
+	})
+
+	return leaf, parents
+}
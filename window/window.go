@@ -0,0 +1,165 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package window manages Neovim windows and queries their layout.
+package window
+
+import (
+	"context"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Window is a Neovim window.
+//
+// Cursor lines are one-based and columns are zero-based byte offsets in
+// the line, as in nvim_win_get_cursor.
+type Window struct {
+	v   *nvim.Nvim
+	win types.Window
+}
+
+// New returns the Window win of v. Window 0 is the current window at the
+// time of each call.
+func New(v *nvim.Nvim, win types.Window) *Window {
+	return &Window{v: v, win: win}
+}
+
+// Current returns the current window.
+func Current(ctx context.Context, v *nvim.Nvim) (*Window, error) {
+	win, err := v.GetCurrentWin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(v, win), nil
+}
+
+// Handle returns the handle of the window.
+func (w *Window) Handle() types.Window {
+	return w.win
+}
+
+func (w *Window) String() string {
+	return w.win.String()
+}
+
+// IsValid reports whether the window exists.
+func (w *Window) IsValid(ctx context.Context) (bool, error) {
+	return w.v.WinIsValid(ctx, w.win)
+}
+
+// Buffer returns the buffer displayed in the window.
+func (w *Window) Buffer(ctx context.Context) (types.Buffer, error) {
+	return w.v.WinGetBuf(ctx, w.win)
+}
+
+// SetBuffer displays buf in the window.
+func (w *Window) SetBuffer(ctx context.Context, buf types.Buffer) error {
+	return w.v.WinSetBuf(ctx, w.win, buf)
+}
+
+// Tabpage returns the tab page of the window.
+func (w *Window) Tabpage(ctx context.Context) (types.Tabpage, error) {
+	return w.v.WinGetTabpage(ctx, w.win)
+}
+
+// Cursor returns the line and column of the cursor.
+func (w *Window) Cursor(ctx context.Context) (line, col int, err error) {
+	pos, err := w.v.WinGetCursor(ctx, w.win)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return pos[0], pos[1], nil
+}
+
+// SetCursor moves the cursor to line and col, scrolling the window to show
+// it. A line out of the buffer is an error.
+func (w *Window) SetCursor(ctx context.Context, line, col int) error {
+	return w.v.WinSetCursor(ctx, w.win, [2]int{line, col})
+}
+
+// Width returns the width of the window, in cells.
+func (w *Window) Width(ctx context.Context) (int, error) {
+	return w.v.WinGetWidth(ctx, w.win)
+}
+
+// SetWidth sets the width of the window, in cells.
+func (w *Window) SetWidth(ctx context.Context, width int) error {
+	return w.v.WinSetWidth(ctx, w.win, width)
+}
+
+// Height returns the height of the window, in lines.
+func (w *Window) Height(ctx context.Context) (int, error) {
+	return w.v.WinGetHeight(ctx, w.win)
+}
+
+// SetHeight sets the height of the window, in lines.
+func (w *Window) SetHeight(ctx context.Context, height int) error {
+	return w.v.WinSetHeight(ctx, w.win, height)
+}
+
+// Position returns the screen row and column of the top left corner of the
+// window, zero-based.
+func (w *Window) Position(ctx context.Context) (row, col int, err error) {
+	pos, err := w.v.WinGetPosition(ctx, w.win)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return pos[0], pos[1], nil
+}
+
+// Option returns the value of the window-local option name.
+func (w *Window) Option(ctx context.Context, name string) (interface{}, error) {
+	return w.v.GetOptionValue(ctx, name, map[string]interface{}{"win": w.win})
+}
+
+// SetOption sets the window-local option name to value.
+func (w *Window) SetOption(ctx context.Context, name string, value interface{}) error {
+	return w.v.SetOptionValue(ctx, name, value, map[string]interface{}{"win": w.win})
+}
+
+// Var stores the value of the window variable name in the value pointed to
+// by result.
+func (w *Window) Var(ctx context.Context, name string, result interface{}) error {
+	return w.v.Call(ctx, "nvim_win_get_var", result, w.win, name)
+}
+
+// SetVar sets the window variable name to value.
+func (w *Window) SetVar(ctx context.Context, name string, value interface{}) error {
+	return w.v.WinSetVar(ctx, w.win, name, value)
+}
+
+// callLua runs the Lua chunk code with args in the window with
+// nvim_win_call.
+const callLua = `
+local win, code, args = ...
+local fn = assert(loadstring(code))
+return vim.api.nvim_win_call(win, function() return fn(unpack(args)) end)
+`
+
+// Call runs the Lua chunk code with args in the context of the window, as
+// if it were the current window, and stores its result in the value pointed
+// to by result. A nil result discards it.
+func (w *Window) Call(ctx context.Context, code string, result interface{}, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	return w.v.Call(ctx, "nvim_exec_lua", result, callLua, []interface{}{w.win, code, args})
+}
+
+// Command executes the Ex command cmd in the context of the window.
+func (w *Window) Command(ctx context.Context, cmd string) error {
+	return w.Call(ctx, "vim.cmd(...)", nil, cmd)
+}
+
+// Close closes the window, as :close. Changes of its buffer are discarded
+// if force is true, and are otherwise an error when it is the last window
+// of the buffer and 'hidden' is off.
+func (w *Window) Close(ctx context.Context, force bool) error {
+	return w.v.WinClose(ctx, w.win, force)
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package winguard pins buffers to windows, such as tool and output
+// panels, so that opening another buffer in them opens it in a normal
+// window instead.
+package winguard
+
+import (
+	"context"
+
+	"github.com/go-nvim/pkg/nvim"
+	"github.com/go-nvim/pkg/runtime/autocmd"
+	"github.com/go-nvim/pkg/types"
+)
+
+// Var is the window-local variable of guarded windows. Its value is the
+// number of the buffer the window is pinned to.
+const Var = "winguard"
+
+// Group is the autocmd group of the Guard.
+const Group = "winguard"
+
+// guardLua defines the function rerouting the buffers entering a guarded
+// window. It runs in BufWinEnter, and defers the change of buffers until
+// the autocmd has returned.
+const guardLua = `
+_G.__go_nvim_winguard = function()
+  local win = vim.api.nvim_get_current_win()
+  local pinned = vim.w[win].winguard
+  local buf = vim.api.nvim_get_current_buf()
+  if not pinned or pinned == buf or not vim.api.nvim_buf_is_valid(pinned) then
+    return
+  end
+  vim.schedule(function()
+    if not vim.api.nvim_win_is_valid(win) or not vim.api.nvim_buf_is_valid(buf) then
+      return
+    end
+    local target
+    for _, w in ipairs(vim.api.nvim_tabpage_list_wins(vim.api.nvim_win_get_tabpage(win))) do
+      if w ~= win and not vim.w[w].winguard and vim.api.nvim_win_get_config(w).relative == ''
+          and vim.bo[vim.api.nvim_win_get_buf(w)].buftype == '' then
+        target = w
+        break
+      end
+    end
+    vim.api.nvim_win_set_buf(win, pinned)
+    if not target then
+      vim.api.nvim_set_current_win(win)
+      vim.cmd('botright vsplit')
+      target = vim.api.nvim_get_current_win()
+      vim.w[target].winguard = nil
+    end
+    vim.api.nvim_win_set_buf(target, buf)
+    vim.api.nvim_set_current_win(target)
+  end)
+end
+`
+
+// Guard reroutes the buffers entering pinned windows.
+type Guard struct {
+	v *nvim.Nvim
+}
+
+// Enable starts rerouting the buffers entering the windows pinned with
+// Pin, to the first normal window of the tab page, or to a new window if
+// there is none. A normal window is not pinned, not floating, and shows a
+// buffer with an empty 'buftype'.
+//
+// The autocmd of the Guard is created in Group, which is cleared first, so
+// enabling it again replaces the previous Guard.
+func Enable(ctx context.Context, v *nvim.Nvim) (*Guard, error) {
+	if _, err := v.ExecLua(ctx, guardLua, []interface{}{}); err != nil {
+		return nil, err
+	}
+	if _, err := autocmd.CreateGroup(ctx, v, Group, true); err != nil {
+		return nil, err
+	}
+	_, err := autocmd.Register(autocmd.BufWinEnter).
+		Pattern("*").
+		Group(Group).
+		Desc("winguard: reroute buffers entering pinned windows").
+		Command("lua _G.__go_nvim_winguard()").
+		Create(ctx, v)
+	if err != nil {
+		_ = autocmd.DeleteGroup(ctx, v, Group)
+		return nil, err
+	}
+
+	return &Guard{v: v}, nil
+}
+
+// Close stops rerouting buffers, deleting Group. Windows stay pinned.
+func (g *Guard) Close(ctx context.Context) error {
+	return autocmd.DeleteGroup(ctx, g.v, Group)
+}
+
+// Pin pins the buffer shown in win to it.
+func Pin(ctx context.Context, v *nvim.Nvim, win types.Window) error {
+	buf, err := v.WinGetBuf(ctx, win)
+	if err != nil {
+		return err
+	}
+
+	return v.WinSetVar(ctx, win, Var, int(buf))
+}
+
+// Unpin lets win show any buffer again.
+func Unpin(ctx context.Context, v *nvim.Nvim, win types.Window) error {
+	_, err := v.ExecLua(ctx, "vim.w[...].winguard = nil", []interface{}{win})
+
+	return err
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 The Go Nvim Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package winguard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-nvim/pkg/nvim/nvimtest"
+	"github.com/go-nvim/pkg/winguard"
+)
+
+func TestEnable(t *testing.T) {
+	s, v := nvimtest.NewMockServer(t)
+	s.Return("nvim_exec_lua", nil)
+	s.Return("nvim_create_augroup", 5)
+	s.Return("nvim_create_autocmd", 7)
+	s.Return("nvim_get_autocmds", []interface{}{})
+	s.Return("nvim_clear_autocmds", nil)
+	s.Return("nvim_del_augroup_by_name", nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := winguard.Enable(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.ExpectSequence(t, "nvim_create_augroup", "nvim_create_autocmd", "nvim_create_augroup", "nvim_create_autocmd")
+	s.ExpectCall(t, "nvim_create_augroup", winguard.Group, map[string]interface{}{"clear": true})
+
+	g, err := winguard.Enable(ctx, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Reset()
+	if err := g.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpectCall(t, "nvim_del_augroup_by_name", winguard.Group)
+}